@@ -75,5 +75,9 @@ func isAttemptDelayedBy(lastAt time.Time, hours int) bool {
 }
 
 func (campaign *VerificationAttemptCampaign) RunPostProcess(dbClient *db.Client, recipient Recipient) error {
-	return dbClient.RecordVerificationAttempt(recipient.User.ID)
+	period, err := strconv.Atoi(getEnv("VERIFICATION_PERIOD", "7"))
+	if err != nil {
+		return err
+	}
+	return dbClient.RecordVerificationAttempt(recipient.User.ID, time.Now().AddDate(0, 0, -period))
 }
@@ -23,18 +23,6 @@ var allSteps = [...]db.UserJourneyStep{
 	db.JourneyStepReceiveFiveAgrees,
 }
 
-var requiredSteps = [...]db.UserJourneyStep{
-	db.JourneyStepSignedUp,
-	db.JourneyStepOneArgument,
-	db.JourneyStepReceiveFiveAgrees,
-}
-
-var rewardForStep = map[db.UserJourneyStep]string{
-	db.JourneyStepSignedUp:          mustEnv("REWARD_STEP_SIGNUP"),
-	db.JourneyStepOneArgument:       mustEnv("REWARD_STEP_ONE_ARGUMENT"),
-	db.JourneyStepReceiveFiveAgrees: mustEnv("REWARD_STEP_FIVE_AGREES"),
-}
-
 type usersflag []int64
 
 func (u *usersflag) String() string {
@@ -72,7 +60,8 @@ func main() {
 	}
 	dbClient := db.NewDBClient(config)
 
-	inviteBatchSize, err := strconv.Atoi(mustEnv("INVITE_BATCH_SIZE"))
+	// the schedule comes from the API, which also uses it to preview pending rewards
+	schedule, err := getRewardSchedule()
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -117,8 +106,8 @@ func main() {
 			// award the first set of invites
 			fmt.Printf("has become eligible for invites. ✅\n")
 
-			fmt.Printf("\tGranting %d new invites... ", inviteBatchSize)
-			err = dbClient.GrantInvites(user.ID, inviteBatchSize)
+			fmt.Printf("\tGranting %d new invites... ", schedule.InviteBatchSize)
+			err = dbClient.GrantInvites(user.ID, schedule.InviteBatchSize)
 			if err != nil {
 				log.Fatalln(err)
 			}
@@ -126,7 +115,7 @@ func main() {
 			sendNotification(app.RewardNotificationRequest{
 				RewardeeID:   user.ID,
 				RewardType:   app.RewardTypeInvite,
-				RewardAmount: strconv.Itoa(inviteBatchSize),
+				RewardAmount: strconv.Itoa(schedule.InviteBatchSize),
 			})
 		}
 
@@ -149,15 +138,15 @@ func main() {
 
 		// if the user has become eligible, reward more invites to the inviting user
 		if eligible {
-			fmt.Printf("\tWas referred by %s. Granting them %d invites as well...", referrer.Username, inviteBatchSize)
-			err = dbClient.GrantInvites(referrer.ID, inviteBatchSize)
+			fmt.Printf("\tWas referred by %s. Granting them %d invites as well...", referrer.Username, schedule.InviteBatchSize)
+			err = dbClient.GrantInvites(referrer.ID, schedule.InviteBatchSize)
 			if err != nil {
 				log.Fatalln(err)
 			}
 			sendNotification(app.RewardNotificationRequest{
 				RewardeeID:   referrer.ID,
 				RewardType:   app.RewardTypeInvite,
-				RewardAmount: strconv.Itoa(inviteBatchSize),
+				RewardAmount: strconv.Itoa(schedule.InviteBatchSize),
 				CauserID:     user.ID,
 				CauserAction: app.RewardCauserActionJourneyComplete,
 			})
@@ -165,7 +154,7 @@ func main() {
 		}
 
 		for _, step := range additionalStepsCompleted {
-			reward, exists := rewardForStep[step]
+			reward, exists := schedule.RewardForStep(step)
 			if !exists {
 				// if no reward is present for this step,
 				// move on to the next one...
@@ -227,17 +216,41 @@ func getCurrentJourney(user db.User) (journey []db.UserJourneyStep, err error) {
 	return
 }
 
+func getRewardSchedule() (schedule app.RewardSchedule, err error) {
+	response, err := makeHTTPRequest(http.MethodGet, mustEnv("ENDPOINT_REWARD_SCHEDULE"), nil)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode != 200 {
+		return schedule, fmt.Errorf("Fetching reward schedule failed")
+	}
+
+	var rewardSchedule RewardScheduleResponse
+	err = json.NewDecoder(response.Body).Decode(&rewardSchedule)
+	if err != nil {
+		return
+	}
+	// an incomplete schedule would grant nothing without failing
+	err = rewardSchedule.Data.Validate()
+	if err != nil {
+		return schedule, err
+	}
+
+	return rewardSchedule.Data, nil
+}
+
 func userHasBecomeEligible(previous, current []db.UserJourneyStep) bool {
 	previouslyEligible := true
 	currentlyEligble := true
-	for _, step := range requiredSteps {
+	for _, step := range app.RequiredJourneySteps {
 		// if any step is not completed, the user is not eligible
 		if !containsStep(previous, step) {
 			previouslyEligible = false
 		}
 	}
 
-	for _, step := range requiredSteps {
+	for _, step := range app.RequiredJourneySteps {
 		// if any step is not completed, the user is not eligible
 		if !containsStep(current, step) {
 			currentlyEligble = false
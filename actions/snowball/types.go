@@ -12,6 +12,11 @@ type UserJourneyResponse struct {
 	Data   truapi.UserJourneyResponse `json:"data"`
 }
 
+type RewardScheduleResponse struct {
+	Status int                   `json:"status"`
+	Data   truapi.RewardSchedule `json:"data"`
+}
+
 func mustEnv(env string) string {
 	val := os.Getenv(env)
 	if val == "" {
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("adding has_media column to claim_images...")
+		_, err := db.Exec(`ALTER TABLE claim_images ADD COLUMN has_media BOOLEAN NOT NULL DEFAULT FALSE`)
+		if err != nil {
+			return err
+		}
+		_, err = db.Exec(`UPDATE claim_images SET has_media = TRUE
+			WHERE (claim_image_url <> '' AND claim_image_url NOT LIKE '%claimImage_default_%')
+			OR (claim_video_url IS NOT NULL AND claim_video_url <> '')`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("removing has_media column from claim_images...")
+		_, err := db.Exec(`ALTER TABLE claim_images DROP COLUMN has_media`)
+		return err
+	})
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("creating claim_tags table...")
+		_, err := db.Exec(`CREATE TABLE claim_tags(
+			id BIGSERIAL PRIMARY KEY,
+			claim_id BIGINT NOT NULL,
+			tag VARCHAR (64) NOT NULL,
+			created_by VARCHAR (45) NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW(),
+			deleted_at TIMESTAMP,
+			CONSTRAINT claim_tags_no_duplicate_tag UNIQUE (claim_id, tag)
+		)`)
+		if err != nil {
+			return err
+		}
+		_, err = db.Exec(`CREATE INDEX claim_tags_tag_idx ON claim_tags (tag)`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("dropping claim_tags table...")
+		_, err := db.Exec(`DROP TABLE claim_tags`)
+		return err
+	})
+}
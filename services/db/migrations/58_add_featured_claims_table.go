@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("creating featured_claims table...")
+		_, err := db.Exec(`CREATE TABLE featured_claims(
+			id BIGSERIAL PRIMARY KEY,
+			claim_id BIGINT NOT NULL,
+			community_id TEXT NOT NULL,
+			starts_at TIMESTAMP NOT NULL,
+			ends_at TIMESTAMP NOT NULL,
+			set_by VARCHAR (45) NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW(),
+			deleted_at TIMESTAMP
+		)`)
+		if err != nil {
+			return err
+		}
+		_, err = db.Exec(`CREATE INDEX featured_claims_community_id_ends_at_idx ON featured_claims (community_id, ends_at)`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("dropping featured_claims table...")
+		_, err := db.Exec(`DROP TABLE featured_claims`)
+		return err
+	})
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("dropping unique email and username constraints on the users table...")
+		_, err := db.Exec(`
+			ALTER TABLE users DROP CONSTRAINT users_email_key;
+			ALTER TABLE users DROP CONSTRAINT users_username_key;
+			DROP INDEX users_lower_case_usernames;
+		`)
+		if err != nil {
+			return err
+		}
+		fmt.Println("unique indexes on email and username of non-deleted users...")
+		_, err = db.Exec(`
+			CREATE UNIQUE INDEX users_active_emails ON users ((lower(email))) WHERE deleted_at IS NULL;
+			CREATE UNIQUE INDEX users_active_usernames ON users ((lower(username))) WHERE deleted_at IS NULL;
+		`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("restoring unique email and username constraints on the users table...")
+		_, err := db.Exec(`
+			DROP INDEX users_active_emails;
+			DROP INDEX users_active_usernames;
+			ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+			ALTER TABLE users ADD CONSTRAINT users_username_key UNIQUE (username);
+			CREATE UNIQUE INDEX users_lower_case_usernames ON users ((lower(username)));
+		`)
+		return err
+	})
+}
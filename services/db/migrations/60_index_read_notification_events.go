@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("indexing timestamp of read notifications on notification_events table...")
+		_, err := db.Exec(`CREATE INDEX idx_read_timestamp_on_notification_events ON notification_events(timestamp) WHERE read IS TRUE`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("drop index on timestamp of read notifications from notification_events table...")
+		_, err := db.Exec(`DROP INDEX idx_read_timestamp_on_notification_events`)
+		return err
+	})
+}
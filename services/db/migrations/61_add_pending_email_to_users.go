@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("adding pending email columns to the users table...")
+		_, err := db.Exec(`ALTER TABLE users ADD COLUMN pending_email VARCHAR(128) DEFAULT NULL, ADD COLUMN pending_email_token TEXT DEFAULT NULL`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("dropping pending email columns from the users table...")
+		_, err := db.Exec(`ALTER TABLE users DROP COLUMN pending_email, DROP COLUMN pending_email_token`)
+		return err
+	})
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("adding canonical email column to the users table...")
+		_, err := db.Exec(`ALTER TABLE users ADD COLUMN canonical_email VARCHAR(128) DEFAULT NULL`)
+		if err != nil {
+			return err
+		}
+		// lowercased, without +suffix, and without dots for gmail addresses
+		_, err = db.Exec(`
+			UPDATE users SET canonical_email = CASE
+				WHEN split_part(LOWER(email), '@', 2) IN ('gmail.com', 'googlemail.com')
+					THEN replace(split_part(split_part(LOWER(email), '@', 1), '+', 1), '.', '') || '@gmail.com'
+				ELSE split_part(split_part(LOWER(email), '@', 1), '+', 1) || '@' || split_part(LOWER(email), '@', 2)
+			END
+			WHERE email IS NOT NULL AND email LIKE '_%@%'
+		`)
+		if err != nil {
+			return err
+		}
+		_, err = db.Exec(`CREATE INDEX users_canonical_email ON users (canonical_email) WHERE deleted_at IS NULL`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("dropping canonical email column from the users table...")
+		_, err := db.Exec(`ALTER TABLE users DROP COLUMN canonical_email`)
+		return err
+	})
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("creating claim_comments_views table...")
+		_, err := db.Exec(`CREATE TABLE claim_comments_views(
+			id BIGSERIAL PRIMARY KEY,
+			address VARCHAR (45) NOT NULL,
+			claim_id BIGINT NOT NULL,
+			last_seen_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW(),
+			deleted_at TIMESTAMP,
+			CONSTRAINT claim_comments_views_one_per_user UNIQUE (address, claim_id)
+		)`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("dropping claim_comments_views table...")
+		_, err := db.Exec(`DROP TABLE claim_comments_views`)
+		return err
+	})
+}
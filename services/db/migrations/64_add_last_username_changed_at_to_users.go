@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("adding last_username_changed_at column to the users table...")
+		_, err := db.Exec(`ALTER TABLE users ADD COLUMN last_username_changed_at TIMESTAMP DEFAULT NULL`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("dropping last_username_changed_at column from the users table...")
+		_, err := db.Exec(`ALTER TABLE users DROP COLUMN last_username_changed_at`)
+		return err
+	})
+}
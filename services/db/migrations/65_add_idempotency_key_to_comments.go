@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("adding idempotency_key column to the comments table...")
+		_, err := db.Exec(`ALTER TABLE comments ADD COLUMN idempotency_key VARCHAR(255) DEFAULT NULL`)
+		if err != nil {
+			return err
+		}
+		_, err = db.Exec(`CREATE UNIQUE INDEX idx_creator_idempotency_key_on_comments ON comments(creator, idempotency_key) WHERE idempotency_key IS NOT NULL AND deleted_at IS NULL`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("dropping idempotency_key column from the comments table...")
+		_, err := db.Exec(`ALTER TABLE comments DROP COLUMN idempotency_key`)
+		return err
+	})
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("creating table claim_search_documents...")
+		_, err := db.Exec(`CREATE TABLE IF NOT EXISTS claim_search_documents (
+			id BIGSERIAL PRIMARY KEY,
+			claim_id BIGINT NOT NULL UNIQUE,
+			community_id TEXT NOT NULL,
+			body TEXT NOT NULL,
+			document TSVECTOR NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			deleted_at TIMESTAMP
+		)`)
+		if err != nil {
+			return err
+		}
+		_, err = db.Exec(`CREATE INDEX idx_document_on_claim_search_documents ON claim_search_documents USING GIN(document)`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("dropping table claim_search_documents...")
+		_, err := db.Exec(`DROP TABLE IF EXISTS claim_search_documents`)
+		return err
+	})
+}
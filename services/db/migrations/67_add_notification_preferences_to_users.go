@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("adding notification_preferences column to the users table...")
+		_, err := db.Exec(`ALTER TABLE users ADD COLUMN notification_preferences jsonb NOT NULL DEFAULT '{}'`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("dropping notification_preferences column from the users table...")
+		_, err := db.Exec(`ALTER TABLE users DROP COLUMN notification_preferences`)
+		return err
+	})
+}
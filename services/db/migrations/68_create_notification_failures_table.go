@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("creating notification_failures table...")
+		_, err := db.Exec(`CREATE TABLE IF NOT EXISTS notification_failures (
+			id BIGSERIAL PRIMARY KEY,
+			address VARCHAR(45) NOT NULL,
+			type INTEGER NOT NULL,
+			platform TEXT NOT NULL DEFAULT '',
+			reason TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			deleted_at TIMESTAMP
+		)`)
+		if err != nil {
+			return err
+		}
+		_, err = db.Exec(`CREATE INDEX idx_created_at_on_notification_failures ON notification_failures(created_at)`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("dropping notification_failures table...")
+		_, err := db.Exec(`DROP TABLE IF EXISTS notification_failures`)
+		return err
+	})
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-pg/migrations"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		fmt.Println("adding anonymized_profile and sessions_revoked_at columns to the users table...")
+		_, err := db.Exec(`ALTER TABLE users
+			ADD COLUMN anonymized_profile JSONB DEFAULT NULL,
+			ADD COLUMN sessions_revoked_at TIMESTAMP DEFAULT NULL`)
+		return err
+	}, func(db migrations.DB) error {
+		fmt.Println("dropping anonymized_profile and sessions_revoked_at columns from the users table...")
+		_, err := db.Exec(`ALTER TABLE users DROP COLUMN anonymized_profile, DROP COLUMN sessions_revoked_at`)
+		return err
+	})
+}
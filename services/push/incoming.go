@@ -13,11 +13,13 @@ func (s *service) startHTTPServer(
 	commentNotifications chan<- *CommentNotificationRequest,
 	rewardNotifications chan<- *app.RewardNotificationRequest,
 	broadcastNotifications chan<- *app.BroadcastNotificationRequest,
+	reactionNotifications chan<- *app.ReactionNotificationRequest,
 ) {
 	mux := http.NewServeMux()
 	s.addHTTPCommentNotificationHandler(mux, commentNotifications)
 	s.addHTTPRewardNotificationHandler(mux, rewardNotifications)
 	s.addHTTPBroadcastNotificationHandler(mux, broadcastNotifications)
+	s.addHTTPReactionNotificationHandler(mux, reactionNotifications)
 	server := &http.Server{
 		Addr:    ":9001",
 		Handler: mux,
@@ -89,3 +91,22 @@ func (s *service) addHTTPBroadcastNotificationHandler(mux *http.ServeMux, notifi
 		w.WriteHeader(http.StatusAccepted)
 	})
 }
+
+func (s *service) addHTTPReactionNotificationHandler(mux *http.ServeMux, notifications chan<- *app.ReactionNotificationRequest) {
+	mux.HandleFunc("/sendReactionNotification", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			fmt.Printf("only POST method allowed received [%s]\n", r.Method)
+			return
+		}
+		n := &app.ReactionNotificationRequest{}
+		err := json.NewDecoder(r.Body).Decode(n)
+		if err != nil {
+			s.log.WithError(err).Error("error decoding request")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.log.WithField("reactionId", n.ID).Info("reaction notification request received")
+		notifications <- n
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -115,99 +116,136 @@ func (s *service) notificationSender(notifications <-chan *Notification, stop <-
 	for {
 		select {
 		case notification := <-notifications:
-			msg := notification.Msg
-			title := notification.Type.String()
-			receiver, err := s.db.UserByAddress(notification.To)
-			if err != nil {
-				s.log.WithError(err).Errorf("could not retrieve user for address %s", notification.To)
-				continue
-			}
-			if receiver == nil {
-				s.log.Warnf("profile doesn't exist for  %s", notification.To)
-				continue
-			}
-			if notification.Trim && len(msg) > BodyMaxLength {
-				msg = fmt.Sprintf("%s...", msg[:BodyMaxLength-3])
-			}
-			notificationEvent := &db.NotificationEvent{
-				Address:       notification.To,
-				UserProfileID: receiver.ID,
-				Read:          false,
-				Timestamp:     time.Now(),
-				Message:       msg,
-				Type:          notification.Type,
-				TypeID:        notification.TypeID,
-			}
+			_ = s.send(notification)
+		case <-stop:
+			s.log.Info("stopping notification sender")
+			return
+		}
+	}
+}
 
-			notificationEvent.Meta = notification.Meta
-			var senderImage, senderAddress *string
-			if notification.From != nil {
-				sender, err := s.db.UserByAddress(*notification.From)
-				if err != nil {
-					s.log.WithError(err).Errorf("could not retrieve user for address %s", *notification.From)
-					continue
-				}
-				notificationEvent.SenderProfileID = sender.ID
-				title = sender.Username
-				senderImage = strPtr(sender.AvatarURL)
-				senderAddress = strPtr(sender.Address)
-			}
-			_, err = s.db.Model(notificationEvent).Returning("*").Insert()
-			if err != nil {
-				s.log.WithError(err).Error("error saving event in database")
-			}
-			receiverAddress := notification.To
-			deviceTokens, err := s.db.DeviceTokensByAddress(receiverAddress)
-			if err != nil {
-				s.log.WithError(err).Error("error retrieving tokens from db")
-				continue
-			}
-			if len(deviceTokens) == 0 {
-				s.log.Infof("account address %s doesn't not have push notification tokens \n", receiverAddress)
-				continue
-			}
-			tokens := make(map[string][]string)
-			for _, deviceToken := range deviceTokens {
-				currentTokens := tokens[deviceToken.Platform]
-				tokens[deviceToken.Platform] = append(currentTokens, deviceToken.Token)
-			}
+// send saves the notification event of the receiver and pushes it to their devices
+func (s *service) send(notification *Notification) error {
+	msg := notification.Msg
+	title := notification.Type.String()
+	receiver, err := s.db.UserByAddress(notification.To)
+	if err != nil {
+		s.log.WithError(err).Errorf("could not retrieve user for address %s", notification.To)
+		return err
+	}
+	if receiver == nil {
+		s.log.Warnf("profile doesn't exist for  %s", notification.To)
+		return fmt.Errorf("profile doesn't exist for %s", notification.To)
+	}
+	if notification.Trim && len(msg) > BodyMaxLength {
+		msg = fmt.Sprintf("%s...", msg[:BodyMaxLength-3])
+	}
+	notificationEvent := &db.NotificationEvent{
+		Address:       notification.To,
+		UserProfileID: receiver.ID,
+		Read:          false,
+		Timestamp:     time.Now(),
+		Message:       msg,
+		Type:          notification.Type,
+		TypeID:        notification.TypeID,
+	}
 
-			pushNotification := PushNotification{
-				Title: title,
-				Body:  stripmd.Strip(msg),
-				NotificationData: NotificationData{
-					Title:     title,
-					ID:        notificationEvent.ID,
-					TypeID:    notification.TypeID,
-					Timestamp: notificationEvent.Timestamp,
-					UserID:    senderAddress,
-					Image:     senderImage,
-					Read:      notificationEvent.Read,
-					Type:      notificationEvent.Type,
-					Meta:      notificationEvent.Meta,
-				},
-			}
+	notificationEvent.Meta = notification.Meta
+	var senderImage, senderAddress *string
+	if notification.From != nil {
+		sender, err := s.db.UserByAddress(*notification.From)
+		if err != nil {
+			s.log.WithError(err).Errorf("could not retrieve user for address %s", *notification.From)
+			return err
+		}
+		notificationEvent.SenderProfileID = sender.ID
+		title = sender.Username
+		senderImage = strPtr(sender.AvatarURL)
+		senderAddress = strPtr(sender.Address)
+	}
+	_, err = s.db.Model(notificationEvent).Returning("*").Insert()
+	if err != nil {
+		s.log.WithError(err).Error("error saving event in database")
+	}
+	receiverAddress := notification.To
+	deviceTokens, err := s.db.DeviceTokensByAddress(receiverAddress)
+	if err != nil {
+		s.log.WithError(err).Error("error retrieving tokens from db")
+		return err
+	}
+	if len(deviceTokens) == 0 {
+		s.log.Infof("account address %s doesn't not have push notification tokens \n", receiverAddress)
+		return nil
+	}
+	tokens := make(map[string][]string)
+	for _, deviceToken := range deviceTokens {
+		currentTokens := tokens[deviceToken.Platform]
+		tokens[deviceToken.Platform] = append(currentTokens, deviceToken.Token)
+	}
+
+	pushNotification := PushNotification{
+		Title: title,
+		Body:  stripmd.Strip(msg),
+		NotificationData: NotificationData{
+			Title:     title,
+			ID:        notificationEvent.ID,
+			TypeID:    notification.TypeID,
+			Timestamp: notificationEvent.Timestamp,
+			UserID:    senderAddress,
+			Image:     senderImage,
+			Read:      notificationEvent.Read,
+			Type:      notificationEvent.Type,
+			Meta:      notificationEvent.Meta,
+		},
+	}
+
+	if notification.Action != "" {
+		pushNotification.Subtitle = notification.Action
+		pushNotification.NotificationData.Subtitle = notification.Action
+	}
+	return s.deliver(receiverAddress, notification.Type, pushNotification, tokens)
+}
 
-			if notification.Action != "" {
-				pushNotification.Subtitle = notification.Action
-				pushNotification.NotificationData.Subtitle = notification.Action
+// deliver sends the push notification to the devices of the receiver on every platform,
+// recording the deliveries that fail. It returns the first failure, after trying every platform.
+func (s *service) deliver(address string, notificationType db.NotificationType, pushNotification PushNotification, tokens map[string][]string) error {
+	var failure error
+	for p, t := range tokens {
+		pushNotification.Platform = p
+		r, err := s.sendNotification(pushNotification, t)
+		if err != nil {
+			s.log.WithError(err).Error("error sending notifications")
+			s.recordFailure(address, notificationType, p, err.Error())
+			if failure == nil {
+				failure = err
 			}
-			for p, t := range tokens {
-				pushNotification.Platform = p
-				r, err := s.sendNotification(pushNotification, t)
-				if err != nil {
-					s.log.WithError(err).Error("error sending notifications")
-					continue
-				}
-				if r != nil {
-					s.log.Infof("notifications sent - status : %s count : %d", r.Success, r.Counts)
+			continue
+		}
+		if r != nil {
+			s.log.Infof("notifications sent - status : %s count : %d", r.Success, r.Counts)
+			for _, entry := range r.Logs {
+				if entry.Error != "" {
+					s.recordFailure(address, notificationType, p, entry.Error)
+					if failure == nil {
+						failure = errors.New(entry.Error)
+					}
 				}
 			}
-		case <-stop:
-			s.log.Info("stopping notification sender")
-			return
 		}
 	}
+	return failure
+}
+
+func (s *service) recordFailure(address string, notificationType db.NotificationType, platform, reason string) {
+	err := s.failures.RecordNotificationFailure(&db.NotificationFailure{
+		Address:  address,
+		Type:     notificationType,
+		Platform: platform,
+		Reason:   reason,
+	})
+	if err != nil {
+		s.log.WithError(err).Error("error recording notification failure")
+	}
 }
 
 func getEnv(env, defaultValue string) string {
@@ -297,10 +335,12 @@ func (s *service) run(stop <-chan struct{}) {
 	cNotificationsCh := make(chan *CommentNotificationRequest)
 	rNotificationsCh := make(chan *app.RewardNotificationRequest)
 	bNotificationsCh := make(chan *app.BroadcastNotificationRequest)
-	go s.startHTTPServer(stop, cNotificationsCh, rNotificationsCh, bNotificationsCh)
+	reactionNotificationsCh := make(chan *app.ReactionNotificationRequest)
+	go s.startHTTPServer(stop, cNotificationsCh, rNotificationsCh, bNotificationsCh, reactionNotificationsCh)
 	go s.processCommentsNotifications(cNotificationsCh, notificationsCh)
 	go s.processRewardsNotifications(rNotificationsCh, notificationsCh)
-	go s.processBroadcastNotifications(bNotificationsCh, notificationsCh)
+	go s.processBroadcastNotifications(bNotificationsCh)
+	go s.processReactionsNotifications(reactionNotificationsCh, notificationsCh)
 	go s.notificationSender(notificationsCh, stop)
 	for {
 		select {
@@ -358,6 +398,7 @@ func main() {
 	srvc := &service{
 		apnsTopic: topic,
 		db:        dbClient,
+		failures:  dbClient,
 		log:       log,
 		httpClient: &http.Client{
 			Timeout: time.Second * 5,
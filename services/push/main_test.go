@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/appleboy/gorush/gorush"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+type fakeFailureRecorder struct {
+	failures []db.NotificationFailure
+}
+
+func (r *fakeFailureRecorder) RecordNotificationFailure(failure *db.NotificationFailure) error {
+	r.failures = append(r.failures, *failure)
+	return nil
+}
+
+func TestDeliverRecordsFailures(t *testing.T) {
+	gorushServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := gorush.RequestPush{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		response := GorushResponse{Success: "ok", Counts: len(request.Notifications[0].Tokens)}
+		for _, token := range request.Notifications[0].Tokens {
+			if token == "expired-token" {
+				response.Logs = append(response.Logs, gorush.LogPushEntry{Type: "failed-push", Token: token, Error: "BadDeviceToken"})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer gorushServer.Close()
+
+	recorder := &fakeFailureRecorder{}
+	s := &service{
+		failures:          recorder,
+		log:               logrus.New(),
+		httpClient:        gorushServer.Client(),
+		gorushHTTPAddress: gorushServer.URL,
+	}
+	s.deliver("alice", db.NotificationCommentAction, PushNotification{Title: "alice", Body: "added a Reply"}, map[string][]string{
+		"ios":     {"valid-token", "expired-token"},
+		"android": {"valid-token"},
+		"windows": {"valid-token"},
+	})
+
+	sort.Slice(recorder.failures, func(i, j int) bool { return recorder.failures[i].Platform < recorder.failures[j].Platform })
+	assert.Equal(t, []db.NotificationFailure{
+		{Address: "alice", Type: db.NotificationCommentAction, Platform: "ios", Reason: "BadDeviceToken"},
+		{Address: "alice", Type: db.NotificationCommentAction, Platform: "windows", Reason: "platform not supported"},
+	}, recorder.failures)
+
+	// gorush being unreachable fails the delivery as a whole
+	gorushServer.Close()
+	recorder.failures = nil
+	s.deliver("bob", db.NotificationRewardTruUnlocked, PushNotification{}, map[string][]string{"android": {"valid-token"}})
+	if assert.Len(t, recorder.failures, 1) {
+		assert.Equal(t, "bob", recorder.failures[0].Address)
+		assert.Equal(t, db.NotificationRewardTruUnlocked, recorder.failures[0].Type)
+		assert.Contains(t, recorder.failures[0].Reason, "connection refused")
+	}
+}
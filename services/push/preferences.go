@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+// preferencesLookup returns the notification preferences of a user by address
+type preferencesLookup func(address string) (db.NotificationPreferences, error)
+
+// enqueueNotification queues the notification unless its receiver turned notifications of its type off.
+// A notification is still sent when the preferences of its receiver can't be retrieved.
+func enqueueNotification(notifications chan<- *Notification, preferences preferencesLookup, n *Notification) {
+	p, err := preferences(n.To)
+	if err == nil && !p.Enabled(n.Type) {
+		return
+	}
+	notifications <- n
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+func TestEnqueueNotification(t *testing.T) {
+	preferences := map[string]db.NotificationPreferences{
+		"alice": {db.NotificationCommentAction: false},
+		"bob":   {},
+		"carol": {db.NotificationMentionAction: false, db.NotificationCommentAction: true},
+	}
+	lookup := func(address string) (db.NotificationPreferences, error) {
+		if address == "dave" {
+			return nil, errors.New("db unavailable")
+		}
+		return preferences[address], nil
+	}
+
+	notifications := make(chan *Notification, 10)
+	for _, to := range []string{"alice", "bob", "carol", "dave", "erin"} {
+		enqueueNotification(notifications, lookup, &Notification{To: to, Type: db.NotificationCommentAction})
+	}
+	enqueueNotification(notifications, lookup, &Notification{To: "alice", Type: db.NotificationRewardTruUnlocked})
+	enqueueNotification(notifications, lookup, &Notification{To: "carol", Type: db.NotificationMentionAction})
+	close(notifications)
+
+	received := make([]string, 0)
+	for n := range notifications {
+		received = append(received, n.To+" "+n.Type.String())
+	}
+	// alice turned off comment notifications only, everything is on by default
+	assert.Equal(t, []string{
+		"bob Reply Added",
+		"carol Reply Added",
+		"dave Reply Added",
+		"erin Reply Added",
+		"alice Earned TRU",
+	}, received)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/machinebox/graphql"
 
@@ -13,7 +14,19 @@ import (
 
 const FEATURED_DEBATE_COMMUNITY_ID = "all"
 
-func (s *service) processBroadcastNotifications(bNotifications <-chan *app.BroadcastNotificationRequest, notifications chan<- *Notification) {
+// broadcastWorkers is the number of notifications of a broadcast sent at the same time
+const broadcastWorkers = 10
+
+// broadcastResults sums up how the notifications of a broadcast went
+type broadcastResults struct {
+	Attempted int
+	Sent      int
+	Failed    int
+	// Skipped are the users who turned notifications of the broadcast type off
+	Skipped int
+}
+
+func (s *service) processBroadcastNotifications(bNotifications <-chan *app.BroadcastNotificationRequest) {
 	for n := range bNotifications {
 		featuredClaimID, err := s.db.ClaimOfTheDayIDByCommunityID(FEATURED_DEBATE_COMMUNITY_ID)
 		if err != nil {
@@ -36,8 +49,8 @@ func (s *service) processBroadcastNotifications(bNotifications <-chan *app.Broad
 			featuredClaim.Claim.Body = featuredClaim.Claim.Body + "."
 		}
 
-		for _, user := range users {
-			notifications <- &Notification{
+		results := broadcast(users, broadcastWorkers, func(user db.User) *Notification {
+			return &Notification{
 				To:     user.Address,
 				TypeID: featuredClaim.Claim.ID,
 				Type:   db.NotificationFeaturedDebate,
@@ -48,8 +61,52 @@ func (s *service) processBroadcastNotifications(bNotifications <-chan *app.Broad
 				Action: "Featured Debate",
 				Trim:   true,
 			}
+		}, s.send)
+		s.log.Infof("broadcast notification sent type[%d] attempted[%d] sent[%d] failed[%d] skipped[%d]\n",
+			n.Type, results.Attempted, results.Sent, results.Failed, results.Skipped)
+	}
+}
+
+// broadcast sends a notification to every user with a pool of workers, skipping the users who turned
+// notifications of its type off, and aggregates the outcome of the sends
+func broadcast(users []db.User, workers int, notification func(user db.User) *Notification, send func(n *Notification) error) broadcastResults {
+	if workers < 1 {
+		workers = 1
+	}
+	results := broadcastResults{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan *Notification)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range queue {
+				err := send(n)
+				mu.Lock()
+				results.Attempted++
+				if err != nil {
+					results.Failed++
+				} else {
+					results.Sent++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	skipped := 0
+	for _, user := range users {
+		n := notification(user)
+		if !user.NotificationPreferences.Enabled(n.Type) {
+			skipped++
+			continue
 		}
+		queue <- n
 	}
+	close(queue)
+	wg.Wait()
+	results.Skipped = skipped
+	return results
 }
 
 func (s *service) getClaim(claimID int64) (ClaimResponse, error) {
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+func TestBroadcast(t *testing.T) {
+	users := make([]db.User, 0)
+	for i := 0; i < 1000; i++ {
+		user := db.User{Address: fmt.Sprintf("user-%d", i)}
+		if i%100 == 0 {
+			user.NotificationPreferences = db.NotificationPreferences{db.NotificationFeaturedDebate: false}
+		}
+		users = append(users, user)
+	}
+
+	var mu sync.Mutex
+	attempted := make(map[string]int)
+	var running, maxRunning int32
+	send := func(n *Notification) error {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+				break
+			}
+		}
+		mu.Lock()
+		attempted[n.To]++
+		mu.Unlock()
+		if n.To == "user-7" || n.To == "user-42" {
+			return errors.New("gorush unavailable")
+		}
+		return nil
+	}
+	notification := func(user db.User) *Notification {
+		return &Notification{To: user.Address, Type: db.NotificationFeaturedDebate}
+	}
+
+	results := broadcast(users, 8, notification, send)
+	assert.Equal(t, broadcastResults{Attempted: 990, Sent: 988, Failed: 2, Skipped: 10}, results)
+	// every user who didn't opt out got exactly one attempt
+	assert.Len(t, attempted, 990)
+	for address, count := range attempted {
+		assert.Equal(t, 1, count, address)
+	}
+	assert.Zero(t, attempted["user-100"])
+	assert.True(t, maxRunning <= 8)
+
+	assert.Equal(t, broadcastResults{}, broadcast(nil, 8, notification, send))
+}
@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/TruStory/octopus/services/truapi/db"
-	"github.com/gernest/mention"
 	stripmd "github.com/writeas/go-strip-markdown"
 )
 
@@ -25,8 +24,7 @@ func unique(values []string) []string {
 func (s *service) parseCosmosMentions(body string) (string, []string) {
 	parsedBody := body
 	usernameByAddress := map[string]string{}
-	terminators := []rune(" \n\r.,():!?'\"")
-	addresses := mention.GetTagsAsUniqueStrings('@', body, terminators...)
+	addresses := db.ParseMentions(body)
 	for _, address := range addresses {
 		user, err := s.db.UserByAddress(address)
 		if err != nil || user == nil {
@@ -41,6 +39,24 @@ func (s *service) parseCosmosMentions(body string) (string, []string) {
 	return parsedBody, addresses
 }
 
+// userAddresses returns the addresses of the users, skipping the ones that can't be found
+// and the ones without an address yet
+func (s *service) userAddresses(ids []int64) []string {
+	addresses := make([]string, 0, len(ids))
+	for _, id := range ids {
+		user, err := s.db.UserByID(id)
+		if err != nil || user == nil {
+			s.log.WithError(err).Errorf("could not find user for id %d", id)
+			continue
+		}
+		if user.Address == "" {
+			continue
+		}
+		addresses = append(addresses, user.Address)
+	}
+	return addresses
+}
+
 func (s *service) processCommentsNotifications(cNotifications <-chan *CommentNotificationRequest, notifications chan<- *Notification) {
 	for n := range cNotifications {
 		c, err := s.db.CommentByID(n.ID)
@@ -69,6 +85,10 @@ func (s *service) processCommentsNotifications(cNotifications <-chan *CommentNot
 		// skip comment creator
 		notified[n.Creator] = true
 		parsedComment, mentions := s.parseCosmosMentions(c.Body)
+		if n.MentionedUserIDs != nil {
+			// mentions resolved by truapi when the comment was created
+			mentions = s.userAddresses(n.MentionedUserIDs)
+		}
 		parsedComment = stripmd.Strip(parsedComment)
 		meta := db.NotificationMeta{
 			ClaimID:    &c.ClaimID,
@@ -89,7 +109,7 @@ func (s *service) processCommentsNotifications(cNotifications <-chan *CommentNot
 				continue
 			}
 			notified[p] = true
-			notifications <- &Notification{
+			enqueueNotification(notifications, s.db.NotificationPreferencesByAddress, &Notification{
 				From:   &c.Creator,
 				To:     p,
 				TypeID: typeId,
@@ -98,7 +118,7 @@ func (s *service) processCommentsNotifications(cNotifications <-chan *CommentNot
 				Meta:   mentionMeta,
 				Action: "Mentioned you in a reply",
 				Trim:   true,
-			}
+			})
 		}
 
 		for _, p := range participants {
@@ -106,7 +126,7 @@ func (s *service) processCommentsNotifications(cNotifications <-chan *CommentNot
 				continue
 			}
 			notified[p] = true
-			notifications <- &Notification{
+			enqueueNotification(notifications, s.db.NotificationPreferencesByAddress, &Notification{
 				From:   &c.Creator,
 				To:     p,
 				TypeID: typeId,
@@ -115,14 +135,14 @@ func (s *service) processCommentsNotifications(cNotifications <-chan *CommentNot
 				Meta:   meta,
 				Action: "Added a new reply",
 				Trim:   true,
-			}
+			})
 		}
 
 		if n.ArgumentCreator == "" {
 			// notify claim creator if claim level comment
 			if _, ok := notified[n.ClaimCreator]; !ok {
 				notified[n.ClaimCreator] = true
-				notifications <- &Notification{
+				enqueueNotification(notifications, s.db.NotificationPreferencesByAddress, &Notification{
 					From:   &c.Creator,
 					To:     n.ClaimCreator,
 					TypeID: typeId,
@@ -131,13 +151,13 @@ func (s *service) processCommentsNotifications(cNotifications <-chan *CommentNot
 					Meta:   meta,
 					Action: "Added a new reply",
 					Trim:   true,
-				}
+				})
 			}
 		} else {
 			// notify argument creator if argument level comment
 			if _, ok := notified[n.ArgumentCreator]; !ok {
 				notified[n.ArgumentCreator] = true
-				notifications <- &Notification{
+				enqueueNotification(notifications, s.db.NotificationPreferencesByAddress, &Notification{
 					From:   &c.Creator,
 					To:     n.ArgumentCreator,
 					TypeID: typeId,
@@ -146,7 +166,7 @@ func (s *service) processCommentsNotifications(cNotifications <-chan *CommentNot
 					Meta:   meta,
 					Action: "Added a new reply",
 					Trim:   true,
-				}
+				})
 			}
 		}
 
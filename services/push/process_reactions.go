@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+	app "github.com/TruStory/octopus/services/truapi/truapi"
+)
+
+func (s *service) processReactionsNotifications(rNotifications <-chan *app.ReactionNotificationRequest, notifications chan<- *Notification) {
+	for n := range rNotifications {
+		if n.ReactionableType != db.Argument {
+			s.log.Warnf("unsupported reactionable type [%s]\n", n.ReactionableType)
+			continue
+		}
+		argument, err := s.getArgumentSummary(n.ReactionableID)
+		if err != nil {
+			s.log.WithError(err).Errorf("could not retrieve argument for id [%d]\n", n.ReactionableID)
+			continue
+		}
+		argumentCreatorAddress := argument.ClaimArgument.Creator.Address
+		// don't notify users about reacting on their own arguments
+		if argumentCreatorAddress == n.Creator {
+			continue
+		}
+		notifications <- &Notification{
+			From:   strPtr(n.Creator),
+			To:     argumentCreatorAddress,
+			Msg:    fmt.Sprintf("%s your argument: %s", reactionVerb(n.ReactionType), argument.ClaimArgument.Summary),
+			TypeID: n.ReactionableID,
+			Type:   db.NotificationReactionReceived,
+			Meta: db.NotificationMeta{
+				ClaimID:    &argument.ClaimArgument.ClaimID,
+				ArgumentID: &n.ReactionableID,
+			},
+			Action: "Reaction Received",
+		}
+	}
+}
+
+func reactionVerb(reactionType db.ReactionType) string {
+	switch reactionType {
+	case db.GotAnIdea:
+		return "got an idea from"
+	case db.ChangedMyMind:
+		return "changed their mind because of"
+	}
+	return "reacted to"
+}
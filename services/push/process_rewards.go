@@ -32,7 +32,7 @@ func (s *service) processRewardsNotifications(rNotifications <-chan *app.RewardN
 			s.log.Warn("Unknown reward type")
 			continue
 		}
-		notifications <- &Notification{
+		enqueueNotification(notifications, s.db.NotificationPreferencesByAddress, &Notification{
 			To:     user.Address,
 			TypeID: 0,
 			Type:   nType,
@@ -42,7 +42,7 @@ func (s *service) processRewardsNotifications(rNotifications <-chan *app.RewardN
 			},
 			Action: "Reward unlocked",
 			Trim:   true,
-		}
+		})
 	}
 }
 
@@ -92,6 +92,8 @@ func getRewardReasonFromRequest(n app.RewardNotificationRequest, causer *db.User
 			stepCompleted = "has written at least one argument"
 		case app.RewardCauserActionReceiveFiveAgrees:
 			stepCompleted = "has received at least five agrees"
+		case app.RewardCauserActionJourneyComplete:
+			stepCompleted = "has completed every step of the journey"
 		}
 		return fmt.Sprintf(reason, causer.Username, stepCompleted)
 	}
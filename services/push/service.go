@@ -9,8 +9,14 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// failureRecorder records the push notifications that couldn't be delivered
+type failureRecorder interface {
+	RecordNotificationFailure(failure *db.NotificationFailure) error
+}
+
 type service struct {
 	db        *db.Client
+	failures  failureRecorder
 	apnsTopic string
 	log       logrus.FieldLogger
 	// gorush
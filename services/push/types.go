@@ -70,6 +70,8 @@ type CommentNotificationRequest struct {
 	ElementID       int64     `json:"elementId"`
 	Creator         string    `json:"creator"`
 	Timestamp       time.Time `json:"timestamp"`
+	// MentionedUserIDs are the users mentioned in the comment, other than its creator
+	MentionedUserIDs []int64 `json:"mentionedUserIds,omitempty"`
 }
 
 // GraphQL responses
@@ -0,0 +1,168 @@
+package spotlight
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_CACHE_SIZE = 500
+	DEFAULT_CACHE_TTL  = 10 * time.Minute
+)
+
+type cachedImage struct {
+	key         string
+	contentType string
+	etag        string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// imageETag is a strong validator of the rendered image bytes
+func imageETag(body []byte) string {
+	return fmt.Sprintf(`"%x"`, sha256.Sum256(body))
+}
+
+// etagMatches tells whether the If-None-Match header lists the etag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// serve writes the image, or only a 304 Not Modified when the client already holds it
+func (image *cachedImage) serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("ETag", image.etag)
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, image.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", image.contentType)
+	_, _ = w.Write(image.body)
+}
+
+// imageCache is a bounded LRU cache of rendered images whose entries expire after a TTL
+type imageCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+}
+
+func newImageCache(size int, ttl time.Duration) *imageCache {
+	if size <= 0 {
+		size = DEFAULT_CACHE_SIZE
+	}
+	if ttl <= 0 {
+		ttl = DEFAULT_CACHE_TTL
+	}
+	return &imageCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the image cached under the key, unless it's missing or expired
+func (c *imageCache) get(key string, now time.Time) (*cachedImage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	image := element.Value.(*cachedImage)
+	if !now.Before(image.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return image, true
+}
+
+// set caches the image under the key, evicting the least recently used image when full
+func (c *imageCache) set(key, contentType string, body []byte, now time.Time) *cachedImage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	image := &cachedImage{key: key, contentType: contentType, etag: imageETag(body), body: body, expiresAt: now.Add(c.ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = image
+		c.order.MoveToFront(element)
+		return image
+	}
+	c.entries[key] = c.order.PushFront(image)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedImage).key)
+	}
+	return image
+}
+
+// bufferingResponseWriter holds back what the handler writes so that successful responses
+// can be cached and validated before being sent
+type bufferingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferingResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferingResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// cached serves the images rendered by the handler from the cache, keyed by route, format and size,
+// tagging them with an ETag and answering matching conditional requests with a 304
+func (s *Service) cached(h http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		format, err := outputFormat(r, s.jpeg)
+		if err != nil {
+			// let the handler reject the format
+			h.ServeHTTP(w, r)
+			return
+		}
+		width, height, err := imageSize(r)
+		if err != nil {
+			// let the handler reject the size
+			h.ServeHTTP(w, r)
+			return
+		}
+		if strings.EqualFold(r.URL.Query().Get("format"), FORMAT_WEBP) {
+			// whether a WebP is served depends on the Accept header
+			w.Header().Add("Vary", "Accept")
+		}
+		key := fmt.Sprintf("%s?format=%s&size=%dx%d", r.URL.Path, format, width, height)
+
+		if image, ok := s.cache.get(key, time.Now()); ok {
+			image.serve(w, r)
+			return
+		}
+
+		buffer := &bufferingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(buffer, r)
+		if buffer.status != http.StatusOK || buffer.body.Len() == 0 || w.Header().Get("Cache-Control") == "no-store" {
+			w.WriteHeader(buffer.status)
+			_, _ = w.Write(buffer.body.Bytes())
+			return
+		}
+		s.cache.set(key, w.Header().Get("Content-Type"), buffer.body.Bytes(), time.Now()).serve(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
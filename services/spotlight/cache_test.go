@@ -0,0 +1,99 @@
+package spotlight
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/machinebox/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageCacheEviction(t *testing.T) {
+	cache := newImageCache(2, time.Minute)
+	now := time.Now()
+
+	cache.set("/claim/1/spotlight", "image/png", []byte("1"), now)
+	cache.set("/claim/2/spotlight", "image/png", []byte("2"), now)
+	// using the first image makes the second one the least recently used
+	_, ok := cache.get("/claim/1/spotlight", now)
+	assert.True(t, ok)
+	cache.set("/claim/3/spotlight", "image/png", []byte("3"), now)
+
+	_, ok = cache.get("/claim/2/spotlight", now)
+	assert.False(t, ok)
+	image, ok := cache.get("/claim/1/spotlight", now)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), image.body)
+
+	// expired images are not served
+	_, ok = cache.get("/claim/3/spotlight", now.Add(time.Minute))
+	assert.False(t, ok)
+}
+
+func TestCachedRenderSkipsGraphQL(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"claim":{"id":1,"body":"The earth is round"}}}`))
+	}))
+	defer server.Close()
+
+	s := &Service{
+		graphqlClient:  graphql.NewClient(server.URL),
+		graphqlTimeout: DEFAULT_GRAPHQL_TIMEOUT,
+		cache:          newImageCache(10, time.Minute),
+	}
+	handler := s.cached(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := getClaim(r.Context(), s, 1)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte(data.Claim.Body))
+	}))
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/claim/1/spotlight?format=png", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "The earth is round", w.Body.String())
+	}
+	assert.Equal(t, 1, calls)
+
+	// other formats are cached separately
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/claim/1/spotlight?format=jpeg", nil))
+	assert.Equal(t, 2, calls)
+}
+
+func TestCachedRenderConditionalGet(t *testing.T) {
+	s := &Service{cache: newImageCache(10, time.Minute)}
+	handler := s.cached(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("rendered"))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/claim/1/spotlight?format=png", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	r := httptest.NewRequest("GET", "/claim/1/spotlight?format=png", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	assert.Empty(t, w.Body.Bytes())
+
+	r.Header.Set("If-None-Match", `"stale"`)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "rendered", w.Body.String())
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/TruStory/octopus/services/spotlight"
 
@@ -12,7 +14,19 @@ import (
 func main() {
 	port := getEnv("PORT", "54448")
 	endpoint := mustEnv("SPOTLIGHT_GRAPHQL_ENDPOINT")
+	graphqlTimeout, err := time.ParseDuration(getEnv("SPOTLIGHT_GRAPHQL_TIMEOUT", "5s"))
+	if err != nil {
+		panic(fmt.Sprintf("invalid SPOTLIGHT_GRAPHQL_TIMEOUT: %s", err))
+	}
 	jpegEnabled := getEnv("SPOTLIGHT_JPEG_ENABLED", "") == "true"
+	cacheSize, err := strconv.Atoi(getEnv("SPOTLIGHT_CACHE_SIZE", "500"))
+	if err != nil {
+		panic(fmt.Sprintf("invalid SPOTLIGHT_CACHE_SIZE: %s", err))
+	}
+	cacheTTL, err := time.ParseDuration(getEnv("SPOTLIGHT_CACHE_TTL", "10m"))
+	if err != nil {
+		panic(fmt.Sprintf("invalid SPOTLIGHT_CACHE_TTL: %s", err))
+	}
 	config := truCtx.Config{
 		Database: truCtx.DatabaseConfig{
 			Host: getEnv("PG_ADDR", "localhost"),
@@ -23,7 +37,7 @@ func main() {
 			Pool: 25,
 		},
 	}
-	service := spotlight.NewService(port, endpoint, jpegEnabled, config)
+	service := spotlight.NewService(port, endpoint, graphqlTimeout, jpegEnabled, cacheSize, cacheTTL, config)
 	service.Run()
 }
 func getEnv(env, defaultValue string) string {
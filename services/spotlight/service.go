@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"fmt"
 	"html"
 	"image/jpeg"
 	"image/png"
@@ -11,7 +12,9 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -37,6 +40,7 @@ const (
 	WORDS_PER_LINE_ARGUMENT  = 10
 	WORDS_PER_LINE_COMMENT   = 10
 	WORDS_PER_LINE_HIGHLIGHT = 10
+	WORDS_PER_LINE_STORY_ARG = 7
 
 	MAX_CHARS_PER_LINE = 40
 
@@ -44,30 +48,57 @@ const (
 	BODY_LINES_ARGUMENT  = 4
 	BODY_LINES_COMMENT   = 4
 	BODY_LINES_HIGHLIGHT = 4
+	BODY_LINES_STORY_ARG = 3
+
+	FORMAT_PNG  = "png"
+	FORMAT_JPEG = "jpeg"
+	FORMAT_WEBP = "webp"
+
+	// WEBP_QUALITY is the cwebp quality factor, from 0 to 100
+	WEBP_QUALITY = 80
+
+	// the templates are laid out for the default size, and scaled to the requested one
+	DEFAULT_IMAGE_WIDTH  = 1920
+	DEFAULT_IMAGE_HEIGHT = 1080
+	MIN_IMAGE_SIZE       = 100
+	MAX_IMAGE_WIDTH      = 3840
+	MAX_IMAGE_HEIGHT     = 2160
+
+	DEFAULT_GRAPHQL_TIMEOUT = 5 * time.Second
 )
 
+var regexSVGSize = regexp.MustCompile(`(<svg[^>]*?\s)width="[^"]*"(\s[^>]*?)height="[^"]*"`)
+
 type Service struct {
-	port          string
-	router        *mux.Router
-	graphqlClient *graphql.Client
-	dbClient      *db.Client
-	jpeg          bool
+	port           string
+	router         *mux.Router
+	graphqlClient  *graphql.Client
+	graphqlTimeout time.Duration
+	dbClient       *db.Client
+	jpeg           bool
+	cache          *imageCache
 }
 
-func NewService(port, endpoint string, jpeg bool, config truCtx.Config) *Service {
+func NewService(port, endpoint string, graphqlTimeout time.Duration, jpeg bool, cacheSize int, cacheTTL time.Duration, config truCtx.Config) *Service {
+	if graphqlTimeout <= 0 {
+		graphqlTimeout = DEFAULT_GRAPHQL_TIMEOUT
+	}
 	return &Service{
-		port:          port,
-		router:        mux.NewRouter(),
-		graphqlClient: graphql.NewClient(endpoint),
-		dbClient:      db.NewDBClient(config),
-		jpeg:          jpeg,
+		port:           port,
+		router:         mux.NewRouter(),
+		graphqlClient:  graphql.NewClient(endpoint),
+		graphqlTimeout: graphqlTimeout,
+		dbClient:       db.NewDBClient(config),
+		jpeg:           jpeg,
+		cache:          newImageCache(cacheSize, cacheTTL),
 	}
 }
 func (s *Service) Run() {
-	s.router.Handle("/claim/{id:[0-9]+}/spotlight", renderClaim(s))
-	s.router.Handle("/argument/{id:[0-9]+}/spotlight", renderArgument(s))
-	s.router.Handle("/comment/{id:[0-9]+}/spotlight", renderComment(s))
-	s.router.Handle("/highlight/{id:[0-9]+}/spotlight", renderHighlight(s))
+	s.router.Handle("/claim/{id:[0-9]+}/spotlight", s.cached(renderClaim(s)))
+	s.router.Handle("/argument/{id:[0-9]+}/spotlight", s.cached(renderArgument(s)))
+	s.router.Handle("/argument/{storyID:[0-9]+}/{argumentID:[0-9]+}/spotlight", s.cached(renderStoryArgument(s)))
+	s.router.Handle("/comment/{id:[0-9]+}/spotlight", s.cached(renderComment(s)))
+	s.router.Handle("/highlight/{id:[0-9]+}/spotlight", s.cached(renderHighlight(s)))
 	http.Handle("/", s.router)
 	err := http.ListenAndServe(":"+s.port, nil)
 	if err != nil {
@@ -76,40 +107,193 @@ func (s *Service) Run() {
 	}
 }
 
-func render(preview string, w http.ResponseWriter, jpegEnabled bool) {
-	cmd := exec.Command("rsvg-convert", "-f", "png", "--width", "1920", "--height", "1080")
-	contentType := "image/png"
-	if jpegEnabled {
-		contentType = "image/jpeg"
+// outputFormat returns the format the image must be encoded in, as requested with the format query parameter,
+// falling back to the configured default when no format is given. WebP is only served to clients
+// accepting it, the others get a JPEG instead.
+func outputFormat(r *http.Request, jpegByDefault bool) (string, error) {
+	switch format := strings.ToLower(r.URL.Query().Get("format")); format {
+	case "":
+		if jpegByDefault {
+			return FORMAT_JPEG, nil
+		}
+		return FORMAT_PNG, nil
+	case FORMAT_PNG:
+		return FORMAT_PNG, nil
+	case FORMAT_JPEG, "jpg":
+		return FORMAT_JPEG, nil
+	case FORMAT_WEBP:
+		if !strings.Contains(r.Header.Get("Accept"), "image/webp") {
+			return FORMAT_JPEG, nil
+		}
+		return FORMAT_WEBP, nil
+	default:
+		return "", fmt.Errorf("unsupported image format %q", format)
 	}
-	w.Header().Add("Content-Type", contentType)
-	cmd.Stdin = strings.NewReader(preview)
+}
+
+// imageSize returns the canvas size requested with the w and h query parameters, clamped to the supported range.
+// It defaults to the size the templates are laid out for, and keeps their aspect ratio when only one side is given.
+func imageSize(r *http.Request) (int, int, error) {
+	parse := func(name string) (int, error) {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			return 0, nil
+		}
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid image %s %q", name, value)
+		}
+		return size, nil
+	}
+	width, err := parse("w")
+	if err != nil {
+		return 0, 0, err
+	}
+	height, err := parse("h")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch {
+	case width == 0 && height == 0:
+		width, height = DEFAULT_IMAGE_WIDTH, DEFAULT_IMAGE_HEIGHT
+	case height == 0:
+		height = (width*DEFAULT_IMAGE_HEIGHT + DEFAULT_IMAGE_WIDTH/2) / DEFAULT_IMAGE_WIDTH
+	case width == 0:
+		width = (height*DEFAULT_IMAGE_WIDTH + DEFAULT_IMAGE_HEIGHT/2) / DEFAULT_IMAGE_HEIGHT
+	}
+	return clamp(width, MIN_IMAGE_SIZE, MAX_IMAGE_WIDTH), clamp(height, MIN_IMAGE_SIZE, MAX_IMAGE_HEIGHT), nil
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+// scalePreview sets the size of the SVG canvas, leaving its viewBox untouched so that the text and margins
+// scale along with it. With another aspect ratio than the template's, the layout is centered.
+func scalePreview(preview string, width, height int) string {
+	loc := regexSVGSize.FindStringIndex(preview)
+	if loc == nil {
+		return preview
+	}
+	tag := regexSVGSize.ReplaceAllString(preview[loc[0]:loc[1]], fmt.Sprintf(`${1}width="%d"${2}height="%d"`, width, height))
+	return preview[:loc[0]] + tag + preview[loc[1]:]
+}
+
+func render(preview string, w http.ResponseWriter, r *http.Request, jpegByDefault bool) {
+	format, err := outputFormat(r, jpegByDefault)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	width, height, err := imageSize(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cmd := exec.Command("rsvg-convert", "-f", "png", "--background-color", "white",
+		"--width", strconv.Itoa(width), "--height", strconv.Itoa(height))
+	cmd.Stdin = strings.NewReader(scalePreview(preview, width, height))
 	buf := new(bytes.Buffer)
 	cmd.Stdout = buf
 
-	err := cmd.Run()
+	err = cmd.Run()
 	if err != nil {
 		http.Error(w, "URL Preview cannot be generated", http.StatusInternalServerError)
 		return
 	}
-	if !jpegEnabled {
-		_, err := io.Copy(w, buf)
+	writeImage(w, buf, format)
+}
+
+// writeImage writes the PNG produced by rsvg-convert, re-encoding it into JPEG or WebP when required
+func writeImage(w http.ResponseWriter, pngImage io.Reader, format string) {
+	switch format {
+	case FORMAT_PNG:
+		w.Header().Set("Content-Type", "image/png")
+		_, err := io.Copy(w, pngImage)
 		if err != nil {
 			http.Error(w, "URL Preview cannot be generated", http.StatusInternalServerError)
 		}
 		return
+	case FORMAT_WEBP:
+		encoded, err := encodeWebP(pngImage)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Can't encode to WebP", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "image/webp")
+		_, err = w.Write(encoded)
+		if err != nil {
+			log.Println(err)
+		}
+		return
 	}
 
-	pngImage, err := png.Decode(buf)
+	decoded, err := png.Decode(pngImage)
 	if err != nil {
 		http.Error(w, "PNG can't be decoded", http.StatusInternalServerError)
 		return
 	}
 
-	if err := jpeg.Encode(w, pngImage, nil); err != nil {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, decoded, nil); err != nil {
 		http.Error(w, "Can't encode to JPEG", http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	_, err = io.Copy(w, &encoded)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// encodeWebP converts a PNG into WebP with cwebp. Files are used rather than pipes,
+// which older versions of cwebp don't support.
+func encodeWebP(pngImage io.Reader) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "spotlight")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "image.png")
+	output := filepath.Join(dir, "image.webp")
+	source, err := ioutil.ReadAll(pngImage)
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(input, source, 0600)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("cwebp", "-quiet", "-q", strconv.Itoa(WEBP_QUALITY), input, "-o", output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("cwebp: %v: %s", err, out)
+	}
+	return ioutil.ReadFile(output)
+}
+
+// renderDefault renders the branded default image, served with a 200 when the data of a preview can't be
+// fetched so that crawlers still get a usable image. It isn't cached, for the actual preview to be rendered
+// as soon as the data can be fetched again.
+func renderDefault(s *Service, w http.ResponseWriter, r *http.Request) {
+	box := packr.New("Templates", "./templates")
+	rawPreview, err := box.Find("default.svg")
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "URL Preview error: svg file not found", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	render(string(rawPreview), w, r, s.jpeg)
 }
 
 func renderClaim(s *Service) http.Handler {
@@ -121,10 +305,10 @@ func renderClaim(s *Service) http.Handler {
 			http.Error(w, "Invalid claim ID passed.", http.StatusBadRequest)
 			return
 		}
-		data, err := getClaim(s, claimID)
+		data, err := getClaim(r.Context(), s, claimID)
 		if err != nil {
 			log.Println(err)
-			http.Error(w, "", http.StatusInternalServerError)
+			renderDefault(s, w, r)
 			return
 		}
 
@@ -136,7 +320,7 @@ func renderClaim(s *Service) http.Handler {
 			return
 		}
 		compiledPreview := compileClaimPreview(rawPreview, data.Claim)
-		render(compiledPreview, w, s.jpeg)
+		render(compiledPreview, w, r, s.jpeg)
 	}
 	return http.HandlerFunc(fn)
 }
@@ -164,10 +348,10 @@ func renderHighlight(s *Service) http.Handler {
 		var user UserObject
 		switch highlight.HighlightableType {
 		case "argument":
-			argument, err := getArgument(s, highlight.HighlightableID)
+			argument, err := getArgument(r.Context(), s, highlight.HighlightableID)
 			if err != nil {
 				log.Println(err)
-				http.Error(w, "Highlight URL Preview error, argument not found", http.StatusInternalServerError)
+				renderDefault(s, w, r)
 				return
 			}
 			user = argument.ClaimArgument.Creator
@@ -198,7 +382,7 @@ func renderHighlight(s *Service) http.Handler {
 			http.Error(w, "Highlight URL Preview error, template compilation failed", http.StatusInternalServerError)
 			return
 		}
-		render(compiledPreview, w, s.jpeg)
+		render(compiledPreview, w, r, s.jpeg)
 	}
 	return http.HandlerFunc(fn)
 }
@@ -212,10 +396,10 @@ func renderArgument(s *Service) http.Handler {
 			http.Error(w, "Invalid argument ID passed.", http.StatusBadRequest)
 			return
 		}
-		data, err := getArgument(s, argumentID)
+		data, err := getArgument(r.Context(), s, argumentID)
 		if err != nil {
 			log.Println(err)
-			http.Error(w, "", http.StatusInternalServerError)
+			renderDefault(s, w, r)
 			return
 		}
 
@@ -233,7 +417,54 @@ func renderArgument(s *Service) http.Handler {
 			http.Error(w, "Argument URL Preview error: svg file not found", http.StatusInternalServerError)
 			return
 		}
-		render(compiledPreview, w, s.jpeg)
+		render(compiledPreview, w, r, s.jpeg)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// renderStoryArgument renders an argument of a story along with its author and community
+func renderStoryArgument(s *Service) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		storyID, err := strconv.ParseInt(vars["storyID"], 10, 64)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Invalid story ID passed.", http.StatusBadRequest)
+			return
+		}
+		argumentID, err := strconv.ParseInt(vars["argumentID"], 10, 64)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Invalid argument ID passed.", http.StatusBadRequest)
+			return
+		}
+		data, err := getArgument(r.Context(), s, argumentID)
+		if err != nil {
+			log.Println(err)
+			renderDefault(s, w, r)
+			return
+		}
+		if data.ClaimArgument.ID == 0 || data.ClaimArgument.ClaimID != storyID {
+			http.Error(w, "Argument not found.", http.StatusNotFound)
+			return
+		}
+
+		box := packr.New("Templates", "./templates")
+		rawPreview, err := box.Find("argument.svg")
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Argument URL Preview error: svg file not found", http.StatusInternalServerError)
+			return
+		}
+
+		compiledPreview, err := compileStoryArgumentPreview(rawPreview, data.ClaimArgument)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Argument URL Preview error: template compilation failed", http.StatusInternalServerError)
+			return
+		}
+		render(compiledPreview, w, r, s.jpeg)
 	}
 
 	return http.HandlerFunc(fn)
@@ -274,7 +505,7 @@ func renderComment(s *Service) http.Handler {
 			http.Error(w, "Comment URL Preview error: svg file not found", http.StatusInternalServerError)
 			return
 		}
-		render(compiledPreview, w, s.jpeg)
+		render(compiledPreview, w, r, s.jpeg)
 	}
 
 	return http.HandlerFunc(fn)
@@ -319,6 +550,46 @@ func compileArgumentPreview(raw []byte, argument ArgumentObject) (string, error)
 	return compilePreview(raw, argument.Summary, WORDS_PER_LINE_ARGUMENT, BODY_LINES_COMMENT, argument.Creator)
 }
 
+func compileStoryArgumentPreview(raw []byte, argument ArgumentObject) (string, error) {
+	body := argument.Summary
+	if strings.TrimSpace(body) == "" {
+		body = argument.Body
+	}
+	// BODY
+	bodyLines := wordWrap(body, WORDS_PER_LINE_STORY_ARG)
+	// make sure to have minimum lines atleast
+	if len(bodyLines) < BODY_LINES_STORY_ARG {
+		for i := len(bodyLines); i < BODY_LINES_STORY_ARG; i++ {
+			bodyLines = append(bodyLines, "")
+		}
+	} else if len(bodyLines) > BODY_LINES_STORY_ARG {
+		bodyLines[BODY_LINES_STORY_ARG-1] += "..." // ellipsis if the entire body couldn't be contained in this preview
+	}
+
+	var compiled bytes.Buffer
+	tmpl, err := template.New("argument").Parse(string(raw))
+	if err != nil {
+		return "", err
+	}
+
+	vars := struct {
+		BodyLines []string
+		Creator   string
+		Community string
+	}{
+		BodyLines: bodyLines,
+		Creator:   html.EscapeString(argument.Creator.UserProfile.Username),
+		Community: html.EscapeString(argument.Claim.Community.Name),
+	}
+
+	err = tmpl.Execute(&compiled, vars)
+	if err != nil {
+		return "", err
+	}
+
+	return compiled.String(), nil
+}
+
 func compileCommentPreview(raw []byte, comment CommentObject) (string, error) {
 	return compilePreview(raw, comment.Body, WORDS_PER_LINE_COMMENT, BODY_LINES_COMMENT, comment.Creator)
 }
@@ -415,12 +686,13 @@ func wordWrap(body string, defaultWordsPerLine int) []string {
 	return lines
 }
 
-func getClaim(s *Service, claimID int64) (ClaimByIDResponse, error) {
+func getClaim(ctx context.Context, s *Service, claimID int64) (ClaimByIDResponse, error) {
 	graphqlReq := graphql.NewRequest(ClaimByIDQuery)
 
 	graphqlReq.Var("claimId", claimID)
 	var graphqlRes ClaimByIDResponse
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(ctx, s.graphqlTimeout)
+	defer cancel()
 	if err := s.graphqlClient.Run(ctx, graphqlReq, &graphqlRes); err != nil {
 		return graphqlRes, err
 	}
@@ -441,12 +713,13 @@ func getHighlight(s *Service, highlightID int64) (*db.Highlight, error) {
 	return highlight, nil
 }
 
-func getArgument(s *Service, argumentID int64) (ArgumentByIDResponse, error) {
+func getArgument(ctx context.Context, s *Service, argumentID int64) (ArgumentByIDResponse, error) {
 	graphqlReq := graphql.NewRequest(ArgumentByIDQuery)
 
 	graphqlReq.Var("argumentId", argumentID)
 	var graphqlRes ArgumentByIDResponse
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(ctx, s.graphqlTimeout)
+	defer cancel()
 	if err := s.graphqlClient.Run(ctx, graphqlReq, &graphqlRes); err != nil {
 		return graphqlRes, err
 	}
@@ -1,8 +1,18 @@
 package spotlight
 
 import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
 	"testing"
+	"time"
 
+	"github.com/gorilla/mux"
+	"github.com/machinebox/graphql"
 	"github.com/stretchr/testify/assert"
 	stripmd "github.com/writeas/go-strip-markdown"
 )
@@ -42,3 +52,164 @@ func TestLinkText(t *testing.T) {
 	assert.Equal(t, lines[1], "http://someveryveryv... says that")
 	assert.Equal(t, lines[2], "TruStory is awesome.")
 }
+
+func TestStoryArgumentPreview(t *testing.T) {
+	raw := []byte(`{{ index .BodyLines 0 }}|{{ index .BodyLines 2 }}|@{{ .Creator }}|{{ .Community }}`)
+	argument := ArgumentObject{
+		Body:    "Full argument body",
+		Creator: UserObject{UserProfile: UserProfileObject{Username: "shane"}},
+		Claim:   ClaimObject{Community: CommunityObject{Name: "Crypto & Blockchain"}},
+	}
+	compiled, err := compileStoryArgumentPreview(raw, argument)
+	assert.NoError(t, err)
+	// falls back to the body when there's no summary
+	assert.Equal(t, "Full argument body||@shane|Crypto &amp; Blockchain", compiled)
+}
+
+func TestImageFormats(t *testing.T) {
+	var source bytes.Buffer
+	assert.NoError(t, png.Encode(&source, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+
+	tests := []struct {
+		url         string
+		accept      string
+		contentType string
+		magic       []byte
+	}{
+		{"/claim/1/spotlight?format=png", "", "image/png", []byte("\x89PNG\r\n\x1a\n")},
+		{"/claim/1/spotlight?format=jpeg", "", "image/jpeg", []byte{0xff, 0xd8, 0xff}},
+		// defaults to the configured format
+		{"/claim/1/spotlight", "", "image/jpeg", []byte{0xff, 0xd8, 0xff}},
+		// falls back to JPEG for clients without WebP support
+		{"/claim/1/spotlight?format=webp", "image/png,image/*;q=0.8", "image/jpeg", []byte{0xff, 0xd8, 0xff}},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", test.url, nil)
+		r.Header.Set("Accept", test.accept)
+		format, err := outputFormat(r, true)
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+		writeImage(w, bytes.NewReader(source.Bytes()), format)
+		assert.Equal(t, test.contentType, w.Header().Get("Content-Type"), test.url)
+		assert.True(t, bytes.HasPrefix(w.Body.Bytes(), test.magic), test.url)
+	}
+
+	_, err := outputFormat(httptest.NewRequest("GET", "/claim/1/spotlight?format=gif", nil), true)
+	assert.Error(t, err)
+}
+
+func TestWebPImage(t *testing.T) {
+	if _, err := exec.LookPath("cwebp"); err != nil {
+		t.Skip("cwebp is not installed")
+	}
+	var source bytes.Buffer
+	assert.NoError(t, png.Encode(&source, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+
+	r := httptest.NewRequest("GET", "/claim/1/spotlight?format=webp", nil)
+	r.Header.Set("Accept", "image/webp,image/apng,image/*,*/*;q=0.8")
+	format, err := outputFormat(r, true)
+	assert.NoError(t, err)
+	assert.Equal(t, FORMAT_WEBP, format)
+	w := httptest.NewRecorder()
+	writeImage(w, bytes.NewReader(source.Bytes()), format)
+	assert.Equal(t, "image/webp", w.Header().Get("Content-Type"))
+	body := w.Body.Bytes()
+	assert.True(t, len(body) > 16)
+	// a RIFF container holding a VP8 (lossy), VP8L (lossless) or VP8X (extended) bitstream
+	assert.Equal(t, "RIFF", string(body[0:4]))
+	assert.Equal(t, "WEBPVP8", string(body[8:15]))
+}
+
+func TestImageSize(t *testing.T) {
+	tests := []struct {
+		query  string
+		width  int
+		height int
+	}{
+		{"", DEFAULT_IMAGE_WIDTH, DEFAULT_IMAGE_HEIGHT},
+		{"?w=1200&h=630", 1200, 630},
+		// keeps the aspect ratio of the templates
+		{"?w=640", 640, 360},
+		{"?h=720", 1280, 720},
+		// clamped to the supported range
+		{"?w=100000&h=100000", MAX_IMAGE_WIDTH, MAX_IMAGE_HEIGHT},
+		{"?w=1&h=-5", MIN_IMAGE_SIZE, MIN_IMAGE_SIZE},
+	}
+	for _, test := range tests {
+		width, height, err := imageSize(httptest.NewRequest("GET", "/claim/1/spotlight"+test.query, nil))
+		assert.NoError(t, err, test.query)
+		assert.Equal(t, test.width, width, test.query)
+		assert.Equal(t, test.height, height, test.query)
+	}
+
+	_, _, err := imageSize(httptest.NewRequest("GET", "/claim/1/spotlight?w=wide", nil))
+	assert.Error(t, err)
+}
+
+func TestScalePreview(t *testing.T) {
+	preview := `<?xml version="1.0"?><svg width="1920px" height="1080px" viewBox="0 0 1920 1080"><rect width="1920" height="1080"/></svg>`
+	assert.Equal(t,
+		`<?xml version="1.0"?><svg width="960" height="540" viewBox="0 0 1920 1080"><rect width="1920" height="1080"/></svg>`,
+		scalePreview(preview, 960, 540),
+	)
+}
+
+func TestRenderSizes(t *testing.T) {
+	if _, err := exec.LookPath("rsvg-convert"); err != nil {
+		t.Skip("rsvg-convert is not installed")
+	}
+	preview := `<svg width="1920" height="1080" viewBox="0 0 1920 1080" xmlns="http://www.w3.org/2000/svg">` +
+		`<rect x="105" y="105" width="1710" height="870" fill="#967BFF"/></svg>`
+
+	for _, size := range [][2]int{{DEFAULT_IMAGE_WIDTH, DEFAULT_IMAGE_HEIGHT}, {1200, 630}, {400, 400}} {
+		url := fmt.Sprintf("/claim/1/spotlight?format=png&w=%d&h=%d", size[0], size[1])
+		w := httptest.NewRecorder()
+		render(preview, w, httptest.NewRequest("GET", url, nil), false)
+		assert.Equal(t, http.StatusOK, w.Code, url)
+		config, err := png.DecodeConfig(w.Body)
+		assert.NoError(t, err, url)
+		assert.Equal(t, size[0], config.Width, url)
+		assert.Equal(t, size[1], config.Height, url)
+	}
+}
+
+func TestGraphQLTimeoutFallback(t *testing.T) {
+	if _, err := exec.LookPath("rsvg-convert"); err != nil {
+		t.Skip("rsvg-convert is not installed")
+	}
+	calls := 0
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// never responds
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	timeout := 200 * time.Millisecond
+	s := &Service{
+		graphqlClient:  graphql.NewClient(server.URL),
+		graphqlTimeout: timeout,
+		cache:          newImageCache(10, time.Minute),
+	}
+	router := mux.NewRouter()
+	router.Handle("/claim/{id:[0-9]+}/spotlight", s.cached(renderClaim(s)))
+
+	for i := 1; i <= 2; i++ {
+		start := time.Now()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/claim/1/spotlight?format=png", nil))
+		// the rendering of the default image takes a bit longer than the fetch
+		assert.True(t, time.Since(start) < timeout+5*time.Second)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		config, err := png.DecodeConfig(w.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, DEFAULT_IMAGE_WIDTH, config.Width)
+		assert.Equal(t, DEFAULT_IMAGE_HEIGHT, config.Height)
+		// the default image isn't cached
+		assert.Equal(t, i, calls)
+	}
+}
@@ -33,6 +33,13 @@ const ArgumentByIDQuery = `
 	query ArgumentQuery($argumentId: ID!) {
     claimArgument(id: $argumentId) {
 			id
+			claimId
+			claim {
+				community {
+					id
+					name
+				}
+			}
 			summary
 			body
 			creator {
@@ -80,11 +87,13 @@ type ClaimObject struct {
 
 // ArgumentObject defines the schema of an argument
 type ArgumentObject struct {
-	ID           int64      `json:"id"`
-	Body         string     `json:"body"`
-	Summary      string     `json:"summary"`
-	Creator      UserObject `json:"creator"`
-	UpvotedCount int        `json:"upvotedCount"`
+	ID           int64       `json:"id"`
+	ClaimID      int64       `json:"claimId"`
+	Claim        ClaimObject `json:"claim"`
+	Body         string      `json:"body"`
+	Summary      string      `json:"summary"`
+	Creator      UserObject  `json:"creator"`
+	UpvotedCount int         `json:"upvotedCount"`
 }
 
 // CommentObject defines the schema of a comment
@@ -5,8 +5,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"path"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/tendermint/tendermint/crypto/secp256k1"
 
@@ -43,11 +47,32 @@ type API struct {
 	apiCtx    truCtx.TruAPIContext
 	Supported MsgTypes
 	router    *mux.Router
+
+	// querySlots limits the in-flight queries to the chain node, nil when unlimited
+	querySlots      chan struct{}
+	inFlightQueries int64
+	node            nodeQuerier
+	queryRetry      queryRetryPolicy
+
+	serversMu sync.Mutex
+	servers   []*http.Server
 }
 
+// shutdownTimeout is how long in-flight requests are given to complete once the API is asked to stop
+const shutdownTimeout = 30 * time.Second
+
 // NewAPI creates an `API` struct from a client context and a `MsgTypes` schema
 func NewAPI(apiCtx truCtx.TruAPIContext, supported MsgTypes) *API {
-	a := API{apiCtx: apiCtx, Supported: supported, router: mux.NewRouter()}
+	a := API{
+		apiCtx:     apiCtx,
+		Supported:  supported,
+		router:     mux.NewRouter(),
+		node:       apiCtx,
+		queryRetry: newQueryRetryPolicy(apiCtx.Config.Host),
+	}
+	if apiCtx.Config.Host.MaxConcurrentQueries > 0 {
+		a.querySlots = make(chan struct{}, apiCtx.Config.Host.MaxConcurrentQueries)
+	}
 	return &a
 }
 
@@ -87,6 +112,11 @@ func (a *API) redirectHTTPS() http.Handler {
 			w.WriteHeader(http.StatusOK)
 			return
 		}
+		// health checks from the load balancer don't go through the redirect
+		if r.URL.Path == "/health" {
+			a.router.ServeHTTP(w, r)
+			return
+		}
 		forwarded := r.Header.Get("X-Forwarded-Proto")
 		if forwarded != "https" {
 			url := fmt.Sprintf("https://%s%s", r.Host, r.URL.Path)
@@ -97,48 +127,103 @@ func (a *API) redirectHTTPS() http.Handler {
 	})
 }
 
-// ListenAndServe serves HTTP using the API router
-func (a *API) ListenAndServe(addr string) error {
+// ListenAndServe serves HTTP using the API router until the context is done, then shuts down gracefully
+func (a *API) ListenAndServe(ctx context.Context, addr string) error {
 	letsEncryptEnabled := a.apiCtx.Config.Host.HTTPSEnabled
 	if !letsEncryptEnabled {
-		return http.ListenAndServe(addr, a.redirectHTTPS())
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		return a.serve(ctx, l)
 	}
-	return a.listenAndServeTLS()
+	return a.listenAndServeTLS(ctx)
+}
+
+func (a *API) serve(ctx context.Context, l net.Listener) error {
+	server := a.trackServer(&http.Server{
+		Handler: a.redirectHTTPS(),
+	})
+	return a.runServers(ctx, func() error {
+		return server.Serve(l)
+	})
 }
 
-func (a *API) listenAndServeTLS() error {
+func (a *API) listenAndServeTLS(ctx context.Context) error {
 	m := &autocert.Manager{
 		Cache:      autocert.DirCache(a.apiCtx.Config.Host.HTTPSCacheDir),
 		Prompt:     autocert.AcceptTOS,
 		HostPolicy: autocert.HostWhitelist(a.apiCtx.Config.Host.HTTPSDomainWhitelist...),
 	}
-	httpServer := &http.Server{
+	httpServer := a.trackServer(&http.Server{
 		Addr:    ":http",
 		Handler: a.redirectHTTPS(),
-	}
-	secureServer := &http.Server{
+	})
+	secureServer := a.trackServer(&http.Server{
 		Addr:      ":https",
 		Handler:   a.router,
 		TLSConfig: m.TLSConfig(),
-	}
+	})
 
-	g, ctx := errgroup.WithContext(context.Background())
-	g.Go(func() error {
+	return a.runServers(ctx, func() error {
 		return httpServer.ListenAndServe()
-	})
-	g.Go(func() error {
+	}, func() error {
 		return secureServer.ListenAndServeTLS("", "")
 	})
+}
+
+func (a *API) trackServer(server *http.Server) *http.Server {
+	a.serversMu.Lock()
+	defer a.serversMu.Unlock()
+	a.servers = append(a.servers, server)
+	return server
+}
+
+// runServers runs the servers until one of them fails, the context is done or Shutdown is called,
+// and then gracefully shuts them all down
+func (a *API) runServers(ctx context.Context, servers ...func() error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	g := new(errgroup.Group)
+	for _, serve := range servers {
+		serve := serve
+		g.Go(func() error {
+			defer cancel()
+			err := serve()
+			if err == http.ErrServerClosed {
+				return nil
+			}
+			return err
+		})
+	}
 	g.Go(func() error {
 		<-ctx.Done()
-		_ = httpServer.Shutdown(ctx)
-		_ = secureServer.Shutdown(ctx)
-		return nil
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancelShutdown()
+		return a.Shutdown(shutdownCtx)
 	})
 
 	return g.Wait()
 }
 
+// Shutdown gracefully shuts down the HTTP and HTTPS servers, waiting for in-flight requests
+// to complete until the context is done
+func (a *API) Shutdown(ctx context.Context) error {
+	a.serversMu.Lock()
+	servers := append([]*http.Server(nil), a.servers...)
+	a.serversMu.Unlock()
+
+	g := new(errgroup.Group)
+	for _, server := range servers {
+		server := server
+		g.Go(func() error {
+			return server.Shutdown(ctx)
+		})
+	}
+	return g.Wait()
+}
+
 // RegisterKey generates a new address/account for a public key
 func (a *API) RegisterKey(k tcmn.HexBytes, algo string, registrarAccountNumber, registrarSequence uint64) (accAddr sdk.AccAddress, err error) {
 
@@ -225,7 +310,8 @@ func (a *API) signAndBroadcastRegistrationTx(addr []byte, k tcmn.HexBytes, algo
 	return res, nil
 }
 
-// SendGiftToAddress sends gift coins to any user
+// SendGiftToAddress sends gift coins to any user, signing with the broker account and sequence numbers.
+// It returns ErrSequenceMismatch when the sequence is stale.
 func (a *API) SendGiftToAddress(address string, amount sdk.Coin, brokerAccountNumber, brokerSequence uint64, memo string) error {
 	recipient, err := sdk.AccAddressFromBech32(address)
 	if err != nil {
@@ -276,7 +362,53 @@ func (a *API) signAndBroadcastGiftTx(recipient sdk.AccAddress, amount sdk.Coin,
 	}
 	fmt.Println(res)
 
-	return res, nil
+	return res, txResponseError(res.Code, res.RawLog)
+}
+
+// InFlightQueries returns the number of queries currently dispatched to the Tendermint node
+func (a *API) InFlightQueries() int64 {
+	return atomic.LoadInt64(&a.inFlightQueries)
+}
+
+// queryWithData dispatches a query to the Tendermint node once a query slot is available,
+// giving up on it when the context is done
+func (a *API) queryWithData(ctx context.Context, path string, data []byte) ([]byte, error) {
+	if a.querySlots != nil {
+		select {
+		case a.querySlots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	type result struct {
+		res []byte
+		err error
+	}
+	// the node client can't be interrupted, so an abandoned query keeps its slot until the node answers
+	done := make(chan result, 1)
+	go func() {
+		atomic.AddInt64(&a.inFlightQueries, 1)
+		defer func() {
+			atomic.AddInt64(&a.inFlightQueries, -1)
+			if a.querySlots != nil {
+				<-a.querySlots
+			}
+		}()
+		var res []byte
+		err := a.queryRetry.do(ctx, func() (err error) {
+			res, _, err = a.node.QueryWithData(path, data)
+			return err
+		})
+		done <- result{res, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.res, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 // RunQuery dispatches a query (path + params) to the Tendermint node
@@ -287,7 +419,7 @@ func (a *API) RunQuery(path string, params interface{}) ([]byte, error) {
 		return nil, err
 	}
 
-	res, _, err := a.apiCtx.QueryWithData("/custom/"+path, paramBytes)
+	res, err := a.queryWithData(context.Background(), "/custom/"+path, paramBytes)
 	if err != nil {
 		return res, err
 	}
@@ -297,11 +429,17 @@ func (a *API) RunQuery(path string, params interface{}) ([]byte, error) {
 
 // Query dispatches a query to the Tendermint node with Amino encoded params
 func (a *API) Query(path string, params interface{}, cdc *codec.Codec) ([]byte, error) {
+	return a.QueryWithContext(context.Background(), path, params, cdc)
+}
+
+// QueryWithContext is like Query, but returns the context error as soon as the context is done,
+// whether the query is waiting for a slot or already dispatched to the node
+func (a *API) QueryWithContext(ctx context.Context, path string, params interface{}, cdc *codec.Codec) ([]byte, error) {
 	paramBytes, err := cdc.MarshalJSON(params)
 	if err != nil {
 		return nil, err
 	}
-	res, _, err := a.apiCtx.QueryWithData("/custom/"+path, paramBytes)
+	res, err := a.queryWithData(ctx, "/custom/"+path, paramBytes)
 	if err != nil {
 		return res, err
 	}
@@ -309,6 +447,23 @@ func (a *API) Query(path string, params interface{}, cdc *codec.Codec) ([]byte,
 	return res, nil
 }
 
+// QuerySubspace returns every key/value pair of a store whose key starts with the prefix, in a single query
+func (a *API) QuerySubspace(ctx context.Context, storeName string, prefix []byte) ([]sdk.KVPair, error) {
+	res, err := a.queryWithData(ctx, fmt.Sprintf("/store/%s/subspace", storeName), prefix)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]sdk.KVPair, 0)
+	if len(res) == 0 {
+		return pairs, nil
+	}
+	err = codec.New().UnmarshalBinaryLengthPrefixed(res, &pairs)
+	if err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
 // DeliverPresigned dispatches a pre-signed transaction to the Tendermint node
 func (a *API) DeliverPresigned(tx auth.StdTx) (res sdk.TxResponse, err error) {
 	ctx := a.apiCtx
@@ -0,0 +1,79 @@
+package chttp
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+)
+
+func TestShutdownDrainsInFlightRequests(t *testing.T) {
+	a := NewAPI(truCtx.TruAPIContext{}, MsgTypes{})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	a.Handle("/slow", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	}))
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	served := make(chan error, 1)
+	go func() { served <- a.serve(context.Background(), l) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		res, err := http.Get("http://" + l.Addr().String() + "/slow")
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer res.Body.Close()
+		body, err := ioutil.ReadAll(res.Body)
+		responses <- result{body: string(body), err: err}
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- a.Shutdown(context.Background()) }()
+
+	// the shutdown waits for the in-flight request
+	select {
+	case <-shutdown:
+		t.Fatal("shut down before the in-flight request completed")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+
+	response := <-responses
+	assert.NoError(t, response.err)
+	assert.Equal(t, "done", response.body)
+	assert.NoError(t, <-shutdown)
+	assert.NoError(t, <-served)
+}
+
+func TestListenAndServeStopsWhenContextIsDone(t *testing.T) {
+	a := NewAPI(truCtx.TruAPIContext{}, MsgTypes{})
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- a.ListenAndServe(ctx, "127.0.0.1:0") }()
+	cancel()
+
+	select {
+	case err := <-served:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
@@ -1,9 +1,16 @@
 package chttp
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+// ErrSequenceMismatch is returned when a transaction is rejected because it was signed with a stale account sequence
+var ErrSequenceMismatch = errors.New("account sequence mismatch")
+
 func expectedMessagesError(receivedCount int, providedTypes []string) error {
 	s := "Tx Error: Got %i messages in expected sequence %v"
 	return fmt.Errorf(s, receivedCount, providedTypes)
@@ -23,3 +30,14 @@ func unsupportedAlgoError(name string, supported []string) error {
 	s := "Tx Error: Unsupported public key algorithm \"%s\" (supported: %v)"
 	return fmt.Errorf(s, name, supported)
 }
+
+// txResponseError returns the error a broadcasted transaction was rejected with, if any
+func txResponseError(code uint32, rawLog string) error {
+	if code == 0 {
+		return nil
+	}
+	if code == uint32(sdk.CodeUnauthorized) && strings.Contains(rawLog, "account sequence") {
+		return fmt.Errorf("%w: %s", ErrSequenceMismatch, rawLog)
+	}
+	return fmt.Errorf("Tx Error: transaction rejected with code %d: %s", code, rawLog)
+}
@@ -0,0 +1,151 @@
+package chttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	app "github.com/TruStory/truchain/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
+)
+
+// maxGiftAttempts is how many times a gift is broadcast while the broker sequence keeps going stale
+const maxGiftAttempts = 3
+
+// giftRetryBackoff is the wait before retrying a gift rejected for a stale sequence, doubled after every retry
+const giftRetryBackoff = 100 * time.Millisecond
+
+// ErrBrokerQuery is returned when the account of the reward broker can't be queried
+var ErrBrokerQuery = errors.New("unable to query the reward broker account")
+
+// ValidateGift checks that the gifted coin is in the stake denomination
+func ValidateGift(amount sdk.Coin) error {
+	if amount.Denom != app.StakeDenom {
+		return fmt.Errorf("invalid denomination coin got %s wanted %s", amount.Denom, app.StakeDenom)
+	}
+	return nil
+}
+
+// BrokerSequence tracks the account and sequence numbers of the reward broker across gifts
+type BrokerSequence struct {
+	query         func(ctx context.Context) (accountNumber, sequence uint64, err error)
+	known         bool
+	accountNumber uint64
+	sequence      uint64
+}
+
+// NewBrokerSequence returns a broker sequence that gets its numbers from the given query
+func NewBrokerSequence(query func(ctx context.Context) (accountNumber, sequence uint64, err error)) *BrokerSequence {
+	return &BrokerSequence{query: query}
+}
+
+// Broadcast calls broadcast with the broker numbers, moving on to the next sequence when it succeeds.
+// After a failed broadcast the numbers are queried again, since the sequence may or may not have been consumed,
+// and a broadcast rejected for a stale sequence is retried after a backoff, up to maxGiftAttempts times.
+// It returns an ErrBrokerQuery error when the broker numbers can't be queried.
+func (s *BrokerSequence) Broadcast(ctx context.Context, broadcast func(accountNumber, sequence uint64) error) error {
+	var err error
+	backoff := giftRetryBackoff
+	for attempt := 0; attempt < maxGiftAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			backoff *= 2
+		}
+		if !s.known {
+			s.accountNumber, s.sequence, err = s.query(ctx)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrBrokerQuery, err)
+			}
+			s.known = true
+		}
+		err = broadcast(s.accountNumber, s.sequence)
+		if err == nil {
+			s.sequence++
+			return nil
+		}
+		s.known = false
+		if !errors.Is(err, ErrSequenceMismatch) {
+			return err
+		}
+	}
+	return err
+}
+
+// SendGiftToAddresses sends gift coins to many users from the reward broker account.
+// It returns the outcome for every recipient, a nil error meaning the gift was delivered.
+// It only fails as a whole when the broker account can't be queried, in which case
+// the recipients that weren't attempted yet are missing from the outcomes.
+func (a *API) SendGiftToAddresses(ctx context.Context, gifts map[string]sdk.Coin) (map[string]error, error) {
+	return sendGifts(ctx, gifts, NewBrokerSequence(a.brokerAccount), func(recipient sdk.AccAddress, amount sdk.Coin, accountNumber, sequence uint64) error {
+		_, err := a.signAndBroadcastGiftTx(recipient, amount, accountNumber, sequence, "")
+		return err
+	})
+}
+
+// brokerAccount queries the account and sequence numbers of the reward broker
+func (a *API) brokerAccount(ctx context.Context) (uint64, uint64, error) {
+	addr, err := sdk.AccAddressFromBech32(a.apiCtx.Config.RewardBroker.Addr)
+	if err != nil {
+		return 0, 0, err
+	}
+	queryRoute := path.Join(auth.QuerierRoute, auth.QueryAccount)
+	res, err := a.QueryWithContext(ctx, queryRoute, auth.QueryAccountParams{Address: addr}, auth.ModuleCdc)
+	if err != nil {
+		return 0, 0, err
+	}
+	var acc authexported.Account
+	err = auth.ModuleCdc.UnmarshalJSON(res, &acc)
+	if err != nil {
+		return 0, 0, err
+	}
+	return acc.GetAccountNumber(), acc.GetSequence(), nil
+}
+
+// sendGifts broadcasts the gifts one after the other, in address order, sharing the broker sequence
+// between them. Invalid addresses and coins are rejected without being broadcast.
+func sendGifts(
+	ctx context.Context,
+	gifts map[string]sdk.Coin,
+	broker *BrokerSequence,
+	broadcast func(recipient sdk.AccAddress, amount sdk.Coin, accountNumber, sequence uint64) error,
+) (map[string]error, error) {
+	addresses := make([]string, 0, len(gifts))
+	for address := range gifts {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	results := make(map[string]error, len(gifts))
+	for _, address := range addresses {
+		recipient, err := sdk.AccAddressFromBech32(address)
+		if err != nil {
+			results[address] = err
+			continue
+		}
+		amount := gifts[address]
+		err = ValidateGift(amount)
+		if err != nil {
+			results[address] = err
+			continue
+		}
+
+		err = broker.Broadcast(ctx, func(accountNumber, sequence uint64) error {
+			return broadcast(recipient, amount, accountNumber, sequence)
+		})
+		if errors.Is(err, ErrBrokerQuery) {
+			return results, err
+		}
+		results[address] = err
+	}
+
+	return results, nil
+}
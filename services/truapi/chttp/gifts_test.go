@@ -0,0 +1,65 @@
+package chttp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendGifts(t *testing.T) {
+	valid := sdk.AccAddress([]byte("recipient-address-01")).String()
+	stale := sdk.AccAddress([]byte("recipient-address-02")).String()
+	rejected := sdk.AccAddress([]byte("recipient-address-03")).String()
+	otherDenom := sdk.AccAddress([]byte("recipient-address-04")).String()
+	gifts := map[string]sdk.Coin{
+		valid:           sdk.NewInt64Coin("utru", 10),
+		stale:           sdk.NewInt64Coin("utru", 20),
+		rejected:        sdk.NewInt64Coin("utru", 30),
+		"not-a-address": sdk.NewInt64Coin("utru", 40),
+		otherDenom:      sdk.NewInt64Coin("tru", 50),
+	}
+
+	chainSequence := uint64(5)
+	staleOnce := true
+	var sequences []uint64
+	queryBroker := func(context.Context) (uint64, uint64, error) {
+		return 1, chainSequence, nil
+	}
+	broadcast := func(recipient sdk.AccAddress, _ sdk.Coin, _, sequence uint64) error {
+		sequences = append(sequences, sequence)
+		switch recipient.String() {
+		case stale:
+			if staleOnce {
+				// another transaction of the broker got in first
+				staleOnce = false
+				chainSequence++
+				return ErrSequenceMismatch
+			}
+		case rejected:
+			return errors.New("rejected")
+		}
+		chainSequence++
+		return nil
+	}
+
+	results, err := sendGifts(context.Background(), gifts, NewBrokerSequence(queryBroker), broadcast)
+	assert.NoError(t, err)
+	assert.Len(t, results, len(gifts))
+	assert.NoError(t, results[valid])
+	assert.NoError(t, results[stale])
+	assert.EqualError(t, results[rejected], "rejected")
+	assert.Error(t, results["not-a-address"])
+	assert.EqualError(t, results[otherDenom], "invalid denomination coin got tru wanted utru")
+	// sequences are consecutive, and re-queried after a stale one
+	assert.Len(t, sequences, 4)
+	for i := 1; i < len(sequences); i++ {
+		assert.Equal(t, sequences[i-1]+1, sequences[i])
+	}
+
+	down := NewBrokerSequence(func(context.Context) (uint64, uint64, error) { return 0, 0, errors.New("chain down") })
+	_, err = sendGifts(context.Background(), gifts, down, broadcast)
+	assert.True(t, errors.Is(err, ErrBrokerQuery))
+}
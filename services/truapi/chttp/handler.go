@@ -11,19 +11,23 @@ type Handler func(*http.Request) Response
 // HandlerFunc wraps a `chttp.Handler` in a standard `http` handler
 func (h Handler) HandlerFunc() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		res := h(r)
-		bs, err := res.Marshal()
+		writeResponse(w, h(r))
+	}
+}
 
-		if err != nil {
-			fmt.Println("INTERNAL DECODING ERROR: ", err, string(res.Data()))
-			panic(err)
-		}
+// writeResponse marshals a Response and writes it with its status code
+func writeResponse(w http.ResponseWriter, res Response) {
+	bs, err := res.Marshal()
+
+	if err != nil {
+		fmt.Println("INTERNAL DECODING ERROR: ", err, string(res.Data()))
+		panic(err)
+	}
 
-		w.WriteHeader(res.HTTPCode())
-		_, err = w.Write(bs)
+	w.WriteHeader(res.HTTPCode())
+	_, err = w.Write(bs)
 
-		if err != nil {
-			panic(err)
-		}
+	if err != nil {
+		panic(err)
 	}
 }
@@ -0,0 +1,80 @@
+package chttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+)
+
+const (
+	defaultQueryMaxAttempts  = 3
+	defaultQueryRetryBackoff = 100 * time.Millisecond
+)
+
+// nodeQuerier dispatches queries to the Tendermint node
+type nodeQuerier interface {
+	QueryWithData(path string, data []byte) ([]byte, int64, error)
+}
+
+// queryRetryPolicy retries queries that failed to reach the node, with an exponential backoff
+type queryRetryPolicy struct {
+	maxAttempts int
+	backoff     time.Duration
+}
+
+func newQueryRetryPolicy(config truCtx.HostConfig) queryRetryPolicy {
+	policy := queryRetryPolicy{
+		maxAttempts: config.QueryMaxAttempts,
+		backoff:     time.Duration(config.QueryRetryBackoff) * time.Millisecond,
+	}
+	if policy.maxAttempts <= 0 {
+		policy.maxAttempts = defaultQueryMaxAttempts
+	}
+	if policy.backoff <= 0 {
+		policy.backoff = defaultQueryRetryBackoff
+	}
+	return policy
+}
+
+// do runs the query until it succeeds, fails with a query error, runs out of attempts or the context is done
+func (p queryRetryPolicy) do(ctx context.Context, query func() error) error {
+	backoff := p.backoff
+	for attempt := 1; ; attempt++ {
+		err := query()
+		if err == nil || attempt >= p.maxAttempts || !isConnectionError(err) {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// isConnectionError tells whether the error comes from reaching the node rather than from the query itself
+func isConnectionError(err error) bool {
+	for err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) ||
+			errors.Is(err, io.EOF) ||
+			errors.Is(err, io.ErrUnexpectedEOF) ||
+			errors.Is(err, syscall.ECONNREFUSED) ||
+			errors.Is(err, syscall.ECONNRESET) {
+			return true
+		}
+		// the Tendermint RPC client wraps errors with github.com/pkg/errors
+		causer, ok := err.(interface{ Cause() error })
+		if !ok || causer.Cause() == err {
+			return false
+		}
+		err = causer.Cause()
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package chttp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+)
+
+type flakyNode struct {
+	failures int
+	err      error
+	attempts int
+}
+
+func (n *flakyNode) QueryWithData(path string, data []byte) ([]byte, int64, error) {
+	n.attempts++
+	if n.attempts <= n.failures {
+		return nil, 0, n.err
+	}
+	return []byte("result"), 1, nil
+}
+
+func TestQueryRetriesConnectionErrors(t *testing.T) {
+	config := truCtx.Config{Host: truCtx.HostConfig{QueryMaxAttempts: 3, QueryRetryBackoff: 1}}
+	connectionErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	node := &flakyNode{failures: 2, err: connectionErr}
+	a := NewAPI(truCtx.TruAPIContext{Config: config}, MsgTypes{})
+	a.node = node
+	res, err := a.RunQuery("trustory/claims", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("result"), res)
+	assert.Equal(t, 3, node.attempts)
+
+	// gives up once the attempts are exhausted
+	node = &flakyNode{failures: 5, err: connectionErr}
+	a.node = node
+	_, err = a.RunQuery("trustory/claims", nil)
+	assert.Error(t, err)
+	assert.Equal(t, 3, node.attempts)
+
+	// query errors are not retried
+	node = &flakyNode{failures: 1, err: errors.New("unknown claim")}
+	a.node = node
+	_, err = a.RunQuery("trustory/claims", nil)
+	assert.EqualError(t, err, "unknown claim")
+	assert.Equal(t, 1, node.attempts)
+}
+
+func TestQueryRetryPolicyDefaults(t *testing.T) {
+	policy := newQueryRetryPolicy(truCtx.HostConfig{})
+	assert.Equal(t, defaultQueryMaxAttempts, policy.maxAttempts)
+	assert.Equal(t, defaultQueryRetryBackoff, policy.backoff)
+	assert.Equal(t, 5*time.Millisecond, newQueryRetryPolicy(truCtx.HostConfig{QueryRetryBackoff: 5}).backoff)
+}
+
+type causeError struct{ cause error }
+
+func (e causeError) Error() string { return "ABCIQuery: " + e.cause.Error() }
+func (e causeError) Cause() error  { return e.cause }
+
+func TestIsConnectionError(t *testing.T) {
+	assert.True(t, isConnectionError(causeError{&net.OpError{Op: "dial", Err: errors.New("connection refused")}}))
+	assert.False(t, isConnectionError(causeError{errors.New("unknown claim")}))
+	assert.False(t, isConnectionError(errors.New("unknown claim")))
+}
+
+type hangingNode struct {
+	release chan struct{}
+}
+
+func (n *hangingNode) QueryWithData(path string, data []byte) ([]byte, int64, error) {
+	<-n.release
+	return []byte("late"), 1, nil
+}
+
+func TestQueryWithContextCancellation(t *testing.T) {
+	config := truCtx.Config{Host: truCtx.HostConfig{MaxConcurrentQueries: 1}}
+	node := &hangingNode{release: make(chan struct{})}
+	a := NewAPI(truCtx.TruAPIContext{Config: config}, MsgTypes{})
+	a.node = node
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := a.QueryWithContext(ctx, "trustory/claims", struct{}{}, codec.New())
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, time.Since(start) < time.Second)
+
+	// the abandoned query holds its slot until the node answers
+	assert.Equal(t, int64(1), a.InFlightQueries())
+	close(node.release)
+	res, err := a.QueryWithContext(context.Background(), "trustory/claims", struct{}{}, codec.New())
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("late"), res)
+}
+
+type storeNode struct {
+	path string
+	data []byte
+	res  []byte
+}
+
+func (n *storeNode) QueryWithData(path string, data []byte) ([]byte, int64, error) {
+	n.path, n.data = path, data
+	return n.res, 1, nil
+}
+
+func TestQuerySubspace(t *testing.T) {
+	pairs := []sdk.KVPair{{Key: []byte{0, 1}, Value: []byte("first")}, {Key: []byte{0, 2}, Value: []byte("second")}}
+	node := &storeNode{res: codec.New().MustMarshalBinaryLengthPrefixed(pairs)}
+	a := NewAPI(truCtx.TruAPIContext{}, MsgTypes{})
+	a.node = node
+
+	res, err := a.QuerySubspace(context.Background(), "trubank", []byte{0})
+	assert.NoError(t, err)
+	assert.Equal(t, "/store/trubank/subspace", node.path)
+	assert.Equal(t, []byte{0}, node.data)
+	assert.Equal(t, pairs, res)
+
+	node.res = nil
+	res, err = a.QuerySubspace(context.Background(), "trubank", []byte{0})
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
@@ -0,0 +1,56 @@
+package chttp
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// StreamWriter writes the body of a streamed response incrementally.
+// The response is sent with a 200 status once the first bytes are written.
+type StreamWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+// Header returns the response headers, which must be set before the first write
+func (s *StreamWriter) Header() http.Header {
+	return s.w.Header()
+}
+
+// Write implements io.Writer
+func (s *StreamWriter) Write(p []byte) (int, error) {
+	s.started = true
+	return s.w.Write(p)
+}
+
+// Flush sends the data written so far to the client
+func (s *StreamWriter) Flush() {
+	if flusher, ok := s.w.(http.Flusher); ok {
+		s.started = true
+		flusher.Flush()
+	}
+}
+
+// StreamHandler is an http.Handler that writes its response body through a StreamWriter.
+// It returns nil once the body is complete, or a Response describing the error otherwise.
+type StreamHandler func(*http.Request, *StreamWriter) Response
+
+// HandlerFunc wraps a `chttp.StreamHandler` in a standard `http` handler.
+// An error returned before anything was written is rendered like any other Response, with its
+// status code. Once streaming started the status can't change anymore, so the connection is
+// aborted instead, for the client to see an incomplete response rather than a truncated one.
+func (h StreamHandler) HandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := &StreamWriter{w: w}
+		res := h(r, s)
+		if res == nil {
+			return
+		}
+		if !s.started {
+			writeResponse(w, res)
+			return
+		}
+		fmt.Printf("stream %s aborted: %s\n", r.URL.Path, res.Error())
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,61 @@
+package chttp
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamHandler(t *testing.T) {
+	h := StreamHandler(func(r *http.Request, s *StreamWriter) Response {
+		if r.FormValue("token") != "secret" {
+			return SimpleErrorResponse(http.StatusUnauthorized, errors.New("Invalid token"))
+		}
+		s.Header().Set("Content-Type", "text/csv")
+		for _, line := range []string{"a,b\n", "1,2\n", "3,4\n"} {
+			_, err := s.Write([]byte(line))
+			if err != nil {
+				return SimpleErrorResponse(http.StatusInternalServerError, err)
+			}
+			s.Flush()
+			if r.FormValue("fail") == "true" {
+				return SimpleErrorResponse(http.StatusInternalServerError, errors.New("database is down"))
+			}
+		}
+		return nil
+	})
+	server := httptest.NewServer(h.HandlerFunc())
+	defer server.Close()
+
+	// the body is written in chunks
+	res, err := http.Get(server.URL + "?token=secret")
+	assert.NoError(t, err)
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, []string{"chunked"}, res.TransferEncoding)
+	assert.Equal(t, "text/csv", res.Header.Get("Content-Type"))
+	assert.Equal(t, "a,b\n1,2\n3,4\n", string(body))
+
+	// an error before streaming is rendered with its status code
+	res, err = http.Get(server.URL)
+	assert.NoError(t, err)
+	body, err = ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.JSONEq(t, `{"data":{},"error":"Invalid token"}`, string(body))
+
+	// an error mid-stream aborts the response
+	res, err = http.Get(server.URL + "?token=secret&fail=true")
+	assert.NoError(t, err)
+	body, err = ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	assert.Error(t, err)
+	assert.Equal(t, "a,b\n", string(body))
+}
@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	stdContext "context"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/TruStory/octopus/services/truapi/context"
 	"github.com/TruStory/octopus/services/truapi/truapi"
@@ -116,9 +119,25 @@ func startCmd(codec *codec.Codec) *cobra.Command {
 				os.Exit(1)
 			}
 			truAPI.RunLeaderboardScheduler(apiCtx)
+			truAPI.RunNotificationPruner(apiCtx)
+			truAPI.RunClaimSearchIndexer(apiCtx)
+
+			// shutting down gracefully on SIGINT/SIGTERM
+			ctx, cancel := stdContext.WithCancel(stdContext.Background())
+			defer cancel()
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+			go func() {
+				<-signals
+				log.Println("Shutting down API daemon...")
+				cancel()
+			}()
 
 			port := strconv.Itoa(apiCtx.Config.Host.Port)
-			log.Fatal(truAPI.ListenAndServe(net.JoinHostPort(apiCtx.Config.Host.Name, port)))
+			err = truAPI.ListenAndServe(ctx, net.JoinHostPort(apiCtx.Config.Host.Name, port))
+			if err != nil {
+				log.Fatal(err)
+			}
 
 			return err
 		},
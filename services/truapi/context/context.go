@@ -16,6 +16,12 @@ type AppConfig struct {
 	LiveDebateURL          string `mapstructure:"live-debate-url"`
 	SlackWebhook           string `mapstructure:"slack-webhook"`
 	RequestTruSlackWebhook string `mapstructure:"request-tru-slack-webhook"`
+	// AvatarSize is the size Twitter avatars are served in, e.g. 400x400 for high-DPI clients. Defaults to 200x200.
+	AvatarSize string `mapstructure:"avatar-size"`
+	// UsernameChangeCooldown is the minimum number of days between two username changes. Defaults to 30.
+	UsernameChangeCooldown int `mapstructure:"username-change-cooldown"`
+	// ReservedUsernames can't be claimed by regular users, on top of the ones related to the brand. Defaults to a built-in list.
+	ReservedUsernames []string `mapstructure:"reserved-usernames"`
 }
 
 // CookieConfig is the config for the cookie
@@ -49,11 +55,29 @@ type HostConfig struct {
 	HTTPSEnabled         bool     `mapstructure:"https-enabled"`
 	HTTPSDomainWhitelist []string `mapstructure:"https-domain-whitelist"`
 	HTTPSCacheDir        string   `mapstructure:"https-cache-dir"`
+	// MaxConcurrentQueries limits the in-flight queries to the chain node, zero means unlimited
+	MaxConcurrentQueries int `mapstructure:"max-concurrent-queries"`
+	// QueryMaxAttempts caps the attempts of a query failing to reach the chain node, zero means the default
+	QueryMaxAttempts int `mapstructure:"query-max-attempts"`
+	// QueryRetryBackoff is the delay in milliseconds before retrying such a query, doubled after every attempt
+	QueryRetryBackoff int `mapstructure:"query-retry-backoff"`
+}
+
+// HTTPClientConfig is the config for the HTTP client calling other services, zero values mean the defaults
+type HTTPClientConfig struct {
+	// Timeout is the timeout of a request in seconds
+	Timeout int `mapstructure:"timeout"`
+	// MaxIdleConnsPerHost is the number of idle connections kept open to each service
+	MaxIdleConnsPerHost int `mapstructure:"max-idle-conns-per-host"`
+	// IdleConnTimeout is the time in seconds an idle connection is kept open
+	IdleConnTimeout int `mapstructure:"idle-conn-timeout"`
 }
 
 // PushConfig is the config for push notifications
 type PushConfig struct {
-	EndpointURL string `mapstructure:"endpoint-url"`
+	EndpointURL             string `mapstructure:"endpoint-url"`
+	NotificationsBufferSize int    `mapstructure:"notifications-buffer-size"`
+	NotificationsOutboxSize int    `mapstructure:"notifications-outbox-size"`
 }
 
 // RegistrarConfig is the config for the registrar account that signs in users
@@ -70,6 +94,17 @@ type RewardBrokerConfig struct {
 	Pass string `mapstructure:"password"`
 }
 
+// RewardsConfig is the schedule of rewards given for completing the user journey
+type RewardsConfig struct {
+	// InviteBatchSize is the number of invites unlocked when the required journey steps are completed
+	InviteBatchSize int    `mapstructure:"invite-batch-size"`
+	StepSignUp      string `mapstructure:"step-signup"`
+	StepOneArgument string `mapstructure:"step-one-argument"`
+	StepFiveAgrees  string `mapstructure:"step-five-agrees"`
+	// AgreesRequired is the number of agrees to receive to complete the "received five agrees" step
+	AgreesRequired int `mapstructure:"agrees-required"`
+}
+
 // TwitterConfig is the config for Twitter
 type TwitterConfig struct {
 	APIKey        string `mapstructure:"api-key"`
@@ -91,6 +126,10 @@ type WebConfig struct {
 type CommunityConfig struct {
 	InactiveCommunities []string `mapstructure:"inactive-communities"`
 	BetaCommunities     []string `mapstructure:"beta-communities"`
+	// BetaLabel is appended to the name of beta communities, " (Beta)" by default
+	BetaLabel string `mapstructure:"beta-label"`
+	// MaxFeaturedClaims is the maximum number of claims featured at the same time in a community
+	MaxFeaturedClaims int `mapstructure:"max-featured-claims"`
 }
 
 // ParamsConfig is the config for off-chain params
@@ -99,6 +138,10 @@ type ParamsConfig struct {
 	CommentMaxLength      int `mapstructure:"comment-max-length"`
 	BlockInterval         int `mapstructure:"block-interval"`
 	TrendingFeedTimeDecay int `mapstructure:"trending-feed-time-decay"`
+	// CommentTreeMaxDepth is the maximum nesting depth returned by the comment tree
+	CommentTreeMaxDepth int `mapstructure:"comment-tree-max-depth"`
+	// CommentTreeMaxSize is the maximum number of comments returned by the comment tree
+	CommentTreeMaxSize int `mapstructure:"comment-tree-max-size"`
 }
 
 // AdminConfig is the config for the admin authentication
@@ -117,6 +160,16 @@ type AWSConfig struct {
 	S3Bucket     string `mapstructure:"aws-s3-bucket"`
 }
 
+// PostmanConfig is the config for the email templates
+type PostmanConfig struct {
+	// TemplatesDir holds templates overriding the embedded ones, with a sub directory per locale
+	TemplatesDir string `mapstructure:"templates-dir"`
+	// DefaultLocale is the locale used when a template is missing in the requested one
+	DefaultLocale string `mapstructure:"default-locale"`
+	// RequiredTemplates are the templates that must exist at startup besides the embedded ones
+	RequiredTemplates []string `mapstructure:"required-templates"`
+}
+
 // SpotlightConfig is the config for the Spotlight service
 type SpotlightConfig struct {
 	URL string `mapstructure:"spotlight-url"`
@@ -126,6 +179,8 @@ type SpotlightConfig struct {
 type DripperConfig struct {
 	Key       string                  `mapstructure:"dripper-api-key"`
 	Workflows []DripperWorkflowConfig `mapstructure:"dripper-workflows"`
+	// ResendCooldown is the minimum number of hours between onboarding email resends
+	ResendCooldown int `mapstructure:"dripper-resend-cooldown"`
 }
 
 // DripperWorkflowConfig represents a drip campaign's config
@@ -145,9 +200,62 @@ type LeaderboardConfig struct {
 	TopDisplaying int `mapstructure:"top-displaying"`
 }
 
+// NotificationsConfig represents notification events retention configuration
+type NotificationsConfig struct {
+	PruneEnabled bool `mapstructure:"prune-enabled"`
+	// RetentionDays is the number of days read notifications are kept for
+	RetentionDays int `mapstructure:"retention-days"`
+	// PruneInterval is the interval in hours for how often read notifications are pruned
+	PruneInterval int `mapstructure:"prune-interval"`
+	// PruneDryRun only logs how many notifications would be pruned
+	PruneDryRun bool `mapstructure:"prune-dry-run"`
+}
+
+// ClaimSearchConfig represents the claim search index configuration
+type ClaimSearchConfig struct {
+	// IndexInterval is the interval in minutes for how often claim bodies are mirrored into the search index
+	IndexInterval int `mapstructure:"index-interval"`
+}
+
+// FeatureFlagsConfig is the config for the client feature flags
+type FeatureFlagsConfig struct {
+	// Flags are the feature flags enabled or disabled for everyone
+	Flags map[string]bool `mapstructure:"flags"`
+	// GroupOverrides override the global flags per user group, keyed by the group number
+	GroupOverrides map[string]map[string]bool `mapstructure:"group-overrides"`
+}
+
+// LoginRateLimitConfig is the config for throttling login attempts
+type LoginRateLimitConfig struct {
+	// IPMaxAttempts is the number of login attempts allowed from a single IP per window
+	IPMaxAttempts int `mapstructure:"ip-max-attempts"`
+	// IdentifierMaxAttempts is the number of login attempts allowed against a single username or email per window
+	IdentifierMaxAttempts int `mapstructure:"identifier-max-attempts"`
+	// Window is the length of the rate limiting window in minutes
+	Window int `mapstructure:"window"`
+}
+
+// VerificationRateLimitConfig is the config for throttling verification emails
+type VerificationRateLimitConfig struct {
+	// Cooldown is the minimum time between two verification emails in minutes
+	Cooldown int `mapstructure:"cooldown"`
+	// MaxAttempts is the number of verification emails allowed per window
+	MaxAttempts int `mapstructure:"max-attempts"`
+	// Window is the length of the rate limiting window in days
+	Window int `mapstructure:"window"`
+}
+
+// ModerationConfig is the config for the automated moderation aids
+type ModerationConfig struct {
+	// Keywords are the words that flag a comment for review
+	Keywords []string `mapstructure:"keywords"`
+}
+
 // Metrics represents metrics configuration
 type MetricsConfig struct {
 	Secret string `mapstructure:"secret"`
+	// StakeExpirationGrace is the number of hours an ended stake still counts as pending
+	StakeExpirationGrace int `mapstructure:"stake-expiration-grace"`
 }
 
 // DefaultsConfig represents the default values
@@ -157,26 +265,35 @@ type DefaultsConfig struct {
 
 // Config contains all the config variables for the API server
 type Config struct {
-	ChainID      string `mapstructure:"chain-id"`
-	App          AppConfig
-	Cookie       CookieConfig
-	Database     DatabaseConfig
-	Flag         FlagConfig
-	Host         HostConfig
-	Push         PushConfig
-	Registrar    RegistrarConfig
-	RewardBroker RewardBrokerConfig
-	Twitter      TwitterConfig
-	Web          WebConfig
-	Community    CommunityConfig
-	Params       ParamsConfig
-	Admin        AdminConfig
-	AWS          AWSConfig
-	Spotlight    SpotlightConfig
-	Dripper      DripperConfig
-	Leaderboard  LeaderboardConfig
-	Defaults     DefaultsConfig
-	Metrics      MetricsConfig
+	ChainID               string `mapstructure:"chain-id"`
+	App                   AppConfig
+	Cookie                CookieConfig
+	Database              DatabaseConfig
+	Flag                  FlagConfig
+	Host                  HostConfig
+	HTTPClient            HTTPClientConfig
+	Push                  PushConfig
+	Registrar             RegistrarConfig
+	RewardBroker          RewardBrokerConfig
+	Twitter               TwitterConfig
+	Web                   WebConfig
+	Community             CommunityConfig
+	Params                ParamsConfig
+	Admin                 AdminConfig
+	AWS                   AWSConfig
+	Postman               PostmanConfig
+	Spotlight             SpotlightConfig
+	Dripper               DripperConfig
+	Leaderboard           LeaderboardConfig
+	Defaults              DefaultsConfig
+	Metrics               MetricsConfig
+	Rewards               RewardsConfig
+	Moderation            ModerationConfig
+	Notifications         NotificationsConfig
+	ClaimSearch           ClaimSearchConfig
+	FeatureFlags          FeatureFlagsConfig
+	LoginRateLimit        LoginRateLimitConfig
+	VerificationRateLimit VerificationRateLimitConfig
 }
 
 // TruAPIContext stores the config for the API and the underlying client context
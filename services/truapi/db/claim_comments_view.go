@@ -0,0 +1,41 @@
+package db
+
+import "time"
+
+// ClaimCommentsView records when a user last saw the comments of a claim
+type ClaimCommentsView struct {
+	Timestamps
+	ID         int64     `json:"id"`
+	Address    string    `json:"address"`
+	ClaimID    int64     `json:"claim_id"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// MarkClaimCommentsSeen records that a user has seen the comments of a claim up to now
+func (c *Client) MarkClaimCommentsSeen(claimID int64, address string) error {
+	view := &ClaimCommentsView{
+		Address:    address,
+		ClaimID:    claimID,
+		LastSeenAt: time.Now(),
+	}
+	_, err := c.Model(view).
+		OnConflict("ON CONSTRAINT claim_comments_views_one_per_user DO UPDATE").
+		Set("last_seen_at = EXCLUDED.last_seen_at").
+		Set("updated_at = EXCLUDED.updated_at").
+		Insert()
+	return err
+}
+
+// UnreadCommentsCount counts the comments of a claim created since the user last saw them,
+// or all of them if they never did. The user's own comments are never unread.
+func (c *Client) UnreadCommentsCount(claimID int64, address string) (int, error) {
+	return c.Model((*Comment)(nil)).
+		Where("claim_id = ?", claimID).
+		Where("creator <> ?", address).
+		Where("deleted_at IS NULL").
+		Where(`created_at > COALESCE(
+			(SELECT last_seen_at FROM claim_comments_views WHERE claim_id = ? AND address = ?),
+			'-infinity'
+		)`, claimID, address).
+		Count()
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnreadCommentsCount(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	claimID := int64(917601)
+	now := time.Now()
+	unread := func(address string) int {
+		t.Helper()
+		count, err := c.UnreadCommentsCount(claimID, address)
+		require.NoError(t, err)
+		return count
+	}
+
+	// no comments yet
+	assert.Equal(t, 0, unread("bob"))
+
+	createTestComment(t, c, claimID, "alice", now.Add(-3*time.Hour))
+	createTestComment(t, c, claimID, "bob", now.Add(-2*time.Hour))
+	createTestComment(t, c, claimID, "alice", now.Add(-time.Hour))
+	deleted := createTestComment(t, c, claimID, "alice", now.Add(-time.Hour))
+	require.NoError(t, c.DeleteComment(deleted.ID))
+	// on another claim
+	createTestComment(t, c, claimID+1, "alice", now.Add(-time.Hour))
+
+	// never seen, all the comments by others are unread
+	assert.Equal(t, 2, unread("bob"))
+	assert.Equal(t, 1, unread("alice"))
+
+	require.NoError(t, c.MarkClaimCommentsSeen(claimID, "bob"))
+	assert.Equal(t, 0, unread("bob"))
+	// others still haven't seen them
+	assert.Equal(t, 1, unread("alice"))
+
+	// a comment after the visit is unread, and marking seen again clears it
+	createTestComment(t, c, claimID, "alice", time.Now().Add(time.Minute))
+	assert.Equal(t, 1, unread("bob"))
+	require.NoError(t, c.MarkClaimCommentsSeen(claimID, "bob"))
+	assert.Equal(t, 0, unread("bob"))
+}
@@ -5,6 +5,7 @@ type ClaimImage struct {
 	ClaimID       uint64 `json:"claim_id"`
 	ClaimImageURL string `json:"claim_image_url"`
 	ClaimVideoURL string `json:"claim_video_url"`
+	HasMedia      bool   `json:"has_media"`
 	Timestamps
 }
 
@@ -34,6 +35,22 @@ func (c *Client) ClaimVideoURL(claimID uint64) (string, error) {
 func (c *Client) AddClaimImage(claimImageURL *ClaimImage) error {
 	_, err := c.Model(claimImageURL).OnConflict("(claim_id) DO UPDATE").
 		Set("claim_image_url = ?", claimImageURL.ClaimImageURL).
+		Set("has_media = ?", claimImageURL.HasMedia).
 		Insert()
 	return err
 }
+
+// ClaimIDsWithMedia returns the ids of all the claims that have a custom image or a video
+func (c *Client) ClaimIDsWithMedia() ([]int64, error) {
+	claimIDs := make([]int64, 0)
+	err := c.Model((*ClaimImage)(nil)).
+		Column("claim_id").
+		Where("has_media = TRUE").
+		WhereOr("claim_video_url <> ''").
+		Select(&claimIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return claimIDs, nil
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-pg/pg"
+)
+
+// maxClaimSearchTerms is the number of words of a search query that are matched, the rest being ignored
+const maxClaimSearchTerms = 10
+
+var claimSearchTermRegex = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// ClaimSearchDocument mirrors the body of a claim so that claims can be searched by full text.
+// Its tsvector lives in the document column, which is only ever written and matched in SQL.
+type ClaimSearchDocument struct {
+	Timestamps
+	ID          int64  `json:"id"`
+	ClaimID     int64  `json:"claim_id"`
+	CommunityID string `json:"community_id"`
+	Body        string `json:"body"`
+}
+
+// ClaimSearchResult is a claim matching a search, with how many of the query terms it contains
+// and its Postgres text search rank
+type ClaimSearchResult struct {
+	ClaimID      int64   `json:"claim_id"`
+	MatchedTerms int     `json:"matched_terms"`
+	Rank         float64 `json:"rank"`
+}
+
+// UpsertClaimSearchDocuments mirrors the bodies of claims, only rewriting the documents whose claim changed
+func (c *Client) UpsertClaimSearchDocuments(documents []ClaimSearchDocument) error {
+	return c.RunInTransaction(func(tx *pg.Tx) error {
+		for _, document := range documents {
+			_, err := tx.Exec(`
+				INSERT INTO claim_search_documents (claim_id, community_id, body, document)
+				VALUES (?0, ?1, ?2, to_tsvector('english', ?2))
+				ON CONFLICT (claim_id) DO UPDATE
+				SET
+					community_id = EXCLUDED.community_id,
+					body = EXCLUDED.body,
+					document = EXCLUDED.document,
+					updated_at = NOW()
+				WHERE
+					claim_search_documents.community_id <> EXCLUDED.community_id
+					OR claim_search_documents.body <> EXCLUDED.body
+			`, document.ClaimID, document.CommunityID, document.Body)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SearchClaims searches the claim bodies for any of the words of the query, words being stemmed and
+// stop words ignored. Claims are ranked by how many of the words they contain first, so that a claim
+// containing all of them comes before one containing only some, and then by their text search rank.
+func (c *Client) SearchClaims(query string, limit, offset int) ([]ClaimSearchResult, error) {
+	results := make([]ClaimSearchResult, 0)
+	terms := claimSearchTerms(query)
+	if len(terms) == 0 {
+		return results, nil
+	}
+
+	params := []interface{}{claimSearchTSQuery(terms), limit, offset}
+	matches := make([]string, 0, len(terms))
+	for _, term := range terms {
+		matches = append(matches, fmt.Sprintf("(document @@ to_tsquery('english', ?%d))::int", len(params)))
+		params = append(params, term)
+	}
+	sql := fmt.Sprintf(`
+		SELECT claim_id, %s AS matched_terms, ts_rank(document, query) AS rank
+		FROM claim_search_documents, to_tsquery('english', ?0) query
+		WHERE document @@ query AND deleted_at IS NULL
+		ORDER BY matched_terms DESC, rank DESC, claim_id DESC
+		LIMIT ?1 OFFSET ?2
+	`, strings.Join(matches, " + "))
+	_, err := c.Query(&results, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// claimSearchTerms splits a search query into its distinct lowercased words, dropping punctuation
+// so that the words can be safely combined into a tsquery
+func claimSearchTerms(query string) []string {
+	terms := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, term := range claimSearchTermRegex.FindAllString(strings.ToLower(query), -1) {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+		if len(terms) == maxClaimSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// claimSearchTSQuery matches documents containing any of the terms
+func claimSearchTSQuery(terms []string) string {
+	return strings.Join(terms, " | ")
+}
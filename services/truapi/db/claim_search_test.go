@@ -0,0 +1,55 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"bitcoin", "is", "money"}, claimSearchTerms("Bitcoin is... money!"))
+	assert.Equal(t, []string{"bitcoin", "s", "price"}, claimSearchTerms("bitcoin's price, BITCOIN"))
+	assert.Equal(t, []string{"café", "2020"}, claimSearchTerms("café & 2020 | !"))
+	assert.Empty(t, claimSearchTerms("  ' & | ! () "))
+	assert.Len(t, claimSearchTerms("a b c d e f g h i j k l"), maxClaimSearchTerms)
+
+	assert.Equal(t, "bitcoin | is | money", claimSearchTSQuery(claimSearchTerms("Bitcoin is money")))
+}
+
+func TestSearchClaims(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	require.NoError(t, c.UpsertClaimSearchDocuments([]ClaimSearchDocument{
+		{ClaimID: 917501, CommunityID: "crypto", Body: "Quokkas eat kelp near the glacier"},
+		// matches a single term, however often
+		{ClaimID: 917502, CommunityID: "crypto", Body: "Quokka quokka quokka quokka everywhere"},
+		{ClaimID: 917503, CommunityID: "crypto", Body: "Kelp forests grow fast"},
+		{ClaimID: 917504, CommunityID: "crypto", Body: "Nothing relevant here"},
+	}))
+
+	claimIDs := func(results []ClaimSearchResult) []int64 {
+		ids := make([]int64, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.ClaimID)
+		}
+		return ids
+	}
+
+	// words are stemmed, and the claim containing all of them ranks first
+	results, err := c.SearchClaims("quokka KELP glaciers", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{917501, 917502, 917503}, claimIDs(results))
+	assert.Equal(t, 3, results[0].MatchedTerms)
+	assert.Equal(t, 1, results[1].MatchedTerms)
+	assert.True(t, results[1].Rank > results[2].Rank)
+
+	results, err = c.SearchClaims("quokka KELP glaciers", 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{917502}, claimIDs(results))
+
+	results, err = c.SearchClaims("&|!", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"strings"
+)
+
+// MaxClaimTagLength is the longest tag, in characters, that can be stored
+const MaxClaimTagLength = 64
+
+// ClaimTag represents a label put on a claim by an editor
+type ClaimTag struct {
+	Timestamps
+	ID        int64  `json:"id"`
+	ClaimID   int64  `json:"claim_id"`
+	Tag       string `json:"tag"`
+	CreatedBy string `json:"created_by"`
+}
+
+// NormalizeClaimTag lowercases and trims a tag so each tag is only stored once per claim
+func NormalizeClaimTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// AddClaimTag tags a claim, ignoring tags the claim already has
+func (c *Client) AddClaimTag(claimID int64, tag, createdBy string) (*ClaimTag, error) {
+	claimTag := &ClaimTag{
+		ClaimID:   claimID,
+		Tag:       NormalizeClaimTag(tag),
+		CreatedBy: createdBy,
+	}
+	_, err := c.Model(claimTag).
+		OnConflict("ON CONSTRAINT claim_tags_no_duplicate_tag DO UPDATE").
+		Set("deleted_at = NULL").
+		Set("updated_at = NOW()").
+		Returning("*").
+		Insert()
+	if err != nil {
+		return nil, err
+	}
+	return claimTag, nil
+}
+
+// RemoveClaimTag removes a tag from a claim
+func (c *Client) RemoveClaimTag(claimID int64, tag string) error {
+	claimTag := new(ClaimTag)
+	_, err := c.Model(claimTag).
+		Where("claim_id = ?", claimID).
+		Where("tag = ?", NormalizeClaimTag(tag)).
+		Where("deleted_at IS NULL").
+		Set("deleted_at = NOW()").
+		Update()
+	return err
+}
+
+// TagsByClaimID returns the tags of a claim
+func (c *Client) TagsByClaimID(claimID int64) ([]ClaimTag, error) {
+	tags := make([]ClaimTag, 0)
+	err := c.Model(&tags).
+		Where("claim_id = ?", claimID).
+		Where("deleted_at IS NULL").
+		Order("tag ASC").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ClaimIDsByTag returns the ids of the claims with a given tag
+func (c *Client) ClaimIDsByTag(tag string) ([]int64, error) {
+	claimIDs := make([]int64, 0)
+	err := c.Model((*ClaimTag)(nil)).
+		Column("claim_id").
+		Where("tag = ?", NormalizeClaimTag(tag)).
+		Where("deleted_at IS NULL").
+		Select(&claimIDs)
+	if err != nil {
+		return nil, err
+	}
+	return claimIDs, nil
+}
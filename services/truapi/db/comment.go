@@ -1,6 +1,12 @@
 package db
 
-import "time"
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg"
+)
 
 // Comment represents a comment in the DB
 type Comment struct {
@@ -13,6 +19,8 @@ type Comment struct {
 	Body        string `json:"body"`
 	Creator     string `json:"creator"`
 	CommunityID string `json:"community_id"`
+	// IdempotencyKey is sent by clients to retry the creation of a comment without posting it twice
+	IdempotencyKey string `json:"-" graphql:"-"`
 }
 
 // ClaimLevelComments returns claim level comments, excluding argument level comments
@@ -79,6 +87,83 @@ func (c *Client) AddComment(comment *Comment) error {
 	return nil
 }
 
+// AddCommentOnce adds a new comment unless the creator already has one with the same idempotency key,
+// in which case the existing comment is loaded into comment and created is false
+func (c *Client) AddCommentOnce(comment *Comment) (created bool, err error) {
+	transformedBody, err := c.replaceUsernamesWithAddress(comment.Body)
+	if err != nil {
+		return false, err
+	}
+	comment.Body = transformedBody
+	result, err := c.Model(comment).
+		OnConflict("(creator, idempotency_key) WHERE idempotency_key IS NOT NULL AND deleted_at IS NULL DO NOTHING").
+		Insert()
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() > 0 {
+		return true, nil
+	}
+	existing, err := c.CommentByIdempotencyKey(comment.Creator, comment.IdempotencyKey)
+	if err != nil {
+		return false, err
+	}
+	if existing == nil {
+		return false, errors.New("comment with the idempotency key was deleted while being created")
+	}
+	*comment = *existing
+	return false, nil
+}
+
+// CommentByIdempotencyKey returns the comment, not deleted, created by the user with the idempotency key,
+// or nil when there's none
+func (c *Client) CommentByIdempotencyKey(creator, key string) (*Comment, error) {
+	comment := new(Comment)
+	err := c.Model(comment).
+		Where("creator = ?", creator).
+		Where("idempotency_key = ?", key).
+		Where("deleted_at IS NULL").
+		Select()
+	if err == pg.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// UpdateCommentBody replaces the body of a comment that isn't deleted and bumps its updated_at
+func (c *Client) UpdateCommentBody(id int64, body string) (*Comment, error) {
+	transformedBody, err := c.replaceUsernamesWithAddress(body)
+	if err != nil {
+		return nil, err
+	}
+	comment := new(Comment)
+	_, err = c.Model(comment).
+		Where("id = ?", id).
+		Where("deleted_at IS NULL").
+		Set("body = ?", transformedBody).
+		Set("updated_at = NOW()").
+		Returning("*").
+		Update()
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// DeleteComment soft deletes a comment, keeping it in threads for its replies
+func (c *Client) DeleteComment(id int64) error {
+	comment := new(Comment)
+	_, err := c.Model(comment).
+		Where("id = ?", id).
+		Where("deleted_at IS NULL").
+		Set("deleted_at = NOW()").
+		Update()
+	return err
+}
+
 // ClaimLevelCommentsParticipants gets the list of users participating on a claim thread.
 func (c *Client) ClaimLevelCommentsParticipants(claimID int64) ([]string, error) {
 	comments := make([]Comment, 0)
@@ -133,6 +218,70 @@ func (c *Client) replaceAddressesWithProfileURLsInComments(comments []Comment) (
 	return transformedComments, nil
 }
 
+// CommentsMatchingKeywords returns recent comments whose body contains any of the keywords, ignoring case
+func (c *Client) CommentsMatchingKeywords(keywords []string, from time.Time, limit int) ([]Comment, error) {
+	comments := make([]Comment, 0)
+	if len(keywords) == 0 {
+		return comments, nil
+	}
+	patterns := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		patterns = append(patterns, "%"+likeEscaper.Replace(keyword)+"%")
+	}
+	err := c.Model(&comments).
+		Where("body ILIKE ANY (?)", pg.Array(patterns)).
+		Where("created_at >= ?", from).
+		Where("deleted_at IS NULL").
+		Order("created_at DESC").
+		Limit(limit).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// CommenterActivity sums up the comments a user made on a claim during a window
+type CommenterActivity struct {
+	ClaimID  int64
+	Creator  string
+	Comments int64
+	// ElapsedSeconds is the sum over the comments of the seconds between the start of the window and their creation
+	ElapsedSeconds  float64
+	LastCommentedAt time.Time
+}
+
+// CommenterActivitySince returns the activity of each commenter on each claim between since and until,
+// counting the comments that aren't deleted
+func (c *Client) CommenterActivitySince(since, until time.Time) ([]CommenterActivity, error) {
+	activity := make([]CommenterActivity, 0)
+	query := `
+				SELECT
+					claim_id,
+					creator,
+					count(id) comments,
+					SUM(EXTRACT(EPOCH FROM LEAST(created_at, ?1) - ?0)) elapsed_seconds,
+					MAX(created_at) last_commented_at
+				FROM
+					comments
+				WHERE
+					created_at >= ?0 AND
+					deleted_at IS NULL
+				GROUP BY
+					claim_id,
+					creator
+				`
+
+	_, err := c.Query(&activity, query, since, until)
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// likeEscaper escapes the LIKE wildcards so keywords are matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // UserRepliesStats represets stats about user comments.
 type UserRepliesStats struct {
 	Address     string
@@ -162,3 +311,39 @@ func (c *Client) UserRepliesStats(date time.Time) ([]UserRepliesStats, error) {
 	}
 	return userRepliesStats, nil
 }
+
+// communityReplies is the number of comments in a community
+type communityReplies struct {
+	CommunityID string
+	Replies     int64
+}
+
+// CommentCountsByCommunity returns the number of comments created before the given date, by community
+func (c *Client) CommentCountsByCommunity(before time.Time) (map[string]int64, error) {
+	replies := make([]communityReplies, 0)
+	query := `
+				SELECT
+					community_id,
+					count(id) replies
+				FROM
+					comments
+				WHERE
+					created_at < ?
+				GROUP BY
+					community_id
+				`
+
+	_, err := c.Query(&replies, query, before)
+	if err != nil {
+		return nil, err
+	}
+	return repliesByCommunity(replies), nil
+}
+
+func repliesByCommunity(replies []communityReplies) map[string]int64 {
+	counts := make(map[string]int64, len(replies))
+	for _, r := range replies {
+		counts[r.CommunityID] += r.Replies
+	}
+	return counts
+}
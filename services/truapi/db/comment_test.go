@@ -0,0 +1,122 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepliesByCommunity(t *testing.T) {
+	replies := []communityReplies{
+		{CommunityID: "crypto", Replies: 3},
+		{CommunityID: "sports", Replies: 2},
+	}
+	assert.Equal(t, map[string]int64{"crypto": 3, "sports": 2}, repliesByCommunity(replies))
+	assert.Empty(t, repliesByCommunity(nil))
+}
+
+func TestCommentCountsByCommunity(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	before := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	claimID := int64(917350)
+	addComment := func(communityID string, createdAt time.Time) {
+		t.Helper()
+		comment := createTestComment(t, c, claimID, "carol", createdAt)
+		_, err := c.Exec("UPDATE comments SET community_id = ? WHERE id = ?", communityID, comment.ID)
+		assert.NoError(t, err)
+	}
+	addComment("countscrypto", before.Add(-2*time.Hour))
+	addComment("countscrypto", before.Add(-time.Hour))
+	addComment("countssports", before.Add(-time.Hour))
+	// created after the cutoff, so not counted
+	addComment("countscrypto", before.Add(time.Hour))
+	addComment("countsbooks", before.Add(time.Hour))
+
+	counts, err := c.CommentCountsByCommunity(before)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, counts["countscrypto"])
+	assert.EqualValues(t, 1, counts["countssports"])
+	assert.NotContains(t, counts, "countsbooks")
+}
+
+// createTestComment adds a comment on the claim, created at the given time
+func createTestComment(t *testing.T, c *Client, claimID int64, creator string, createdAt time.Time) *Comment {
+	t.Helper()
+	comment := &Comment{
+		ClaimID:     claimID,
+		Body:        "a comment",
+		Creator:     creator,
+		CommunityID: "crypto",
+		Timestamps:  Timestamps{CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+	assert.NoError(t, c.Add(comment))
+	return comment
+}
+
+func TestCommenterActivitySince(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	since := now.Add(-72 * time.Hour)
+	claimID := int64(917301)
+	createTestComment(t, c, claimID, "carol", now.Add(-5*time.Hour))
+	createTestComment(t, c, claimID, "carol", now.Add(-4*time.Hour))
+	createTestComment(t, c, claimID, "dave", now.Add(-30*time.Hour))
+	// before the window
+	createTestComment(t, c, claimID, "dave", now.Add(-100*time.Hour))
+	deleted := createTestComment(t, c, claimID, "erin", now.Add(-time.Hour))
+	assert.NoError(t, c.DeleteComment(deleted.ID))
+
+	activity, err := c.CommenterActivitySince(since, now)
+	assert.NoError(t, err)
+	byCreator := make(map[string]CommenterActivity)
+	for _, a := range activity {
+		if a.ClaimID == claimID {
+			byCreator[a.Creator] = a
+		}
+	}
+	assert.Len(t, byCreator, 2)
+	assert.Equal(t, int64(2), byCreator["carol"].Comments)
+	assert.InDelta(t, (67+68)*time.Hour.Seconds(), byCreator["carol"].ElapsedSeconds, 1)
+	assert.True(t, now.Add(-4*time.Hour).Equal(byCreator["carol"].LastCommentedAt))
+	assert.Equal(t, int64(1), byCreator["dave"].Comments)
+	assert.InDelta(t, 42*time.Hour.Seconds(), byCreator["dave"].ElapsedSeconds, 1)
+}
+
+func TestAddCommentOnce(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	comment := &Comment{ClaimID: 917302, Body: "a comment", Creator: "carol", CommunityID: "crypto", IdempotencyKey: "retry-1"}
+	created, err := c.AddCommentOnce(comment)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	// a retry gets the same comment back instead of failing on the unique index
+	retried := &Comment{ClaimID: 917302, Body: "a comment", Creator: "carol", CommunityID: "crypto", IdempotencyKey: "retry-1"}
+	created, err = c.AddCommentOnce(retried)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, comment.ID, retried.ID)
+
+	// keys are scoped per user
+	other := &Comment{ClaimID: 917302, Body: "a comment", Creator: "dave", CommunityID: "crypto", IdempotencyKey: "retry-1"}
+	created, err = c.AddCommentOnce(other)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	// a deleted comment doesn't hold on to its key
+	assert.NoError(t, c.DeleteComment(comment.ID))
+	existing, err := c.CommentByIdempotencyKey("carol", "retry-1")
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+	recreated := &Comment{ClaimID: 917302, Body: "a comment", Creator: "carol", CommunityID: "crypto", IdempotencyKey: "retry-1"}
+	created, err = c.AddCommentOnce(recreated)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.NotEqual(t, comment.ID, recreated.ID)
+}
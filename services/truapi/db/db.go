@@ -1,18 +1,22 @@
 package db
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
 	"os"
 
 	truCtx "github.com/TruStory/octopus/services/truapi/context"
 	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
 )
 
 // Client is a Postgres client.
-// It wraps a pool of Postgres DB connections.
+// It wraps a pool of Postgres DB connections, or a transaction when created by WithTx.
 type Client struct {
-	*pg.DB
+	orm.DB
+	pool   *pg.DB
+	tx     *pg.Tx
 	config truCtx.Config
 }
 
@@ -38,7 +42,44 @@ func NewDBClient(config truCtx.Config) *Client {
 		db.AddQueryHook(dbLogger{})
 	}
 
-	return &Client{db, config}
+	return &Client{DB: db, pool: db, config: config}
+}
+
+// WithTx runs fn with a client bound to a transaction, committing when fn succeeds and rolling back otherwise.
+// Clients already bound to a transaction run fn in a savepoint of that transaction, so only the changes
+// made by fn are rolled back when it fails.
+func (c *Client) WithTx(fn func(*Client) error) error {
+	if c.tx != nil {
+		return c.withSavepoint(fn)
+	}
+	return c.pool.RunInTransaction(func(tx *pg.Tx) error {
+		return fn(&Client{DB: tx, pool: c.pool, tx: tx, config: c.config})
+	})
+}
+
+func (c *Client) withSavepoint(fn func(*Client) error) error {
+	_, err := c.tx.Exec("SAVEPOINT with_tx")
+	if err != nil {
+		return err
+	}
+	err = fn(c)
+	if err != nil {
+		_, rollbackErr := c.tx.Exec("ROLLBACK TO SAVEPOINT with_tx")
+		if rollbackErr != nil {
+			fmt.Println("rollback to savepoint err: ", rollbackErr)
+		}
+		return err
+	}
+	_, err = c.tx.Exec("RELEASE SAVEPOINT with_tx")
+	return err
+}
+
+// RunInTransaction runs fn in a transaction, reusing the client's transaction if it has one
+func (c *Client) RunInTransaction(fn func(*pg.Tx) error) error {
+	if c.tx != nil {
+		return fn(c.tx)
+	}
+	return c.pool.RunInTransaction(fn)
 }
 
 // GenericMutations write to the database
@@ -68,6 +109,7 @@ type GenericQueries interface {
 	Count(model interface{}) (int, error)
 	Find(model interface{}) error
 	FindAll(models interface{}) error
+	Ping(ctx context.Context) error
 }
 
 // Count returns the count of the model
@@ -87,6 +129,12 @@ func (c *Client) FindAll(models interface{}) error {
 	return c.Model(models).Select()
 }
 
+// Ping checks that the database can be reached
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.ExecContext(ctx, "SELECT 1")
+	return err
+}
+
 // Misc functions
 func generateCryptoSafeRandomBytes(strength int) ([]byte, error) {
 	random := make([]byte, strength)
@@ -0,0 +1,28 @@
+package db
+
+import "strings"
+
+// canonicalEmailDomains maps the domains of providers ignoring dots in the local part to their canonical domain
+var canonicalEmailDomains = map[string]string{
+	"gmail.com":      "gmail.com",
+	"googlemail.com": "gmail.com",
+}
+
+// CanonicalEmail returns the form of an email shared by all its aliases: lowercased,
+// without a +suffix and, for providers ignoring them, without dots in the local part
+func CanonicalEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+	if canonicalDomain, ok := canonicalEmailDomains[domain]; ok {
+		local = strings.Replace(local, ".", "", -1)
+		domain = canonicalDomain
+	}
+	return local + "@" + domain
+}
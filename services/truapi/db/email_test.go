@@ -0,0 +1,22 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalEmail(t *testing.T) {
+	canonical := CanonicalEmail("user@gmail.com")
+	assert.Equal(t, "user@gmail.com", canonical)
+	assert.Equal(t, canonical, CanonicalEmail("user+foo@gmail.com"))
+	assert.Equal(t, canonical, CanonicalEmail("u.ser@gmail.com"))
+	assert.Equal(t, canonical, CanonicalEmail(" U.Ser+1@GoogleMail.com "))
+
+	// dots are only meaningless for known providers
+	assert.Equal(t, "first.last@trustory.io", CanonicalEmail("First.Last+news@trustory.io"))
+	assert.NotEqual(t, CanonicalEmail("u.ser@trustory.io"), CanonicalEmail("user@trustory.io"))
+
+	assert.Equal(t, "", CanonicalEmail(""))
+	assert.Equal(t, "+user@trustory.io", CanonicalEmail("+user@trustory.io"))
+}
@@ -7,4 +7,15 @@ var (
 	ErrInvalidAddress            = errors.New("invalid address")
 	ErrFollowAtLeastOneCommunity = errors.New("should follow at least one community")
 	ErrNotFollowingCommunity     = errors.New("user doesn't follow community")
+	ErrTooManyFeaturedClaims     = errors.New("too many featured claims in this community for that period")
 )
+
+// ValidationError is returned when a field of a request doesn't pass validation
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"time"
+)
+
+// FeaturedClaim represents a claim pinned to a community for a period of time
+type FeaturedClaim struct {
+	Timestamps
+	ID          int64     `json:"id"`
+	ClaimID     int64     `json:"claim_id"`
+	CommunityID string    `json:"community_id"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	SetBy       string    `json:"set_by"`
+}
+
+// SetFeaturedClaim features a claim, unless the community already has maxPerCommunity
+// features overlapping the same period. Features of a community are set one at a time,
+// so that concurrent requests can't both pass the count.
+func (c *Client) SetFeaturedClaim(featuredClaim *FeaturedClaim, maxPerCommunity int) error {
+	return c.WithTx(func(tx *Client) error {
+		_, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('featured_claims'), hashtext(?))`, featuredClaim.CommunityID)
+		if err != nil {
+			return err
+		}
+		overlapping, err := tx.Model((*FeaturedClaim)(nil)).
+			Where("community_id = ?", featuredClaim.CommunityID).
+			Where("starts_at < ?", featuredClaim.EndsAt).
+			Where("ends_at > ?", featuredClaim.StartsAt).
+			Where("deleted_at IS NULL").
+			Count()
+		if err != nil {
+			return err
+		}
+		if overlapping >= maxPerCommunity {
+			return ErrTooManyFeaturedClaims
+		}
+		return tx.Add(featuredClaim)
+	})
+}
+
+// ActiveFeaturedClaims returns the claims currently featured in a community
+func (c *Client) ActiveFeaturedClaims(communityID string) ([]FeaturedClaim, error) {
+	featuredClaims := make([]FeaturedClaim, 0)
+	now := time.Now()
+	err := c.Model(&featuredClaims).
+		Where("community_id = ?", communityID).
+		Where("starts_at <= ?", now).
+		Where("ends_at > ?", now).
+		Where("deleted_at IS NULL").
+		Order("starts_at DESC").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return featuredClaims, nil
+}
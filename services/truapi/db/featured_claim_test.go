@@ -0,0 +1,31 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFeaturedClaim(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	startsAt := time.Date(2019, 9, 12, 0, 0, 0, 0, time.UTC)
+	feature := func(claimID int64, communityID string, startsAt time.Time) error {
+		return c.SetFeaturedClaim(&FeaturedClaim{
+			ClaimID:     claimID,
+			CommunityID: communityID,
+			StartsAt:    startsAt,
+			EndsAt:      startsAt.Add(24 * time.Hour),
+			SetBy:       "admin",
+		}, 2)
+	}
+
+	assert.NoError(t, feature(1, "featured-test", startsAt))
+	assert.NoError(t, feature(2, "featured-test", startsAt.Add(time.Hour)))
+	assert.Equal(t, ErrTooManyFeaturedClaims, feature(3, "featured-test", startsAt.Add(2*time.Hour)))
+	// other communities and periods have their own limit
+	assert.NoError(t, feature(3, "featured-other", startsAt))
+	assert.NoError(t, feature(3, "featured-test", startsAt.Add(48*time.Hour)))
+}
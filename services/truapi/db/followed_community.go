@@ -2,6 +2,8 @@ package db
 
 import (
 	"time"
+
+	"github.com/go-pg/pg"
 )
 
 // FollowedCommunity represents a community that an user follows.
@@ -92,3 +94,93 @@ func (c *Client) FollowsCommunity(address, communityID string) (bool, error) {
 	}
 	return true, nil
 }
+
+// CommunityFollowers returns the users following a community, earliest followers first.
+// Soft-deleted users are excluded.
+func (c *Client) CommunityFollowers(communityID string) ([]User, error) {
+	followedCommunities := make([]FollowedCommunity, 0)
+	err := c.Model(&followedCommunities).
+		Where("community_id = ?", communityID).
+		Order("following_since ASC", "id ASC").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	if len(followedCommunities) == 0 {
+		return make([]User, 0), nil
+	}
+	addresses := make([]string, 0, len(followedCommunities))
+	for _, f := range followedCommunities {
+		addresses = append(addresses, f.Address)
+	}
+	users := make([]User, 0)
+	err = c.Model(&users).Where("address IN (?)", pg.In(addresses)).Select()
+	if err != nil {
+		return nil, err
+	}
+	return activeFollowers(followedCommunities, users), nil
+}
+
+// CommunityFollowersCount counts the users following a community, excluding soft-deleted users
+func (c *Client) CommunityFollowersCount(communityID string) (int, error) {
+	followers, err := c.CommunityFollowers(communityID)
+	if err != nil {
+		return 0, err
+	}
+	return len(followers), nil
+}
+
+// activeFollowers returns the users of the follows in the same order, skipping deleted and unknown users
+func activeFollowers(followedCommunities []FollowedCommunity, users []User) []User {
+	usersByAddress := make(map[string]User, len(users))
+	for _, user := range users {
+		usersByAddress[user.Address] = user
+	}
+	followers := make([]User, 0, len(followedCommunities))
+	for _, f := range followedCommunities {
+		user, ok := usersByAddress[f.Address]
+		if !ok || user.DeletedAt != nil {
+			continue
+		}
+		followers = append(followers, user)
+	}
+	return followers
+}
+
+// RecentCommunityMembers returns users that joined a community since a given time, most recent first.
+// A user joins a community when they first follow it or first participate in it.
+func (c *Client) RecentCommunityMembers(communityID string, since time.Time, limit int) ([]User, error) {
+	users := make([]User, 0)
+	query := `
+		SELECT users.*
+		FROM users
+		INNER JOIN (
+			SELECT address, MIN(joined_at) joined_at
+			FROM (
+				SELECT address, following_since joined_at
+				FROM followed_communities
+				WHERE community_id = ?0
+				UNION ALL
+				SELECT creator address, created_at joined_at
+				FROM comments
+				WHERE community_id = ?0 AND deleted_at IS NULL
+				UNION ALL
+				SELECT address, date joined_at
+				FROM leaderboard_user_metrics
+				WHERE community_id = ?0 AND (earned > 0 OR agrees_received > 0 OR agrees_given > 0)
+			) participations
+			GROUP BY address
+		) members ON members.address = users.address
+		WHERE
+			members.joined_at >= ?1
+			AND users.blacklisted_at IS NULL
+			AND users.deleted_at IS NULL
+		ORDER BY members.joined_at DESC
+		LIMIT ?2
+	`
+	_, err := c.Query(&users, query, communityID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
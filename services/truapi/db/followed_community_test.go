@@ -0,0 +1,36 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveFollowers(t *testing.T) {
+	deletedAt := time.Now()
+	follows := []FollowedCommunity{
+		{Address: "carol", CommunityID: "crypto"},
+		{Address: "alice", CommunityID: "crypto"},
+		{Address: "dave", CommunityID: "crypto"},
+		{Address: "bob", CommunityID: "crypto"},
+		// no user with this address
+		{Address: "eve", CommunityID: "crypto"},
+	}
+	users := []User{
+		{Address: "alice", Username: "alice"},
+		{Address: "bob", Username: "bob"},
+		{Address: "carol", Username: "carol"},
+		{Address: "dave", Username: "dave", Timestamps: Timestamps{DeletedAt: &deletedAt}},
+	}
+
+	followers := activeFollowers(follows, users)
+	assert.Len(t, followers, 3)
+	usernames := make([]string, 0)
+	for _, f := range followers {
+		usernames = append(usernames, f.Username)
+	}
+	// in follow order, without the deleted user
+	assert.Equal(t, []string{"carol", "alice", "bob"}, usernames)
+	assert.Empty(t, activeFollowers(nil, users))
+}
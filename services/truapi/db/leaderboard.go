@@ -17,7 +17,9 @@ type LeaderboardProcessedDate struct {
 }
 
 type LeaderboardTopUser struct {
-	Address        string
+	Address string
+	// CommunityID is the community of a community leaderboard, empty in the global one
+	CommunityID    string
 	Earned         int64
 	AgreesReceived int64
 	AgreesGiven    int64
@@ -98,6 +100,30 @@ func (c *Client) Leaderboard(since time.Time, sortBy string, limit int, excluded
 		q = q.Where("date >= ?", since)
 	}
 	q = q.Group("address").
+		Having(fmt.Sprintf("SUM(%s) > 0", sortBy)).
+		OrderExpr(fmt.Sprintf("SUM(%s) DESC", sortBy)).
+		Limit(limit)
+	err := q.Select(&topUsers)
+	if err != nil {
+		return topUsers, err
+	}
+	return topUsers, nil
+}
+
+// CommunityLeaderboard returns the top users of a community, by their metrics within that community only
+func (c *Client) CommunityLeaderboard(communityID string, since time.Time, sortBy string, limit int) ([]LeaderboardTopUser, error) {
+	topUsers := make([]LeaderboardTopUser, 0)
+	q := c.Model((*LeaderboardUserMetric)(nil)).
+		Column("address", "community_id").
+		ColumnExpr("SUM(earned) earned").
+		ColumnExpr("SUM(agrees_received) agrees_received").
+		ColumnExpr("SUM(agrees_given) agrees_given").
+		Where("community_id = ?", communityID)
+	if !since.IsZero() {
+		q = q.Where("date >= ?", since)
+	}
+	q = q.Group("address", "community_id").
+		Having(fmt.Sprintf("SUM(%s) > 0", sortBy)).
 		OrderExpr(fmt.Sprintf("SUM(%s) DESC", sortBy)).
 		Limit(limit)
 	err := q.Select(&topUsers)
@@ -4,82 +4,126 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"regexp"
 	"strings"
-
-	"github.com/gernest/mention"
 )
 
 // replace @cosmosaddr with profile link [@username](https://app.trustory.io/profile/cosmosaddr)
 func (c *Client) replaceAddressesWithProfileURLs(body string) (string, error) {
 	profileURLPrefix := path.Join(c.config.Host.Domain, "profile")
-	profileURLsByAddress, err := c.mapAddressesToProfileURLs(body, profileURLPrefix)
-	if err != nil {
-		return "", err
-	}
-	for address, profileURL := range profileURLsByAddress {
-		body = strings.ReplaceAll(body, fmt.Sprintf("@%s", address), profileURL)
-	}
-
-	return body, nil
+	return rewriteMentions(body, addressToProfileLink(c.UserByAddress, profileURLPrefix, c.config.Host.HTTPSEnabled))
 }
 
-func (c *Client) mapAddressesToProfileURLs(body string, profileURLPrefix string) (map[string]string, error) {
-	profileURLsByAddress := map[string]string{}
-	addresses := parseMentions(body)
+// addressToProfileLink rewrites an address mention into a markdown link to the profile of its user,
+// leaving mentions of unknown addresses untouched
+func addressToProfileLink(userByAddress func(string) (*User, error), profileURLPrefix string, httpsEnabled bool) func(string) (string, error) {
 	// TODO : query multiple users instead of one by one
 	// https://github.com/TruStory/octopus/issues/315
-	for _, address := range addresses {
-		user, err := c.UserByAddress(address)
+	return func(address string) (string, error) {
+		user, err := userByAddress(address)
 		if err != nil {
-			return profileURLsByAddress, err
+			return "", err
 		}
 		if user == nil {
-			profileURLsByAddress[address] = address
-			continue
+			return "@" + address, nil
 		}
 		profileURLString := path.Join(profileURLPrefix, user.Address)
 		profileURL, err := url.Parse(profileURLString)
 		if err != nil {
-			return profileURLsByAddress, err
+			return "", err
 		}
 
 		httpPrefix := "http://"
-		if c.config.Host.HTTPSEnabled {
+		if httpsEnabled {
 			httpPrefix = "https://"
 		}
-		markdownProfileURL := fmt.Sprintf("[@%s](%s%s)", user.Username, httpPrefix, profileURL)
-		profileURLsByAddress[address] = markdownProfileURL
+		return fmt.Sprintf("[@%s](%s%s)", user.Username, httpPrefix, profileURL), nil
 	}
-
-	return profileURLsByAddress, nil
 }
 
-// extract @mentions from text and return as slice
-func parseMentions(body string) []string {
-	terminators := []rune(" \n\r.,():!?'\"")
-	return mention.GetTagsAsUniqueStrings('@', body, terminators...)
+// mentionRegex matches an @mention at the start of the text or after a character that can't be part of one,
+// so that emails aren't taken for mentions. The mention ends at the first character not allowed in usernames.
+var mentionRegex = regexp.MustCompile(`(?:^|[^\w@-])@([\w-]+)`)
+
+// ParseMentions extracts the unique @mentions from the text, in order of appearance
+func ParseMentions(body string) []string {
+	mentions := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, match := range mentionRegex.FindAllStringSubmatch(body, -1) {
+		if seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		mentions = append(mentions, match[1])
+	}
+	return mentions
 }
 
 // replace @usernames with @cosmosaddr
 func (c *Client) replaceUsernamesWithAddress(body string) (string, error) {
-	addressByUsername := map[string]string{}
-	usernames := parseMentions(body)
-	for _, username := range usernames {
-		user, err := c.UserByUsername(username)
+	return rewriteMentions(body, usernameToAddress(c.UserByUsername))
+}
+
+// usernameToAddress rewrites a username mention into a mention of the address of its user,
+// leaving mentions of unknown users, or of users without an address yet, untouched
+func usernameToAddress(userByUsername func(string) (*User, error)) func(string) (string, error) {
+	return func(username string) (string, error) {
+		user, err := userByUsername(username)
 		if err != nil {
-			return body, err
+			return "", err
 		}
-		if user == nil {
-			addressByUsername[username] = username
-		} else {
-			addressByUsername[username] = user.Address
+		if user == nil || user.Address == "" {
+			return "@" + username, nil
 		}
+		return "@" + user.Address, nil
 	}
-	for username, address := range addressByUsername {
-		body = strings.ReplaceAll(body, "@"+username, "@"+address)
+}
+
+// rewriteMentions replaces every @mention in the body with what rewrite returns for it.
+// Mentions are replaced in place, so that rewriting @bob doesn't touch @bobby.
+func rewriteMentions(body string, rewrite func(mention string) (string, error)) (string, error) {
+	rewritten := make(map[string]string)
+	for _, mention := range ParseMentions(body) {
+		replacement, err := rewrite(mention)
+		if err != nil {
+			return body, err
+		}
+		rewritten[mention] = replacement
 	}
+	return mentionRegex.ReplaceAllStringFunc(body, func(match string) string {
+		// the match may start with the character preceding the @
+		at := strings.IndexByte(match, '@')
+		return match[:at] + rewritten[match[at+1:]]
+	}), nil
+}
+
+// MentionedUserIDs returns the IDs of the users mentioned in the body, either by address or by username,
+// ignoring mentions that don't match any user
+func (c *Client) MentionedUserIDs(body string) ([]int64, error) {
+	return mentionedUserIDs(body, c.UserByAddress, c.UserByUsername)
+}
 
-	return body, nil
+func mentionedUserIDs(body string, userByAddress, userByUsername func(string) (*User, error)) ([]int64, error) {
+	ids := make([]int64, 0)
+	seen := make(map[int64]bool)
+	for _, mention := range ParseMentions(body) {
+		user, err := userByAddress(mention)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			user, err = userByUsername(mention)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if user == nil || seen[user.ID] {
+			continue
+		}
+		seen[user.ID] = true
+		ids = append(ids, user.ID)
+	}
+	return ids, nil
 }
 
 // TranslateToCosmosMentions translates from users mentions to cosmos addresses mentions.
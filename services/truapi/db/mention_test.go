@@ -8,18 +8,100 @@ import (
 
 func TestMentionParse(t *testing.T) {
 	text := "@d-truth, @shanev you hear me?"
-	mentions := parseMentions(text)
+	mentions := ParseMentions(text)
 	assert.Equal(t, 2, len(mentions))
 }
 
 func TestMentionWithNewLine(t *testing.T) {
 	testComment := "@d-truth\n@shanev you hear me?"
-	mentions := parseMentions(testComment)
+	mentions := ParseMentions(testComment)
 	assert.Equal(t, 2, len(mentions))
 }
 
 func TestMentionWithPunctuations(t *testing.T) {
 	testComment := "@user-a. (@shanev you hear me @userb?) @user-c, @userd! and @userX:"
-	mentions := parseMentions(testComment)
+	mentions := ParseMentions(testComment)
 	assert.Equal(t, 6, len(mentions))
 }
+
+func TestMentionTerminators(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected []string
+	}{
+		{"thanks @alice, that helped", []string{"alice"}},
+		{"agreed with @bob.", []string{"bob"}},
+		{"go @d-truth!", []string{"d-truth"}},
+		{"cc @shane_v", []string{"shane_v"}},
+		{"@carol;@dave", []string{"carol", "dave"}},
+		{"[@erin](https://trustory.io) and @frank's point", []string{"erin", "frank"}},
+		{"@alice @alice", []string{"alice"}},
+		{"mail me at shane@trustory.io", []string{}},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, ParseMentions(tc.text), tc.text)
+	}
+}
+
+func TestTranslateNativeUserMentions(t *testing.T) {
+	// a user who signed up with email and password, without any connected Twitter account
+	native := &User{Username: "bob", Address: "cosmos1bobaddress"}
+	users := []*User{native, {Username: "bobby", Address: "cosmos1bobbyaddress"}}
+	userByUsername := func(username string) (*User, error) {
+		for _, u := range users {
+			if u.Username == username {
+				return u, nil
+			}
+		}
+		return nil, nil
+	}
+	userByAddress := func(address string) (*User, error) {
+		for _, u := range users {
+			if u.Address == address {
+				return u, nil
+			}
+		}
+		return nil, nil
+	}
+
+	written, err := rewriteMentions("@bob, @bobby and @nobody", usernameToAddress(userByUsername))
+	assert.NoError(t, err)
+	assert.Equal(t, "@cosmos1bobaddress, @cosmos1bobbyaddress and @nobody", written)
+
+	read, err := rewriteMentions(written, addressToProfileLink(userByAddress, "app.trustory.io/profile", true))
+	assert.NoError(t, err)
+	assert.Equal(t, "[@bob](https://app.trustory.io/profile/cosmos1bobaddress), "+
+		"[@bobby](https://app.trustory.io/profile/cosmos1bobbyaddress) and @nobody", read)
+}
+
+func TestMentionedUserIDs(t *testing.T) {
+	users := []*User{
+		{ID: 1, Username: "alice", Address: "cosmos1aliceaddress"},
+		{ID: 2, Username: "bob", Address: "cosmos1bobaddress"},
+	}
+	userByAddress := func(address string) (*User, error) {
+		for _, u := range users {
+			if u.Address == address {
+				return u, nil
+			}
+		}
+		return nil, nil
+	}
+	userByUsername := func(username string) (*User, error) {
+		for _, u := range users {
+			if u.Username == username {
+				return u, nil
+			}
+		}
+		return nil, nil
+	}
+
+	ids, err := mentionedUserIDs("@bob @cosmos1aliceaddress, @alice and @bob again, @nobody @cosmos1unknown",
+		userByAddress, userByUsername)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, ids)
+
+	ids, err = mentionedUserIDs("no mentions here", userByAddress, userByUsername)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+}
@@ -23,12 +23,22 @@ type Mutations interface {
 	UpsertFlaggedStory(flaggedStory *FlaggedStory) error
 	MarkAllNotificationEventsAsReadByAddress(addr string) error
 	MarkAllNotificationEventsAsSeenByAddress(addr string) error
+	PruneNotificationEvents(olderThan time.Time) (int, error)
+	RecordNotificationFailure(failure *NotificationFailure) error
 	MarkCommentThreadNotificationsAsRead(addr string, claimID int64) error
 	MarkArgumentCommentThreadNotificationsAsRead(addr string, claimID int64, argumentID int64, elementID int64) error
 	MarkArgumentNotificationAsRead(addr string, claimID int64, argumentID int64) error
 	AddComment(comment *Comment) error
+	AddCommentOnce(comment *Comment) (bool, error)
+	UpdateCommentBody(id int64, body string) (*Comment, error)
+	DeleteComment(id int64) error
+	MarkClaimCommentsSeen(claimID int64, address string) error
 	AddQuestion(question *Question) error
 	DeleteQuestion(ID int64) error
+	AddClaimTag(claimID int64, tag, createdBy string) (*ClaimTag, error)
+	RemoveClaimTag(claimID int64, tag string) error
+	SetFeaturedClaim(featuredClaim *FeaturedClaim, maxPerCommunity int) error
+	UpsertClaimSearchDocuments(documents []ClaimSearchDocument) error
 	AddInvite(invite *Invite) error
 	ReactOnReactionable(addr string, reaction ReactionType, reactionable Reactionable) error
 	UnreactByAddressAndID(addr string, id int64) error
@@ -36,19 +46,30 @@ type Mutations interface {
 	DeleteClaimOfTheDayID(communityID string) error
 	AddClaimImage(claimImage *ClaimImage) error
 	AddUser(user *User) error
+	MergeUsers(primaryID, secondaryID int64) error
 	ApproveUserByID(id int64) error
 	RejectUserByID(id int64) error
 	RegisterUser(user *User, referrerCode, defaultAvatarURL string) error
 	BlacklistUser(id int64) error
 	UnblacklistUser(id int64) error
+	DeleteUser(id int64) error
+	SetUserGroup(id int64, group UserGroup) (UserGroup, error)
+	AnonymizeUser(id int64, avatarURL string) error
+	DeleteAccount(id int64, avatarURL string) error
+	RestoreUser(id int64) error
 	VerifyUser(id int64, token string) error
+	InitiateEmailChange(id int64, newEmail string) (string, error)
+	ConfirmEmailChange(id int64, token string) error
+	BulkVerifyUsers(ids []int64) (int, error)
 	TouchLastAuthenticatedAt(id int64) error
 	AddAddressToUser(id int64, address string) error
 	UpdatePassword(id int64, password *UserPassword) error
 	ResetPassword(id int64, password string) error
-	UpdateProfile(id int64, profile *UserProfile) error
+	ResetPasswordWithToken(prt *PasswordResetToken, password string) error
+	UpdateProfile(id int64, profile *UserProfile, usernameCooldown time.Duration) error
 	SetUserCredentials(id int64, credentials *UserCredentials) error
 	SetUserMeta(id int64, userMeta *UserMeta) error
+	SetNotificationPreferences(id int64, preferences NotificationPreferences) error
 	IssueResetToken(userID int64) (*PasswordResetToken, error)
 	UseResetToken(prt *PasswordResetToken) error
 	UpsertConnectedAccount(connectedAccount *ConnectedAccount) error
@@ -57,45 +78,70 @@ type Mutations interface {
 	FollowedCommunities(address string) ([]FollowedCommunity, error)
 	UnfollowCommunity(address, communityID string) error
 	FollowsCommunity(address, communityID string) (bool, error)
+	CommunityFollowers(communityID string) ([]User, error)
+	CommunityFollowersCount(communityID string) (int, error)
+	RecentCommunityMembers(communityID string, since time.Time, limit int) ([]User, error)
 	AddImageURLToHighlight(id int64, url string) error
 	GrantInvites(id int64, count int) error
+	GrantInvitesToGroup(group UserGroup, count int) (int64, error)
 	ConsumeInvite(id int64) (bool, error)
 	UsersWithIncompleteJourney() ([]User, error)
+	UsersWhoCompletedJourney() ([]User, error)
 	UpdateUserJourney(id int64, journey []UserJourneyStep) error
 	RecordRewardLedgerEntry(userID int64, direction RewardLedgerEntryDirection, amount int64, currency RewardLedgerEntryCurrency) (*RewardLedgerEntry, error)
-	RecordVerificationAttempt(id int64) error
+	RewardLedgerByUser(id int64) ([]RewardLedgerEntry, error)
+	CanAttemptVerification(id int64, cooldown, window time.Duration, maxAttempts int) (bool, error)
+	RecordVerificationAttempt(id int64, windowStart time.Time) error
+	UpsertTwitterProfiles(profiles []TwitterProfile) error
 }
 
 // Queries read from the database
 type Queries interface {
 	GenericQueries
-	UsernamesAndImagesByPrefix(prefix string) ([]UsernameAndImage, error)
+	UsernamesAndImagesByPrefix(prefix string, limit int) ([]UsernameAndImage, error)
 	KeyPairByUserID(userID int64) (*KeyPair, error)
 	DeviceTokensByAddress(addr string) ([]DeviceToken, error)
 	NotificationEventsByAddress(addr string) ([]NotificationEvent, error)
+	NotificationEventsByAddressAndType(addr string, notificationType NotificationType) ([]NotificationEvent, error)
 	UnreadNotificationEventsCountByAddress(addr string) (*NotificationsCountResponse, error)
 	UnseenNotificationEventsCountByAddress(addr string) (*NotificationsCountResponse, error)
+	CountPrunableNotificationEvents(olderThan time.Time) (int, error)
+	RecentNotificationFailures(since time.Time, limit int) ([]NotificationFailure, error)
 	FlaggedStoriesIDs(flagAdmin string, flagLimit int) ([]int64, error)
 	ArgumentLevelComments(argumentID uint64, elementID uint64) ([]Comment, error)
 	CommentsByClaimID(claimID uint64) ([]Comment, error)
+	CommentsMatchingKeywords(keywords []string, from time.Time, limit int) ([]Comment, error)
+	CommenterActivitySince(since, until time.Time) ([]CommenterActivity, error)
+	UnreadCommentsCount(claimID int64, address string) (int, error)
 	ClaimLevelComments(claimID uint64) ([]Comment, error)
 	CommentByID(id int64) (*Comment, error)
+	CommentByIdempotencyKey(creator, key string) (*Comment, error)
 	QuestionsByClaimID(claimID uint64) ([]Question, error)
 	QuestionByID(ID int64) (*Question, error)
+	TagsByClaimID(claimID int64) ([]ClaimTag, error)
+	ClaimIDsByTag(tag string) ([]int64, error)
+	ActiveFeaturedClaims(communityID string) ([]FeaturedClaim, error)
+	SearchClaims(query string, limit, offset int) ([]ClaimSearchResult, error)
 	Invites() ([]Invite, error)
 	InvitesByAddress(addr string) ([]Invite, error)
 	InvitesByFriendEmail(email string) (*Invite, error)
 	ReactionsByReactionable(reactionable Reactionable) ([]Reaction, error)
 	ReactionsByAddress(addr string) ([]Reaction, error)
 	ReactionsCountByReactionable(reactionable Reactionable) ([]ReactionsCount, error)
+	ReactionsCountByReactionables(reactionableType ReactionableType, ids []int64) (map[int64][]ReactionsCount, error)
+	ReactionCountsByArgumentID(argumentID int64) ([]ReactionsCount, error)
+	ReactionsByAddressAndReactionable(addr string, reactionable Reactionable) ([]Reaction, error)
+	ReactionByAddressAndReactionable(addr string, reaction ReactionType, reactionable Reactionable) (*Reaction, error)
 	TranslateToCosmosMentions(body string) (string, error)
 	TranslateToUsersMentions(body string) (string, error)
+	MentionedUserIDs(body string) ([]int64, error)
 	InitialStakeBalanceByAddress(address string) (*InitialStakeBalance, error)
 	OpenedClaimsSummary(date time.Time) ([]UserOpenedClaimsSummary, error)
 	OpenedArgumentsSummary(date time.Time) ([]UserOpenedArgumentsSummary, error)
 	ClaimOfTheDayIDByCommunityID(communityID string) (int64, error)
 	ClaimImageURL(claimID uint64) (string, error)
 	ClaimVideoURL(claimID uint64) (string, error)
+	ClaimIDsWithMedia() ([]int64, error)
 	VerifiedUserByID(id int64) (*User, error)
 	GetAuthenticatedUser(identifier, password string) (*User, error)
 	UserByID(ID int64) (*User, error)
@@ -103,10 +149,12 @@ type Queries interface {
 	UserByEmail(email string) (*User, error)
 	UserByUsername(username string) (*User, error)
 	UserByAddress(address string) (*User, error)
+	NotificationPreferencesByAddress(address string) (NotificationPreferences, error)
 	UserByConnectedAccountTypeAndID(accountType, accountID string) (*User, error)
 	IsTwitterUser(userID int64) bool
 	ReferredUsers() ([]User, error)
 	ReferredUsersByID(referrerID int64) ([]User, error)
+	ReferralTree(rootID int64, maxDepth int) ([]ReferredUser, error)
 	UnusedResetTokensByUser(userID int64) ([]PasswordResetToken, error)
 	UnusedResetTokenByUserAndToken(userID int64, token string) (*PasswordResetToken, error)
 	ConnectedAccountsByUserID(userID int64) ([]ConnectedAccount, error)
@@ -114,16 +162,24 @@ type Queries interface {
 	UserProfileByAddress(addr string) (*UserProfile, error)
 	UsersByAddress(addresses []string) ([]User, error)
 	UsersByID(ids []int64) ([]User, error)
+	UsersByGroup(group UserGroup) ([]User, error)
+	UsersForExport(includeDeleted bool, afterID int64, limit int) ([]User, error)
+	ActiveUserCount(since time.Time) (int64, error)
+	ActiveParticipantCount(since time.Time) (int64, error)
 	UserProfileByUsername(username string) (*UserProfile, error)
-	ClaimViewsStats(date time.Time) ([]ClaimViewsStats, error)
-	ClaimRepliesStats(date time.Time) ([]ClaimRepliesStats, error)
+	ClaimViewsStatsByDay(date time.Time) ([]ClaimDayViewsStats, error)
+	ClaimRepliesStatsByDay(date time.Time) ([]ClaimDayRepliesStats, error)
+	CommentCountsByCommunity(before time.Time) (map[string]int64, error)
 	Leaderboard(since time.Time, sortBy string, limit int, excludedCommunities []string, address string) ([]LeaderboardTopUser, error)
+	CommunityLeaderboard(communityID string, since time.Time, sortBy string, limit int) ([]LeaderboardTopUser, error)
 	LastLeaderboardProcessedDate() (*LeaderboardProcessedDate, error)
 	FeedLeaderboardInTransaction(fn func(*pg.Tx) error) error
 	UpsertLeaderboardMetric(tx *pg.Tx, metric *LeaderboardUserMetric) error
 	UpsertLeaderboardProcessedDate(tx *pg.Tx, metric *LeaderboardProcessedDate) error
 	UserRepliesStats(date time.Time) ([]UserRepliesStats, error)
 	UnverifiedUsersWithinDays(days int64) ([]User, error)
+	StaleUnverifiedUsers(olderThanDays int64) ([]User, error)
+	InactiveUsersSince(t time.Time) ([]User, error)
 
 	// deprecated, use UserProfileByAddress/UserProfileByUsername
 	TwitterProfileByAddress(addr string) (*TwitterProfile, error)
@@ -35,6 +35,7 @@ const (
 	NotificationFeaturedDebate
 	NotificationStakeLimitIncreased
 	NotificationGift
+	NotificationReactionReceived
 )
 
 var NotificationTypeName = []string{
@@ -55,6 +56,7 @@ var NotificationTypeName = []string{
 	NotificationFeaturedDebate:        "Featured Debate",
 	NotificationStakeLimitIncreased:   "Staking Limit Increased",
 	NotificationGift:                  "Gift Received",
+	NotificationReactionReceived:      "Reaction received on Argument",
 }
 
 func (t NotificationType) String() string {
@@ -130,6 +132,21 @@ func (c *Client) NotificationEventsByAddress(addr string) ([]NotificationEvent,
 	return evts, nil
 }
 
+// NotificationEventsByAddressAndType retrieves the notifications of a type sent to an user.
+func (c *Client) NotificationEventsByAddressAndType(addr string, notificationType NotificationType) ([]NotificationEvent, error) {
+	evts := make([]NotificationEvent, 0)
+
+	err := c.Model(&evts).
+		Column("notification_event.*", "UserProfile", "SenderProfile").
+		Where("notification_event.address = ?", addr).
+		Where("notification_event.type = ?", notificationType).
+		Order("timestamp DESC").Select()
+	if err != nil {
+		return nil, err
+	}
+	return evts, nil
+}
+
 // UnreadNotificationEventsCountByAddress retrieves the number of unread notifications sent to an user.
 func (c *Client) UnreadNotificationEventsCountByAddress(addr string) (*NotificationsCountResponse, error) {
 	notificationEvent := new(NotificationEvent)
@@ -294,3 +311,31 @@ func (c *Client) MarkArgumentNotificationAsRead(addr string, claimID int64, argu
 
 	return nil
 }
+
+// PruneNotificationEvents deletes read notifications older than the given time, unread notifications are always kept.
+func (c *Client) PruneNotificationEvents(olderThan time.Time) (int, error) {
+	notificationEvent := new(NotificationEvent)
+	result, err := c.Model(notificationEvent).
+		Where("read IS TRUE").
+		Where("timestamp < ?", olderThan).
+		Delete()
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// CountPrunableNotificationEvents counts the notifications PruneNotificationEvents would delete
+func (c *Client) CountPrunableNotificationEvents(olderThan time.Time) (int, error) {
+	notificationEvent := new(NotificationEvent)
+	count, err := c.Model(notificationEvent).
+		Where("read IS TRUE").
+		Where("timestamp < ?", olderThan).
+		Count()
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
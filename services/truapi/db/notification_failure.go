@@ -0,0 +1,35 @@
+package db
+
+import (
+	"time"
+)
+
+// NotificationFailure is a push notification that couldn't be delivered to a user
+type NotificationFailure struct {
+	Timestamps
+	ID       int64            `json:"id"`
+	Address  string           `json:"address"`
+	Type     NotificationType `json:"type" sql:",notnull"`
+	Platform string           `json:"platform"`
+	Reason   string           `json:"reason"`
+}
+
+// RecordNotificationFailure records a failed delivery of a push notification
+func (c *Client) RecordNotificationFailure(failure *NotificationFailure) error {
+	return c.Add(failure)
+}
+
+// RecentNotificationFailures returns the failed deliveries since the given time, newest first
+func (c *Client) RecentNotificationFailures(since time.Time, limit int) ([]NotificationFailure, error) {
+	failures := make([]NotificationFailure, 0)
+	err := c.Model(&failures).
+		Where("created_at >= ?", since).
+		Where("deleted_at IS NULL").
+		Order("created_at DESC", "id DESC").
+		Limit(limit).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return failures, nil
+}
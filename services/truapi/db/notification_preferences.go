@@ -0,0 +1,37 @@
+package db
+
+// NotificationPreferences are the notification types a user turned on or off.
+// Types that aren't set are on.
+type NotificationPreferences map[NotificationType]bool
+
+// Enabled tells whether the user gets notifications of the type
+func (p NotificationPreferences) Enabled(t NotificationType) bool {
+	enabled, ok := p[t]
+	return !ok || enabled
+}
+
+// NotificationPreferencesByAddress returns the notification preferences of a user
+func (c *Client) NotificationPreferencesByAddress(address string) (NotificationPreferences, error) {
+	user, err := c.UserByAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return NotificationPreferences{}, nil
+	}
+	return user.NotificationPreferences, nil
+}
+
+// SetNotificationPreferences turns notification types on or off for a user, keeping the other types as they were
+func (c *Client) SetNotificationPreferences(id int64, preferences NotificationPreferences) error {
+	var user User
+	_, err := c.Model(&user).
+		Where("id = ?", id).
+		Where("deleted_at IS NULL").
+		Set("notification_preferences = notification_preferences || ?", preferences).
+		Update()
+	if err != nil {
+		return err
+	}
+	return nil
+}
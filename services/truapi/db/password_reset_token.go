@@ -93,14 +93,13 @@ func (c *Client) UseResetToken(prt *PasswordResetToken) error {
 		Where("deleted_at IS NULL").
 		Set("used_at = ?", time.Now()).
 		Update()
+	if err != nil {
+		return err
+	}
 
 	if result.RowsAffected() == 0 {
 		return errors.New("invalid token")
 	}
 
-	if err != nil {
-		return err
-	}
-
 	return nil
 }
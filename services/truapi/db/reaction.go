@@ -29,6 +29,9 @@ const (
 const (
 	// Argument represents a type of reactionable
 	Argument ReactionableType = "arguments"
+
+	// CommentReactionable represents comments as a type of reactionable
+	CommentReactionable ReactionableType = "comments"
 )
 
 // Reaction represents a reaction left by a user
@@ -71,6 +74,8 @@ func (c *Client) ReactionsCountByReactionable(reactionable Reactionable) ([]Reac
 	var result []ReactionsCount
 
 	err := c.Model((*Reaction)(nil)).
+		Where("reactionable_type = ?", reactionable.Type).
+		Where("reactionable_id = ?", reactionable.ID).
 		Where("deleted_at IS NULL").
 		ColumnExpr("reaction_type as type").
 		ColumnExpr("count(*) AS count").
@@ -84,6 +89,63 @@ func (c *Client) ReactionsCountByReactionable(reactionable Reactionable) ([]Reac
 	return result, nil
 }
 
+// ReactionCountsByArgumentID returns the count of each reaction type left on an argument
+func (c *Client) ReactionCountsByArgumentID(argumentID int64) ([]ReactionsCount, error) {
+	return c.ReactionsCountByReactionable(Reactionable{Type: Argument, ID: argumentID})
+}
+
+// ReactionsByAddressAndReactionable returns all the reactions left by a user on a particular reactionable
+func (c *Client) ReactionsByAddressAndReactionable(addr string, reactionable Reactionable) ([]Reaction, error) {
+	reactions := make([]Reaction, 0)
+
+	err := c.Model(&reactions).
+		Where("creator = ?", addr).
+		Where("reactionable_type = ?", reactionable.Type).
+		Where("reactionable_id = ?", reactionable.ID).
+		Where("deleted_at IS NULL").
+		Order("created_at DESC").
+		Select()
+
+	if err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
+
+type reactionableReactionsCount struct {
+	ReactionableID int64
+	Type           ReactionType
+	Count          int64
+}
+
+// ReactionsCountByReactionables returns the count of each reaction type for many reactionables of the same type, keyed by reactionable id
+func (c *Client) ReactionsCountByReactionables(reactionableType ReactionableType, ids []int64) (map[int64][]ReactionsCount, error) {
+	counts := make(map[int64][]ReactionsCount)
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	var result []reactionableReactionsCount
+	err := c.Model((*Reaction)(nil)).
+		Where("reactionable_type = ?", reactionableType).
+		Where("reactionable_id IN (?)", pg.In(ids)).
+		Where("deleted_at IS NULL").
+		ColumnExpr("reactionable_id").
+		ColumnExpr("reaction_type as type").
+		ColumnExpr("count(*) AS count").
+		Group("reactionable_id", "reaction_type").
+		Order("reaction_type").
+		Select(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range result {
+		counts[r.ReactionableID] = append(counts[r.ReactionableID], ReactionsCount{Type: r.Type, Count: r.Count})
+	}
+	return counts, nil
+}
+
 // ReactionsByAddress returns all the reactions left by a user on any reactionable
 func (c *Client) ReactionsByAddress(addr string) ([]Reaction, error) {
 	reactions := make([]Reaction, 0)
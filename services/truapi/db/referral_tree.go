@@ -0,0 +1,58 @@
+package db
+
+import "github.com/go-pg/pg"
+
+// ReferredUser is a user in a referral tree, with their distance from the root referrer.
+// It only carries the public fields of the user, since any user can read their own tree.
+type ReferredUser struct {
+	ID         int64
+	ReferredBy int64
+	Address    string
+	Username   string
+	// Depth is 1 for the users invited by the root, 2 for the users they invited, and so on
+	Depth int
+}
+
+// ReferralTree returns the users referred by a user, directly or through the users they referred,
+// walking breadth first up to maxDepth levels
+func (c *Client) ReferralTree(rootID int64, maxDepth int) ([]ReferredUser, error) {
+	return walkReferralTree(rootID, maxDepth, func(referrerIDs []int64) ([]User, error) {
+		users := make([]User, 0)
+		err := c.Model(&users).
+			Where("deleted_at IS NULL").
+			WhereIn("referred_by IN (?)", pg.In(referrerIDs)).
+			Order("id ASC").
+			Select()
+		return users, err
+	})
+}
+
+// walkReferralTree visits the referral levels breadth first, never visiting a user twice
+// so that a user referring up their own chain cannot loop
+func walkReferralTree(rootID int64, maxDepth int, referredBy func(referrerIDs []int64) ([]User, error)) ([]ReferredUser, error) {
+	tree := make([]ReferredUser, 0)
+	visited := map[int64]bool{rootID: true}
+	frontier := []int64{rootID}
+	for depth := 1; depth <= maxDepth && len(frontier) > 0; depth++ {
+		users, err := referredBy(frontier)
+		if err != nil {
+			return nil, err
+		}
+		frontier = make([]int64, 0, len(users))
+		for _, user := range users {
+			if visited[user.ID] {
+				continue
+			}
+			visited[user.ID] = true
+			tree = append(tree, ReferredUser{
+				ID:         user.ID,
+				ReferredBy: user.ReferredBy,
+				Address:    user.Address,
+				Username:   user.Username,
+				Depth:      depth,
+			})
+			frontier = append(frontier, user.ID)
+		}
+	}
+	return tree, nil
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func referredByFunc(users []User) func([]int64) ([]User, error) {
+	return func(referrerIDs []int64) ([]User, error) {
+		referred := make([]User, 0)
+		for _, user := range users {
+			for _, id := range referrerIDs {
+				if user.ReferredBy == id {
+					referred = append(referred, user)
+				}
+			}
+		}
+		return referred, nil
+	}
+}
+
+func TestWalkReferralTree(t *testing.T) {
+	users := []User{
+		{ID: 2, ReferredBy: 1},
+		{ID: 3, ReferredBy: 1},
+		{ID: 4, ReferredBy: 2},
+		{ID: 5, ReferredBy: 4},
+	}
+	tree, err := walkReferralTree(1, 3, referredByFunc(users))
+	assert.NoError(t, err)
+	depths := make(map[int64]int)
+	for _, user := range tree {
+		depths[user.ID] = user.Depth
+	}
+	assert.Equal(t, map[int64]int{2: 1, 3: 1, 4: 2, 5: 3}, depths)
+
+	tree, err = walkReferralTree(1, 2, referredByFunc(users))
+	assert.NoError(t, err)
+	assert.Len(t, tree, 3)
+
+	// the root was referred by someone down its own chain
+	cyclic := append(users, User{ID: 1, ReferredBy: 5})
+	tree, err = walkReferralTree(1, 10, referredByFunc(cyclic))
+	assert.NoError(t, err)
+	assert.Len(t, tree, 4)
+}
+
+func TestWalkReferralTreeOnlyPublicFields(t *testing.T) {
+	users := []User{
+		{ID: 2, ReferredBy: 1, Address: "cosmos1xyz", Username: "alice", Email: "alice@example.com", InvitesLeft: 5},
+	}
+	tree, err := walkReferralTree(1, 1, referredByFunc(users))
+	assert.NoError(t, err)
+	assert.Equal(t, []ReferredUser{
+		{ID: 2, ReferredBy: 1, Address: "cosmos1xyz", Username: "alice", Depth: 1},
+	}, tree)
+}
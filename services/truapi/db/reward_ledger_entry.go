@@ -49,3 +49,20 @@ func (c *Client) RecordRewardLedgerEntry(
 
 	return entry, nil
 }
+
+// RewardLedgerByUser returns the invites credited to and debited from the user, newest first.
+// Coin gifts recorded in the ledger are left out.
+func (c *Client) RewardLedgerByUser(id int64) ([]RewardLedgerEntry, error) {
+	entries := make([]RewardLedgerEntry, 0)
+	err := c.Model(&entries).
+		Where("user_id = ?", id).
+		Where("currency = ?", RewardLedgerEntryCurrencyInvite).
+		Where("deleted_at IS NULL").
+		Order("created_at DESC", "id DESC").
+		Select()
+	if err != nil {
+		return entries, err
+	}
+
+	return entries, nil
+}
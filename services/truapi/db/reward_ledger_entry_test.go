@@ -0,0 +1,53 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewardLedgerByUser(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	alice := createTestUser(t, c, "ledger_alice")
+	bob := createTestUser(t, c, "ledger_bob")
+	require.NoError(t, c.GrantInvites(alice.ID, 2))
+	require.NoError(t, c.GrantInvites(bob.ID, 5))
+	for i := 0; i < 3; i++ {
+		consumed, err := c.ConsumeInvite(alice.ID)
+		require.NoError(t, err)
+		// only two invites were granted
+		assert.Equal(t, i < 2, consumed)
+	}
+	// gifts are recorded in the same ledger
+	_, err := c.RecordRewardLedgerEntry(alice.ID, RewardLedgerEntryDirectionCredit, 1000, RewardLedgerEntryCurrencyTru)
+	require.NoError(t, err)
+
+	user, err := c.UserByID(alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), user.InvitesLeft)
+
+	entries, err := c.RewardLedgerByUser(alice.ID)
+	require.NoError(t, err)
+	type ledgerLine struct {
+		direction RewardLedgerEntryDirection
+		amount    int64
+	}
+	lines := make([]ledgerLine, 0, len(entries))
+	for i, entry := range entries {
+		assert.Equal(t, alice.ID, entry.UserID)
+		assert.Equal(t, RewardLedgerEntryCurrencyInvite, entry.Currency)
+		if i > 0 {
+			assert.True(t, entry.ID < entries[i-1].ID)
+			assert.False(t, entry.CreatedAt.After(entries[i-1].CreatedAt))
+		}
+		lines = append(lines, ledgerLine{entry.Direction, entry.Amount})
+	}
+	assert.Equal(t, []ledgerLine{
+		{RewardLedgerEntryDirectionDebit, 1},
+		{RewardLedgerEntryDirectionDebit, 1},
+		{RewardLedgerEntryDirectionCredit, 2},
+	}, lines)
+}
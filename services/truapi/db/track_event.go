@@ -1,6 +1,8 @@
 package db
 
-import "time"
+import (
+	"time"
+)
 
 type TrackEventMeta struct {
 	ClaimID     *int64  `json:"claimId,omitempty"`
@@ -119,41 +121,48 @@ func (c *Client) OpenedArgumentsSummary(date time.Time) ([]UserOpenedArgumentsSu
 	return openedArgumentsSummary, nil
 }
 
-func (c *Client) ClaimViewsStats(date time.Time) ([]ClaimViewsStats, error) {
-	claimViewsStats := make([]ClaimViewsStats, 0)
+// ClaimDayViewsStats represents the views of a claim and its arguments on a single day.
+type ClaimDayViewsStats struct {
+	Day time.Time
+	ClaimViewsStats
+}
+
+// ClaimViewsStatsByDay returns the views of every claim and its arguments on each day before the given date,
+// ordered by day and claim id. Authenticated viewers are identified by address and anonymous ones by session,
+// and unique views count every viewer once per day, so that the stats of days add up.
+// Events with neither an address nor a session are ignored.
+func (c *Client) ClaimViewsStatsByDay(date time.Time) ([]ClaimDayViewsStats, error) {
+	claimViewsStats := make([]ClaimDayViewsStats, 0)
 	query := `
+		SELECT
+			day,
+			(meta ->> 'claimId')::bigint claim_id,
+			COUNT(*) FILTER (WHERE event = 'claim_opened' AND by_user) user_views,
+			COUNT(DISTINCT address) FILTER (WHERE event = 'claim_opened' AND by_user) unique_user_views,
+			COUNT(*) FILTER (WHERE event = 'claim_opened' AND by_anon) anon_views,
+			COUNT(DISTINCT session_id) FILTER (WHERE event = 'claim_opened' AND by_anon) unique_anon_views,
+			COUNT(*) FILTER (WHERE event = 'argument_opened' AND by_user) user_arguments_views,
+			COUNT(DISTINCT address) FILTER (WHERE event = 'argument_opened' AND by_user) unique_user_arguments_views,
+			COUNT(*) FILTER (WHERE event = 'argument_opened' AND by_anon) anon_arguments_views,
+			COUNT(DISTINCT session_id) FILTER (WHERE event = 'argument_opened' AND by_anon) unique_anon_arguments_views
+		FROM (
 			SELECT
-				claim_id,
-				sum(m.user_views) user_views,
-				sum(m.unique_user_views) unique_user_views,
-				sum(m.anon_views) anon_views,
-				sum(m.unique_anon_views) unique_anon_views,
-				sum(m.user_arguments_views) user_arguments_views,
-				sum(m.unique_user_arguments_views) unique_user_arguments_views,
-				sum(m.anon_arguments_views) anon_arguments_views,
-				sum(m.unique_anon_arguments_views) unique_anon_arguments_views
-			FROM (
-				SELECT
-					DATE(created_at),
-					meta ->> 'claimId' claim_id,
-					count(address) FILTER (WHERE event = 'claim_opened') user_views,
-					count(DISTINCT address) FILTER (WHERE event = 'claim_opened') unique_user_views,
-					count(session_id) FILTER (WHERE event = 'claim_opened') anon_views,
-					count(DISTINCT session_id) FILTER (WHERE event = 'claim_opened') unique_anon_views,
-					count(address) FILTER (WHERE event = 'argument_opened') user_arguments_views,
-					count(DISTINCT address) FILTER (WHERE event = 'argument_opened') unique_user_arguments_views,
-					count(session_id) FILTER (WHERE event = 'argument_opened') anon_arguments_views,
-					count(DISTINCT session_id) FILTER (WHERE event = 'argument_opened') unique_anon_arguments_views 
-					FROM track_events WHERE event in('claim_opened', 'argument_opened')
-					AND
-					created_at < ?
-				GROUP BY
-					DATE(created_at),
-					meta ->> 'claimId') AS m
-			GROUP BY
-				m.claim_id;
-				`
-
+				meta,
+				event,
+				address,
+				session_id,
+				DATE(created_at) AS day,
+				COALESCE(address, '') != '' AS by_user,
+				COALESCE(address, '') = '' AND COALESCE(session_id, '') != '' AS by_anon
+			FROM track_events
+			WHERE
+				event IN ('claim_opened', 'argument_opened')
+				AND meta -> 'claimId' IS NOT NULL
+				AND created_at < ?
+		) AS views
+		GROUP BY day, (meta ->> 'claimId')::bigint
+		ORDER BY day, claim_id
+	`
 	_, err := c.Query(&claimViewsStats, query, date)
 	if err != nil {
 		return nil, err
@@ -161,20 +170,26 @@ func (c *Client) ClaimViewsStats(date time.Time) ([]ClaimViewsStats, error) {
 	return claimViewsStats, nil
 }
 
-func (c *Client) ClaimRepliesStats(date time.Time) ([]ClaimRepliesStats, error) {
-	claimRepliesStats := make([]ClaimRepliesStats, 0)
-	query := `
-				SELECT
-					claim_id,
-					count(claim_id) replies
-				FROM
-					comments
-				WHERE
-					created_at < ?
-				GROUP BY
-					claim_id
-				`
+// ClaimDayRepliesStats represents the replies on a claim on a single day.
+type ClaimDayRepliesStats struct {
+	Day time.Time
+	ClaimRepliesStats
+}
 
+// ClaimRepliesStatsByDay returns the replies on every claim on each day before the given date,
+// ordered by day and claim id.
+func (c *Client) ClaimRepliesStatsByDay(date time.Time) ([]ClaimDayRepliesStats, error) {
+	claimRepliesStats := make([]ClaimDayRepliesStats, 0)
+	query := `
+		SELECT
+			DATE(created_at) AS day,
+			claim_id,
+			COUNT(*) replies
+		FROM comments
+		WHERE created_at < ?
+		GROUP BY DATE(created_at), claim_id
+		ORDER BY day, claim_id
+	`
 	_, err := c.Query(&claimRepliesStats, query, date)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimViewsStatsByDay(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour).Add(time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	first, second := int64(917401), int64(917402)
+	track := func(claimID int64, event, address, sessionID string, at time.Time) {
+		t.Helper()
+		require.NoError(t, c.Add(&TrackEvent{
+			Address:    address,
+			Event:      event,
+			Meta:       TrackEventMeta{ClaimID: &claimID},
+			SessionID:  sessionID,
+			Timestamps: Timestamps{CreatedAt: at, UpdatedAt: at},
+		}))
+	}
+	// alice opened the second claim twice today and once yesterday
+	track(second, "claim_opened", "alice", "", today)
+	track(second, "claim_opened", "alice", "", today.Add(time.Minute))
+	track(second, "claim_opened", "alice", "", yesterday)
+	track(second, "claim_opened", "bob", "", today)
+	track(second, "claim_opened", "", "session-1", today)
+	track(second, "claim_opened", "", "session-1", today)
+	track(second, "claim_opened", "", "session-1", today)
+	track(second, "argument_opened", "alice", "", today)
+	track(second, "argument_opened", "alice", "", today)
+	track(second, "argument_opened", "", "session-1", today)
+	track(first, "claim_opened", "bob", "", today)
+	// neither an user nor a session
+	track(first, "claim_opened", "", "", today)
+	// after the date
+	track(first, "claim_opened", "bob", "", today.Add(48*time.Hour))
+
+	stats, err := c.ClaimViewsStatsByDay(today.Add(24 * time.Hour))
+	require.NoError(t, err)
+	ours := make([]ClaimDayViewsStats, 0)
+	for _, s := range stats {
+		if s.ClaimID == first || s.ClaimID == second {
+			s.Day = s.Day.UTC()
+			ours = append(ours, s)
+		}
+	}
+	day := func(t time.Time) time.Time { return t.Truncate(24 * time.Hour) }
+	assert.Equal(t, []ClaimDayViewsStats{
+		{Day: day(yesterday), ClaimViewsStats: ClaimViewsStats{ClaimID: second, UserViews: 1, UniqueUserViews: 1}},
+		{Day: day(today), ClaimViewsStats: ClaimViewsStats{ClaimID: first, UserViews: 1, UniqueUserViews: 1}},
+		{Day: day(today), ClaimViewsStats: ClaimViewsStats{
+			ClaimID:   second,
+			UserViews: 3, UniqueUserViews: 2,
+			AnonViews: 3, UniqueAnonViews: 1,
+			UserArgumentsViews: 2, UniqueUserArgumentsViews: 1,
+			AnonArgumentsViews: 1, UniqueAnonArgumentsViews: 1,
+		}},
+	}, ours)
+}
+
+func TestClaimRepliesStatsByDay(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour).Add(time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	first, second := int64(917411), int64(917412)
+	createTestComment(t, c, first, "alice", yesterday)
+	createTestComment(t, c, first, "bob", today)
+	createTestComment(t, c, first, "bob", today.Add(time.Minute))
+	createTestComment(t, c, second, "alice", today)
+	// after the date
+	createTestComment(t, c, second, "alice", today.Add(48*time.Hour))
+
+	stats, err := c.ClaimRepliesStatsByDay(today.Add(24 * time.Hour))
+	require.NoError(t, err)
+	ours := make([]ClaimDayRepliesStats, 0)
+	for _, s := range stats {
+		if s.ClaimID == first || s.ClaimID == second {
+			s.Day = s.Day.UTC()
+			ours = append(ours, s)
+		}
+	}
+	day := func(t time.Time) time.Time { return t.Truncate(24 * time.Hour) }
+	assert.Equal(t, []ClaimDayRepliesStats{
+		{Day: day(yesterday), ClaimRepliesStats: ClaimRepliesStats{ClaimID: first, Replies: 1}},
+		{Day: day(today), ClaimRepliesStats: ClaimRepliesStats{ClaimID: first, Replies: 2}},
+		{Day: day(today), ClaimRepliesStats: ClaimRepliesStats{ClaimID: second, Replies: 1}},
+	}, ours)
+}
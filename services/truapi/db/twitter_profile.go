@@ -2,7 +2,9 @@ package db
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/go-pg/pg"
 )
@@ -17,6 +19,8 @@ type TwitterProfile struct {
 	Email       string `json:"email"`
 	AvatarURI   string `json:"avatar_uri"`
 	Description string `json:"description"`
+	// FetchedAt is when the profile was fetched from Twitter, only used when backfilling
+	FetchedAt time.Time `json:"fetched_at" sql:"-"`
 }
 
 func (t TwitterProfile) String() string {
@@ -101,3 +105,87 @@ func (c *Client) TwitterProfileByUsername(username string) (*TwitterProfile, err
 
 	return twitterProfile, nil
 }
+
+// UpsertTwitterProfiles backfills the stored Twitter profiles from the given ones.
+// Profiles are deduped by address keeping the latest fetched one, and a stored profile is only
+// replaced by one fetched after it was last updated. The users' own name, avatar and bio are
+// only filled in when blank, so that edits made by the users are never overwritten.
+func (c *Client) UpsertTwitterProfiles(profiles []TwitterProfile) error {
+	return c.RunInTransaction(func(tx *pg.Tx) error {
+		for _, profile := range latestTwitterProfiles(profiles) {
+			applied, err := upsertTwitterProfile(tx, profile)
+			if err != nil {
+				return err
+			}
+			if !applied {
+				continue
+			}
+			_, err = tx.Exec(`
+				UPDATE users
+				SET
+					full_name = CASE WHEN COALESCE(full_name, '') = '' THEN ?0 ELSE full_name END,
+					avatar_url = CASE WHEN COALESCE(avatar_url, '') = '' THEN ?1 ELSE avatar_url END,
+					bio = CASE WHEN COALESCE(bio, '') = '' THEN ?2 ELSE bio END
+				WHERE address = ?3 AND deleted_at IS NULL
+			`, profile.FullName, profile.AvatarURI, profile.Description, profile.Address)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// upsertTwitterProfile updates the stored profile of the address when the given one is fresher,
+// or inserts it when there is none. It tells whether the profile was applied.
+func upsertTwitterProfile(tx *pg.Tx, profile TwitterProfile) (bool, error) {
+	result, err := tx.Exec(`
+		UPDATE twitter_profiles
+		SET
+			username = COALESCE(NULLIF(?0, ''), username),
+			full_name = COALESCE(NULLIF(?1, ''), full_name),
+			avatar_uri = COALESCE(NULLIF(?2, ''), avatar_uri),
+			description = COALESCE(NULLIF(?3, ''), description),
+			updated_at = ?4
+		WHERE address = ?5 AND deleted_at IS NULL AND updated_at < ?4
+	`, profile.Username, profile.FullName, profile.AvatarURI, profile.Description, profile.FetchedAt, profile.Address)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() > 0 {
+		return true, nil
+	}
+
+	result, err = tx.Exec(`
+		INSERT INTO twitter_profiles (address, username, full_name, avatar_uri, description, created_at, updated_at)
+		SELECT ?0, ?1, ?2, ?3, ?4, NOW(), ?5
+		WHERE NOT EXISTS (SELECT 1 FROM twitter_profiles WHERE address = ?0 AND deleted_at IS NULL)
+	`, profile.Address, profile.Username, profile.FullName, profile.AvatarURI, profile.Description, profile.FetchedAt)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// latestTwitterProfiles dedupes profiles by address, keeping the most recently fetched one
+func latestTwitterProfiles(profiles []TwitterProfile) []TwitterProfile {
+	latest := make(map[string]TwitterProfile)
+	for _, profile := range profiles {
+		if profile.Address == "" {
+			continue
+		}
+		existing, ok := latest[profile.Address]
+		if !ok || profile.FetchedAt.After(existing.FetchedAt) {
+			latest[profile.Address] = profile
+		}
+	}
+
+	deduped := make([]TwitterProfile, 0, len(latest))
+	for _, profile := range latest {
+		deduped = append(deduped, profile)
+	}
+	sort.Slice(deduped, func(i, j int) bool {
+		return deduped[i].Address < deduped[j].Address
+	})
+	return deduped
+}
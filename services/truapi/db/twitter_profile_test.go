@@ -0,0 +1,74 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatestTwitterProfiles(t *testing.T) {
+	now := time.Now()
+	profiles := []TwitterProfile{
+		{Address: "b", AvatarURI: "b-old", FetchedAt: now.Add(-time.Hour)},
+		{Address: "a", AvatarURI: "a", FetchedAt: now},
+		{Address: "b", AvatarURI: "b-new", FetchedAt: now},
+		{Address: "b", AvatarURI: "b-older", FetchedAt: now.Add(-2 * time.Hour)},
+		{Address: "", AvatarURI: "missing"},
+	}
+
+	latest := latestTwitterProfiles(profiles)
+	assert.Len(t, latest, 2)
+	assert.Equal(t, "a", latest[0].AvatarURI)
+	assert.Equal(t, "b-new", latest[1].AvatarURI)
+}
+
+func TestUpsertTwitterProfiles(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	user := createTestUser(t, c, "backfilled")
+	assert.NoError(t, c.AddAddressToUser(user.ID, "cosmos1backfilled"))
+	assert.NoError(t, c.UpdateProfile(user.ID, &UserProfile{FullName: "Edited Name", Username: "backfilled"}, 0))
+	before, err := c.UserByID(user.ID)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	profileByAddress := func() TwitterProfile {
+		var profile TwitterProfile
+		assert.NoError(t, c.Model(&profile).Where("address = ?", "cosmos1backfilled").Select())
+		return profile
+	}
+
+	err = c.UpsertTwitterProfiles([]TwitterProfile{{
+		Address:     "cosmos1backfilled",
+		Username:    "backfilled_tw",
+		FullName:    "Twitter Name",
+		AvatarURI:   "avatar-1",
+		Description: "twitter bio",
+		FetchedAt:   now.Add(-time.Hour),
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, "avatar-1", profileByAddress().AvatarURI)
+
+	// blank fields are filled in, the name the user edited is kept and the user isn't touched otherwise
+	after, err := c.UserByID(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Edited Name", after.FullName)
+	assert.Equal(t, "avatar-1", after.AvatarURL)
+	assert.Equal(t, "twitter bio", after.Bio)
+	assert.Equal(t, before.UpdatedAt, after.UpdatedAt)
+
+	// a staler profile doesn't replace the stored one
+	err = c.UpsertTwitterProfiles([]TwitterProfile{{Address: "cosmos1backfilled", AvatarURI: "stale", FetchedAt: now.Add(-2 * time.Hour)}})
+	assert.NoError(t, err)
+	assert.Equal(t, "avatar-1", profileByAddress().AvatarURI)
+
+	// a fresher one does, without overwriting the avatar the user now has
+	err = c.UpsertTwitterProfiles([]TwitterProfile{{Address: "cosmos1backfilled", AvatarURI: "avatar-2", FetchedAt: now}})
+	assert.NoError(t, err)
+	assert.Equal(t, "avatar-2", profileByAddress().AvatarURI)
+	after, err = c.UserByID(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "avatar-1", after.AvatarURL)
+}
@@ -37,6 +37,11 @@ var userGroupTypeName = []string{
 	UserGroupResearchAnalyst: "Research Analyst",
 }
 
+// IsValid tells whether the group is one of the defined user groups
+func (ug UserGroup) IsValid() bool {
+	return ug >= UserGroupUser && int(ug) < len(userGroupTypeName)
+}
+
 func (ug UserGroup) String() string {
 	if int(ug) >= len(userGroupTypeName) {
 		return "Unknown"
@@ -48,26 +53,35 @@ func (ug UserGroup) String() string {
 type User struct {
 	Timestamps
 
-	ID                        int64      `json:"id"`
-	FullName                  string     `json:"full_name"`
-	Username                  string     `json:"username"`
-	Email                     string     `json:"email"`
-	Bio                       string     `json:"bio"`
-	AvatarURL                 string     `json:"avatar_url"`
-	Address                   string     `json:"address"`
-	InvitesLeft               int64      `json:"invites_left"`
-	Password                  string     `json:"-" graphql:"-"`
-	ReferredBy                int64      `json:"referred_by"`
-	Token                     string     `json:"-" graphql:"-"`
-	ApprovedAt                time.Time  `json:"approved_at" graphql:"-"`
-	RejectedAt                time.Time  `json:"rejected_at" graphql:"-"`
-	VerifiedAt                time.Time  `json:"verified_at" graphql:"-"`
-	BlacklistedAt             time.Time  `json:"blacklisted_at" graphql:"-"`
-	LastAuthenticatedAt       *time.Time `json:"last_authenticated_at" graphql:"-"`
-	UserGroup                 UserGroup  `json:"user_group"`
-	LastVerificationAttemptAt time.Time  `json:"last_verification_attempt_at" graphql:"-"`
-	VerificationAttemptCount  int        `json:"verification_attempt_count"`
-	Meta                      UserMeta   `json:"meta"`
+	ID                        int64                   `json:"id"`
+	FullName                  string                  `json:"full_name"`
+	Username                  string                  `json:"username"`
+	Email                     string                  `json:"email"`
+	CanonicalEmail            string                  `json:"-" graphql:"-"`
+	Bio                       string                  `json:"bio"`
+	AvatarURL                 string                  `json:"avatar_url"`
+	Address                   string                  `json:"address"`
+	InvitesLeft               int64                   `json:"invites_left"`
+	Password                  string                  `json:"-" graphql:"-"`
+	ReferredBy                int64                   `json:"referred_by"`
+	Token                     string                  `json:"-" graphql:"-"`
+	ApprovedAt                time.Time               `json:"approved_at" graphql:"-"`
+	RejectedAt                time.Time               `json:"rejected_at" graphql:"-"`
+	VerifiedAt                time.Time               `json:"verified_at" graphql:"-"`
+	BlacklistedAt             time.Time               `json:"blacklisted_at" graphql:"-"`
+	LastAuthenticatedAt       *time.Time              `json:"last_authenticated_at" graphql:"-"`
+	LastUsernameChangedAt     *time.Time              `json:"last_username_changed_at" graphql:"-"`
+	UserGroup                 UserGroup               `json:"user_group"`
+	LastVerificationAttemptAt time.Time               `json:"last_verification_attempt_at" graphql:"-"`
+	VerificationAttemptCount  int                     `json:"verification_attempt_count"`
+	PendingEmail              string                  `json:"-" graphql:"-"`
+	PendingEmailToken         string                  `json:"-" graphql:"-"`
+	Meta                      UserMeta                `json:"meta"`
+	NotificationPreferences   NotificationPreferences `json:"notification_preferences" graphql:"-"`
+	// AnonymizedProfile keeps the profile of a deleted user until they are restored
+	AnonymizedProfile *UserProfile `json:"-" graphql:"-"`
+	// SessionsRevokedAt invalidates the login cookies issued until then
+	SessionsRevokedAt *time.Time `json:"-" graphql:"-"`
 }
 
 // UserMeta holds user meta data
@@ -76,6 +90,7 @@ type UserMeta struct {
 	OnboardCarousel          *bool             `json:"onboardCarousel,omitempty"`
 	OnboardContextual        *bool             `json:"onboardContextual,omitempty"`
 	Journey                  []UserJourneyStep `json:"journey,omitempty"`
+	OnboardingResentAt       *time.Time        `json:"onboardingResentAt,omitempty"`
 }
 
 // UserJourneyStep is a step in the entire journey
@@ -282,27 +297,25 @@ func (c *Client) RegisterUser(user *User, referrerCode, defaultAvatarURL string)
 	user.LastVerificationAttemptAt = time.Now()
 	user.VerificationAttemptCount = 1
 
-	referrer, err := c.UserByAddress(referrerCode)
-	if err != nil {
-		return err
-	}
-	if referrer != nil {
-		consumed, err := c.ConsumeInvite(referrer.ID)
+	// the invite is only consumed if the user is created
+	return c.WithTx(func(tx *Client) error {
+		referrer, err := tx.UserByAddress(referrerCode)
 		if err != nil {
 			return err
 		}
+		if referrer != nil {
+			consumed, err := tx.ConsumeInvite(referrer.ID)
+			if err != nil {
+				return err
+			}
 
-		if consumed {
-			user.ReferredBy = referrer.ID
+			if consumed {
+				user.ReferredBy = referrer.ID
+			}
 		}
-	}
 
-	err = c.AddUser(user)
-	if err != nil {
-		return err
-	}
-
-	return nil
+		return tx.AddUser(user)
+	})
 }
 
 // VerifyUser verifies the user via token
@@ -326,6 +339,113 @@ func (c *Client) VerifyUser(id int64, token string) error {
 	return nil
 }
 
+// InitiateEmailChange stores the new email of a user as pending until it is confirmed with the returned token
+func (c *Client) InitiateEmailChange(id int64, newEmail string) (token string, err error) {
+	newEmail = strings.ToLower(strings.TrimSpace(newEmail))
+	if !regex.IsValidEmail(newEmail) {
+		return "", errors.New("invalid email")
+	}
+	err = c.ensureEmailAvailable(id, newEmail)
+	if err != nil {
+		return "", err
+	}
+
+	random, err := generateCryptoSafeRandomBytes(32)
+	if err != nil {
+		return "", err
+	}
+	token = hex.EncodeToString(random)
+
+	var user User
+	result, err := c.Model(&user).
+		Where("id = ?", id).
+		Where("deleted_at IS NULL").
+		Set("pending_email = ?", newEmail).
+		Set("pending_email_token = ?", token).
+		Update()
+	if err != nil {
+		return "", err
+	}
+
+	if result.RowsAffected() == 0 {
+		return "", errors.New("invalid user")
+	}
+
+	return token, nil
+}
+
+// ConfirmEmailChange replaces the email of a user with their pending email
+func (c *Client) ConfirmEmailChange(id int64, token string) error {
+	user, err := c.UserByID(id)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.PendingEmail == "" || token == "" || user.PendingEmailToken != token {
+		return errors.New("invalid token")
+	}
+	err = c.ensureEmailAvailable(id, user.PendingEmail)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.Model(user).
+		Where("id = ?", id).
+		Where("pending_email_token = ?", token).
+		Where("deleted_at IS NULL").
+		Set("email = pending_email").
+		Set("canonical_email = ?", CanonicalEmail(user.PendingEmail)).
+		Set("pending_email = NULL").
+		Set("pending_email_token = NULL").
+		Update()
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("invalid token")
+	}
+
+	return nil
+}
+
+// ensureEmailAvailable checks that no other non-deleted user uses the email or one of its aliases
+func (c *Client) ensureEmailAvailable(id int64, email string) error {
+	count, err := c.Model((*User)(nil)).
+		Where("id != ?", id).
+		Where("(LOWER(email) = ? OR canonical_email = ?)", strings.ToLower(email), CanonicalEmail(email)).
+		Where("deleted_at IS NULL").
+		Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("a user already exists with same email")
+	}
+	return nil
+}
+
+// BulkVerifyUsers marks the given users as verified without the email round-trip.
+// Already verified, blacklisted or deleted users are left untouched.
+func (c *Client) BulkVerifyUsers(ids []int64) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var user User
+	result, err := c.Model(&user).
+		WhereIn("id IN (?)", pg.In(ids)).
+		Where("verified_at IS NULL").
+		Where("blacklisted_at IS NULL").
+		Where("deleted_at IS NULL").
+		Set("verified_at = ?", time.Now()).
+		Update()
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
 // AddAddressToUser adds a cosmos address to the user
 func (c *Client) AddAddressToUser(id int64, address string) error {
 	var user User
@@ -351,7 +471,6 @@ func (c *Client) ResetPassword(id int64, password string) error {
 	if user == nil {
 		return errors.New("no such user found")
 	}
-
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
@@ -370,6 +489,18 @@ func (c *Client) ResetPassword(id int64, password string) error {
 	return nil
 }
 
+// ResetPasswordWithToken saves the new password and marks the reset token as used in one transaction,
+// so that the token stays usable when the password cannot be saved
+func (c *Client) ResetPasswordWithToken(prt *PasswordResetToken, password string) error {
+	return c.WithTx(func(tx *Client) error {
+		err := tx.ResetPassword(prt.UserID, password)
+		if err != nil {
+			return err
+		}
+		return tx.UseResetToken(prt)
+	})
+}
+
 // UpdatePassword changes a password for a user
 func (c *Client) UpdatePassword(id int64, password *UserPassword) error {
 	user, err := c.VerifiedUserByID(id)
@@ -384,7 +515,6 @@ func (c *Client) UpdatePassword(id int64, password *UserPassword) error {
 	if err != nil {
 		return errors.New("incorrect current password")
 	}
-
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password.New), bcrypt.DefaultCost)
 	if err != nil {
 		return err
@@ -404,8 +534,14 @@ func (c *Client) UpdatePassword(id int64, password *UserPassword) error {
 	return nil
 }
 
-// UpdateProfile changes a profile fields for a user
-func (c *Client) UpdateProfile(id int64, profile *UserProfile) error {
+// UpdateProfile changes a profile fields for a user.
+// The username can only be changed once per cooldown, the other fields can be changed anytime.
+func (c *Client) UpdateProfile(id int64, profile *UserProfile, usernameCooldown time.Duration) error {
+	err := validateProfile(profile)
+	if err != nil {
+		return err
+	}
+
 	user, err := c.UserByID(id)
 	if err != nil {
 		return err
@@ -414,40 +550,79 @@ func (c *Client) UpdateProfile(id int64, profile *UserProfile) error {
 		return errors.New("no such user found")
 	}
 
-	user, err = c.UserByUsername(profile.Username)
+	// the username owner is looked up separately so the update below always targets the user identified by id
+	existing, err := c.UserByUsername(profile.Username)
 	if err != nil {
-		return errors.New("no such user found")
-	}
-
-	if user != nil && user.ID != id {
-		return errors.New("this username has already been taken, please choose another")
+		return err
 	}
-
-	if profile.FullName == "" {
-		return errors.New("name cannot be left blank")
+	if usernameTakenByOther(existing, id) {
+		return ValidationError{Field: "username", Message: "this username has already been taken, please choose another"}
 	}
-
-	if profile.Username == "" {
-		return errors.New("username cannot be left blank")
+	err = checkUsernameChange(user, profile.Username, time.Now(), usernameCooldown)
+	if err != nil {
+		return err
 	}
-
-	if len(profile.Bio) > 160 {
-		return errors.New("the bio is too long")
+	if profile.Username != user.Username && isReservedUsername(profile.Username, c.reservedUsernames()) {
+		return errReservedUsername
 	}
 
-	_, err = c.Model(user).
+	query := c.Model((*User)(nil)).
 		Where("id = ?", id).
 		Where("deleted_at IS NULL").
 		Set("full_name = ?", profile.FullName).
 		Set("username = ?", profile.Username).
 		Set("bio = ?", profile.Bio).
-		Set("avatar_url = ?", profile.AvatarURL).
-		Update()
+		Set("avatar_url = ?", profile.AvatarURL)
+	if profile.Username != user.Username {
+		query = query.Set("last_username_changed_at = NOW()")
+	}
+	result, err := query.Update()
 
 	if err != nil {
 		return err
 	}
 
+	if result.RowsAffected() == 0 {
+		return errors.New("invalid user")
+	}
+
+	return nil
+}
+
+// checkUsernameChange returns a ValidationError when the user changes their username again before the cooldown is over
+func checkUsernameChange(user *User, username string, now time.Time, cooldown time.Duration) error {
+	if username == user.Username || user.LastUsernameChangedAt == nil {
+		return nil
+	}
+	availableAt := user.LastUsernameChangedAt.Add(cooldown)
+	if now.Before(availableAt) {
+		return ValidationError{
+			Field:   "username",
+			Message: fmt.Sprintf("you have changed your username recently, you can change it again after %s", availableAt.UTC().Format("January 2, 2006 15:04 MST")),
+		}
+	}
+	return nil
+}
+
+// usernameTakenByOther returns whether the owner of a username, if any, is a user other than the one with the given id
+func usernameTakenByOther(owner *User, id int64) bool {
+	return owner != nil && owner.ID != id
+}
+
+// validateProfile checks the profile fields, returning a ValidationError for the first invalid one
+func validateProfile(profile *UserProfile) error {
+	if profile.FullName == "" {
+		return ValidationError{Field: "full_name", Message: "name cannot be left blank"}
+	}
+
+	if profile.Username == "" {
+		return ValidationError{Field: "username", Message: "username cannot be left blank"}
+	}
+
+	if len(profile.Bio) > 160 {
+		return ValidationError{Field: "bio", Message: "the bio is too long"}
+	}
+
 	return nil
 }
 
@@ -463,10 +638,14 @@ func (c *Client) SetUserCredentials(id int64, credentials *UserCredentials) erro
 	if !user.VerifiedAt.IsZero() {
 		return errors.New("this user already has credentials set and verified")
 	}
+	err = c.ensureEmailAvailable(id, credentials.Email)
+	if err != nil {
+		return err
+	}
 
 	hashedPassword, err := getHashedPassword(credentials.Password)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	_, err = c.Model(user).
@@ -474,6 +653,7 @@ func (c *Client) SetUserCredentials(id int64, credentials *UserCredentials) erro
 		Where("verified_at IS NULL").
 		Where("deleted_at IS NULL").
 		Set("email = ?", credentials.Email).
+		Set("canonical_email = ?", CanonicalEmail(credentials.Email)).
 		Set("password = ?", hashedPassword).
 		Update()
 
@@ -520,10 +700,14 @@ func (c *Client) RejectUserByID(id int64) error {
 
 // AddUser upserts the user into the database
 func (c *Client) AddUser(user *User) error {
+	if isReservedUsername(user.Username, c.reservedUsernames()) {
+		return errReservedUsername
+	}
 	user.Email = strings.ToLower(user.Email)
+	user.CanonicalEmail = CanonicalEmail(user.Email)
 	inserted, err := c.Model(user).
-		Where("LOWER(email) = ?", user.Email).
-		WhereOr("LOWER(username) = ?", strings.ToLower(user.Username)).
+		Where("(LOWER(email) = ? OR canonical_email = ? OR LOWER(username) = ?)", user.Email, user.CanonicalEmail, strings.ToLower(user.Username)).
+		Where("deleted_at IS NULL").
 		OnConflict("DO NOTHING").
 		SelectOrInsert()
 
@@ -570,6 +754,105 @@ func (c *Client) UnblacklistUser(id int64) error {
 	return nil
 }
 
+// DeleteUser soft deletes a user, freeing their email and username for re-registration
+func (c *Client) DeleteUser(id int64) error {
+	var user User
+	result, err := c.Model(&user).
+		Where("id = ?", id).
+		Where("deleted_at IS NULL").
+		Set("deleted_at = NOW()").
+		Update()
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("invalid user")
+	}
+
+	return nil
+}
+
+// DeletedUserName is shown in place of the name and username of deleted users
+const DeletedUserName = "[deleted]"
+
+// AnonymizeUser replaces the profile of a user with the deleted user tombstone,
+// so the comments and arguments they wrote show a "[deleted]" author. The original profile
+// is kept aside for RestoreUser, and the login sessions of the user are revoked.
+func (c *Client) AnonymizeUser(id int64, avatarURL string) error {
+	var user User
+	result, err := c.Model(&user).
+		Where("id = ?", id).
+		Set(`anonymized_profile = COALESCE(anonymized_profile, json_build_object(
+			'full_name', full_name, 'username', username, 'bio', bio, 'avatar_url', avatar_url)::jsonb)`).
+		Set("full_name = ?", DeletedUserName).
+		Set("username = ?", DeletedUserName).
+		Set("bio = ''").
+		Set("avatar_url = ?", avatarURL).
+		Set("sessions_revoked_at = NOW()").
+		Update()
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("invalid user")
+	}
+
+	return nil
+}
+
+// DeleteAccount soft deletes and anonymizes a user in a transaction
+func (c *Client) DeleteAccount(id int64, avatarURL string) error {
+	return c.WithTx(func(tx *Client) error {
+		err := tx.DeleteUser(id)
+		if err != nil {
+			return err
+		}
+		return tx.AnonymizeUser(id, avatarURL)
+	})
+}
+
+// RestoreUser restores a soft deleted user, along with their profile if it was anonymized.
+// It fails when their username was taken by another user in the meantime.
+func (c *Client) RestoreUser(id int64) error {
+	return c.WithTx(func(tx *Client) error {
+		user := new(User)
+		err := tx.Model(user).
+			Where("id = ?", id).
+			Where("deleted_at IS NOT NULL").
+			For("UPDATE").
+			Select()
+		if err == pg.ErrNoRows {
+			return errors.New("invalid user")
+		}
+		if err != nil {
+			return err
+		}
+
+		if user.AnonymizedProfile != nil {
+			taken, err := tx.UserByUsername(user.AnonymizedProfile.Username)
+			if err != nil {
+				return err
+			}
+			if taken != nil {
+				return errors.New("the username of the user was taken while they were deleted")
+			}
+		}
+
+		_, err = tx.Model(user).
+			Where("id = ?", id).
+			Set("full_name = COALESCE(anonymized_profile ->> 'full_name', full_name)").
+			Set("username = COALESCE(anonymized_profile ->> 'username', username)").
+			Set("bio = COALESCE(anonymized_profile ->> 'bio', bio)").
+			Set("avatar_url = COALESCE(anonymized_profile ->> 'avatar_url', avatar_url)").
+			Set("anonymized_profile = NULL").
+			Set("deleted_at = NULL").
+			Update()
+		return err
+	})
+}
+
 // ReferredUsers returns all the users who are invited
 func (c *Client) ReferredUsers() ([]User, error) {
 	var referredUsers = make([]User, 0)
@@ -639,29 +922,32 @@ func (c *Client) AddUserViaConnectedAccount(connectedAccount *ConnectedAccount,
 		ApprovedAt: time.Now(),
 	}
 
-	// setting referrer, if any
-	referrer, err := c.UserByAddress(referrerCode)
-	if err != nil {
-		return nil, err
-	}
-	if referrer != nil {
-		consumed, err := c.ConsumeInvite(referrer.ID)
+	// the invite is only consumed if the user and their connected account are created
+	err = c.WithTx(func(tx *Client) error {
+		// setting referrer, if any
+		referrer, err := tx.UserByAddress(referrerCode)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		if referrer != nil {
+			consumed, err := tx.ConsumeInvite(referrer.ID)
+			if err != nil {
+				return err
+			}
 
-		if consumed {
-			user.ReferredBy = referrer.ID
+			if consumed {
+				user.ReferredBy = referrer.ID
+			}
 		}
-	}
 
-	err = c.AddUser(user)
-	if err != nil {
-		return nil, err
-	}
+		err = tx.AddUser(user)
+		if err != nil {
+			return err
+		}
 
-	connectedAccount.UserID = user.ID
-	err = c.UpsertConnectedAccount(connectedAccount)
+		connectedAccount.UserID = user.ID
+		return tx.UpsertConnectedAccount(connectedAccount)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -695,12 +981,13 @@ func (c *Client) IsTwitterUser(userID int64) bool {
 }
 
 func getUniqueUsername(c *Client, username string, suffix string) (string, error) {
+	username = unreservedUsername(username)
 	candidate := username + suffix
 	user, err := c.UserByUsername(username + suffix)
 	if err != nil {
 		return "", err
 	}
-	if user != nil {
+	if user != nil || isReservedUsername(candidate, c.reservedUsernames()) {
 		intSuffix := 0
 		if suffix != "" {
 			intSuffix, err = strconv.Atoi(suffix)
@@ -714,16 +1001,57 @@ func getUniqueUsername(c *Client, username string, suffix string) (string, error
 	return candidate, nil
 }
 
+// defaultReservedUsernames are the usernames reserved when none are configured
+var defaultReservedUsernames = []string{
+	"admin", "administrator", "api", "help", "moderator", "official", "root",
+	"security", "staff", "support", "system", "team", "tru",
+}
+
+// fallbackUsername is suggested to users whose username from another platform is entirely the brand name
+const fallbackUsername = "user"
+
+var errReservedUsername = ValidationError{Field: "username", Message: "this username is reserved, please choose another"}
+
+func (c *Client) reservedUsernames() []string {
+	if len(c.config.App.ReservedUsernames) > 0 {
+		return c.config.App.ReservedUsernames
+	}
+	return defaultReservedUsernames
+}
+
+// isReservedUsername tells whether the username is one of the reserved ones, ignoring case, or relates to the brand
+func isReservedUsername(username string, reserved []string) bool {
+	if regex.HasTrustory(username) {
+		return true
+	}
+	for _, name := range reserved {
+		if strings.EqualFold(username, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// unreservedUsername drops the brand name out of a username taken from another platform,
+// so that it can be claimed once suffixed if it's still reserved
+func unreservedUsername(username string) string {
+	username = regex.RegexHasTrustory.ReplaceAllString(username, "")
+	if username == "" {
+		return fallbackUsername
+	}
+	return username
+}
+
 type UsernameAndImage struct {
 	Username  string `json:"username"`
 	AvatarURL string `json:"avatar_url"`
 }
 
-// UsernamesAndImagesByPrefix returns the first five usernames and their corresponding images for the provided prefix string
-func (c *Client) UsernamesAndImagesByPrefix(prefix string) (usernames []UsernameAndImage, err error) {
+// UsernamesAndImagesByPrefix returns the first usernames and their corresponding images for the provided prefix string,
+// limit defaults to five and is capped at twenty five
+func (c *Client) UsernamesAndImagesByPrefix(prefix string, limit int) (usernames []UsernameAndImage, err error) {
 	var users []User
-	sqlFragment := fmt.Sprintf("username ILIKE '%s", prefix)
-	err = c.Model(&users).Where(sqlFragment + "%'").Limit(5).Select()
+	err = c.Model(&users).Where("username ILIKE ?", usernamePrefixPattern(prefix)).Limit(usernameSearchLimit(limit)).Select()
 	if err == pg.ErrNoRows {
 		return usernames, nil
 	}
@@ -742,6 +1070,27 @@ func (c *Client) UsernamesAndImagesByPrefix(prefix string) (usernames []Username
 	return usernames, nil
 }
 
+const (
+	defaultUsernameSearchLimit = 5
+	maxUsernameSearchLimit     = 25
+)
+
+// usernameSearchLimit returns the number of username search results to return for the requested limit
+func usernameSearchLimit(limit int) int {
+	if limit <= 0 {
+		return defaultUsernameSearchLimit
+	}
+	if limit > maxUsernameSearchLimit {
+		return maxUsernameSearchLimit
+	}
+	return limit
+}
+
+// usernamePrefixPattern returns the ILIKE pattern matching usernames starting with the literal prefix
+func usernamePrefixPattern(prefix string) string {
+	return likeEscaper.Replace(prefix) + "%"
+}
+
 // UserProfileByAddress fetches user profile details by address
 func (c *Client) UserProfileByAddress(addr string) (*UserProfile, error) {
 	userProfile := new(UserProfile)
@@ -790,6 +1139,87 @@ func (c *Client) UsersByID(ids []int64) ([]User, error) {
 	return users, nil
 }
 
+// SetUserGroup assigns a user to a group and returns the group they previously belonged to
+func (c *Client) SetUserGroup(id int64, group UserGroup) (previous UserGroup, err error) {
+	if !group.IsValid() {
+		return previous, fmt.Errorf("invalid user group %d", group)
+	}
+	_, err = c.QueryOne(pg.Scan(&previous), `
+		UPDATE users
+		SET user_group = ?0, updated_at = NOW()
+		FROM (SELECT id, user_group FROM users WHERE id = ?1 FOR UPDATE) previous
+		WHERE users.id = previous.id
+		RETURNING previous.user_group
+	`, group, id)
+	if err == pg.ErrNoRows {
+		return previous, errors.New("invalid user")
+	}
+	return previous, err
+}
+
+// UsersByGroup returns the non-deleted users that belong to the given group
+func (c *Client) UsersByGroup(group UserGroup) ([]User, error) {
+	users := make([]User, 0)
+	err := c.Model(&users).
+		Where("user_group = ?", group).
+		Where("deleted_at IS NULL").
+		Order("id ASC").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UsersForExport fetches a batch of users with an id greater than afterID for admin exports,
+// optionally including soft-deleted users
+func (c *Client) UsersForExport(includeDeleted bool, afterID int64, limit int) ([]User, error) {
+	users := make([]User, 0)
+	q := c.Model(&users).Where("id > ?", afterID).Order("id ASC").Limit(limit)
+	if !includeDeleted {
+		q = q.Where("deleted_at IS NULL")
+	}
+	err := q.Select()
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ActiveUserCount counts the users that authenticated after the given time
+func (c *Client) ActiveUserCount(since time.Time) (int64, error) {
+	count, err := c.Model((*User)(nil)).
+		Where("last_authenticated_at > ?", since).
+		Where("blacklisted_at IS NULL").
+		Where("deleted_at IS NULL").
+		Count()
+	if err != nil {
+		return 0, err
+	}
+	return int64(count), nil
+}
+
+// ActiveParticipantCount counts the users that authenticated, commented or agreed after the given time
+func (c *Client) ActiveParticipantCount(since time.Time) (int64, error) {
+	var count int64
+	_, err := c.QueryOne(pg.Scan(&count), `
+		SELECT COUNT(*)
+		FROM users
+		WHERE
+			blacklisted_at IS NULL
+			AND deleted_at IS NULL
+			AND (
+				last_authenticated_at > ?0
+				OR address IN (SELECT creator FROM comments WHERE created_at > ?0)
+				OR address IN (SELECT address FROM leaderboard_user_metrics WHERE date >= date(?0) AND agrees_given > 0)
+			)
+	`, since)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // UserProfileByUsername fetches user profile by username
 func (c *Client) UserProfileByUsername(username string) (*UserProfile, error) {
 	userProfile := new(UserProfile)
@@ -832,6 +1262,59 @@ func (c *Client) GrantInvites(id int64, count int) error {
 	return nil
 }
 
+// GrantInvitesToGroup grants invites to all the non-deleted users of a group and records a ledger credit for each of them
+func (c *Client) GrantInvitesToGroup(group UserGroup, count int) (affected int64, err error) {
+	if !group.IsValid() {
+		return 0, fmt.Errorf("invalid user group %d", group)
+	}
+	if count <= 0 {
+		return 0, errors.New("count must be positive")
+	}
+
+	err = c.RunInTransaction(func(tx *pg.Tx) error {
+		userIDs := make([]int64, 0)
+		_, err := tx.Query(&userIDs, `
+			UPDATE users
+			SET invites_left = invites_left + ?0, updated_at = NOW()
+			WHERE user_group = ?1 AND deleted_at IS NULL
+			RETURNING id
+		`, count, group)
+		if err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		entries := inviteLedgerEntries(userIDs, count)
+		_, err = tx.Model(&entries).Insert()
+		if err != nil {
+			return err
+		}
+		affected = int64(len(userIDs))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// inviteLedgerEntries returns the ledger credits for granting invites to the given users
+func inviteLedgerEntries(userIDs []int64, count int) []RewardLedgerEntry {
+	entries := make([]RewardLedgerEntry, 0, len(userIDs))
+	for _, userID := range userIDs {
+		entries = append(entries, RewardLedgerEntry{
+			UserID:    userID,
+			Direction: RewardLedgerEntryDirectionCredit,
+			Amount:    int64(count),
+			Currency:  RewardLedgerEntryCurrencyInvite,
+		})
+	}
+	return entries
+}
+
 // ConsumeInvite consumes one invite, if available
 func (c *Client) ConsumeInvite(id int64) (bool, error) {
 	user := new(User)
@@ -856,12 +1339,15 @@ func (c *Client) ConsumeInvite(id int64) (bool, error) {
 	return true, nil
 }
 
+// journeyLengthExpr is the number of completed journey steps of a user,
+// counting a missing meta, a missing journey key or a non array journey as no steps
+const journeyLengthExpr = "COALESCE(jsonb_array_length(CASE WHEN jsonb_typeof(meta->'journey') = 'array' THEN meta->'journey' END), 0)"
+
 // UsersWithIncompleteJourney returns all the users who have not yet completed their journey
 func (c *Client) UsersWithIncompleteJourney() ([]User, error) {
 	var users = make([]User, 0)
 	err := c.Model(&users).
-		Where("meta ? 'journey' = false").
-		WhereOr("jsonb_array_length(meta->'journey') < ?", StepsToCompleteJourney).
+		Where(journeyLengthExpr+" < ?", StepsToCompleteJourney).
 		Select()
 	if err != nil {
 		return users, err
@@ -870,6 +1356,26 @@ func (c *Client) UsersWithIncompleteJourney() ([]User, error) {
 	return users, nil
 }
 
+// UsersWhoCompletedJourney returns all the users who have completed every step of their journey
+func (c *Client) UsersWhoCompletedJourney() ([]User, error) {
+	var users = make([]User, 0)
+	err := c.Model(&users).
+		Where("blacklisted_at IS NULL").
+		Where("deleted_at IS NULL").
+		Where(journeyLengthExpr+" >= ?", StepsToCompleteJourney).
+		Select()
+	if err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
+// HasCompletedJourney tells whether the user has completed every step of the journey
+func HasCompletedJourney(u User) bool {
+	return len(u.Meta.Journey) >= StepsToCompleteJourney
+}
+
 // UpdateUserJourney updates the user journey
 func (c *Client) UpdateUserJourney(id int64, journey []UserJourneyStep) error {
 	user, err := c.UserByID(id)
@@ -903,13 +1409,94 @@ func (c *Client) UnverifiedUsersWithinDays(days int64) ([]User, error) {
 	return users, nil
 }
 
-// RecordVerificationAttempt records a verification attempt
-func (c *Client) RecordVerificationAttempt(id int64) error {
+// StaleUnverifiedUsers returns users created more than the given days ago that never verified nor authenticated
+func (c *Client) StaleUnverifiedUsers(olderThanDays int64) ([]User, error) {
+	users := make([]User, 0)
+	err := c.Model(&users).
+		Where("blacklisted_at IS NULL").                                           // not blacklisted
+		Where("deleted_at IS NULL").                                               // not already deleted
+		Where("verified_at IS NULL").                                              // never verified
+		Where("last_authenticated_at IS NULL").                                    // never authenticated
+		Where("created_at < ?", staleUnverifiedCutoff(time.Now(), olderThanDays)). // is stale
+		Order("id ASC").
+		Select()
+	if err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
+// staleUnverifiedCutoff returns the creation time before which unverified users are considered stale
+func staleUnverifiedCutoff(now time.Time, olderThanDays int64) time.Time {
+	return now.AddDate(0, 0, -int(olderThanDays))
+}
+
+// InactiveUsersSince returns the verified users that haven't authenticated since the given time,
+// including the ones created before then that never authenticated
+func (c *Client) InactiveUsersSince(t time.Time) ([]User, error) {
+	users := make([]User, 0)
+	err := c.Model(&users).
+		Where("verified_at IS NOT NULL").
+		Where("blacklisted_at IS NULL").
+		Where("deleted_at IS NULL").
+		Where("created_at < ?", t).
+		Where("(last_authenticated_at IS NULL OR last_authenticated_at < ?)", t).
+		Order("id ASC").
+		Select()
+	if err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
+// CanAttemptVerification tells whether a user can be sent another verification email.
+// Attempts older than window no longer count towards maxAttempts.
+func (c *Client) CanAttemptVerification(id int64, cooldown, window time.Duration, maxAttempts int) (bool, error) {
+	user, err := c.UserByID(id)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return false, errors.New("invalid user")
+	}
+
+	return canAttemptVerification(user, time.Now(), cooldown, window, maxAttempts), nil
+}
+
+// canAttemptVerification checks the last attempt is outside the cooldown window and that
+// another attempt would not go over maxAttempts (0 means no limit) within the window
+func canAttemptVerification(user *User, now time.Time, cooldown, window time.Duration, maxAttempts int) bool {
+	if user.LastVerificationAttemptAt.IsZero() {
+		return true
+	}
+	since := now.Sub(user.LastVerificationAttemptAt)
+	if since < cooldown {
+		return false
+	}
+	// the count resets once the window since the last attempt is over
+	if since >= window {
+		return true
+	}
+	if maxAttempts > 0 && user.VerificationAttemptCount >= maxAttempts {
+		return false
+	}
+
+	return true
+}
+
+// RecordVerificationAttempt records a verification attempt, restarting the count when the last
+// attempt is older than windowStart. It should only be called after CanAttemptVerification.
+func (c *Client) RecordVerificationAttempt(id int64, windowStart time.Time) error {
 	var user User
 	_, err := c.Model(&user).
 		Where("id = ?", id).
 		Set("last_verification_attempt_at = NOW()").
-		Set("verification_attempt_count = verification_attempt_count + 1").
+		Set(`verification_attempt_count = CASE
+			WHEN last_verification_attempt_at > ? THEN verification_attempt_count + 1
+			ELSE 1
+		END`, windowStart).
 		Update()
 
 	if err != nil {
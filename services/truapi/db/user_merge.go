@@ -0,0 +1,101 @@
+package db
+
+import (
+	"errors"
+)
+
+// userMergeStatement is a statement run when merging a user account into another
+type userMergeStatement struct {
+	query  string
+	params []interface{}
+}
+
+// MergeUsers merges the account of a user who signed up twice into their primary account.
+// The comments, connected accounts, referrals, invites and ledger entries of the secondary user
+// are moved over to the primary user, and the secondary user is soft deleted, all in a transaction.
+func (c *Client) MergeUsers(primaryID, secondaryID int64) error {
+	if primaryID == secondaryID {
+		return errors.New("a user can't be merged into themselves")
+	}
+
+	return c.WithTx(func(tx *Client) error {
+		primary, err := tx.UserByID(primaryID)
+		if err != nil {
+			return err
+		}
+		secondary, err := tx.UserByID(secondaryID)
+		if err != nil {
+			return err
+		}
+		err = validateUserMerge(primary, secondary)
+		if err != nil {
+			return err
+		}
+
+		for _, statement := range userMergeStatements(primary, secondary) {
+			_, err = tx.Exec(statement.query, statement.params...)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// validateUserMerge checks that both users exist, are still active and that the primary user
+// has an address to take over the comments of the secondary one
+func validateUserMerge(primary, secondary *User) error {
+	if primary == nil || secondary == nil {
+		return errors.New("no such user found")
+	}
+	if primary.ID == secondary.ID {
+		return errors.New("a user can't be merged into themselves")
+	}
+	if primary.DeletedAt != nil || secondary.DeletedAt != nil {
+		return errors.New("deleted users can't be merged")
+	}
+	if primary.Address == "" {
+		return errors.New("users can't be merged into a user without an address")
+	}
+	return nil
+}
+
+// userMergeStatements returns the statements moving what belongs to the secondary user over to the primary one,
+// ending with the soft deletion of the secondary user
+func userMergeStatements(primary, secondary *User) []userMergeStatement {
+	statements := make([]userMergeStatement, 0)
+	// comments are authored by address, which users without one can't have
+	if secondary.Address != "" {
+		statements = append(statements, userMergeStatement{
+			query:  `UPDATE comments SET creator = ?, updated_at = NOW() WHERE creator = ?`,
+			params: []interface{}{primary.Address, secondary.Address},
+		})
+	}
+	return append(statements,
+		userMergeStatement{
+			query:  `UPDATE connected_accounts SET user_id = ?, updated_at = NOW() WHERE user_id = ?`,
+			params: []interface{}{primary.ID, secondary.ID},
+		},
+		// the primary user can't end up referring themselves
+		userMergeStatement{
+			query:  `UPDATE users SET referred_by = NULL, updated_at = NOW() WHERE id = ? AND referred_by = ?`,
+			params: []interface{}{primary.ID, secondary.ID},
+		},
+		userMergeStatement{
+			query:  `UPDATE users SET referred_by = ?, updated_at = NOW() WHERE referred_by = ?`,
+			params: []interface{}{primary.ID, secondary.ID},
+		},
+		userMergeStatement{
+			query:  `UPDATE reward_ledger_entries SET user_id = ?, updated_at = NOW() WHERE user_id = ?`,
+			params: []interface{}{primary.ID, secondary.ID},
+		},
+		userMergeStatement{
+			query:  `UPDATE users SET invites_left = invites_left + ?, updated_at = NOW() WHERE id = ?`,
+			params: []interface{}{secondary.InvitesLeft, primary.ID},
+		},
+		userMergeStatement{
+			query:  `UPDATE users SET invites_left = 0, deleted_at = NOW(), updated_at = NOW() WHERE id = ? AND deleted_at IS NULL`,
+			params: []interface{}{secondary.ID},
+		},
+	)
+}
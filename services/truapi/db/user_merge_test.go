@@ -0,0 +1,64 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUserMerge(t *testing.T) {
+	deletedAt := time.Now()
+	primary := &User{ID: 1, Address: "primary-address"}
+	assert.NoError(t, validateUserMerge(primary, &User{ID: 2}))
+	assert.Error(t, validateUserMerge(primary, nil))
+	assert.Error(t, validateUserMerge(primary, primary))
+	assert.Error(t, validateUserMerge(primary, &User{ID: 2, Timestamps: Timestamps{DeletedAt: &deletedAt}}))
+	assert.Error(t, validateUserMerge(&User{ID: 1}, &User{ID: 2, Address: "secondary-address"}))
+
+	// checked before reaching the database
+	assert.EqualError(t, (&Client{}).MergeUsers(1, 1), "a user can't be merged into themselves")
+}
+
+func TestMergeUsers(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	primary := createTestUser(t, c, "mergeprimary")
+	secondary := createTestUser(t, c, "mergesecondary")
+	referred := createTestUser(t, c, "mergereferred")
+	_, err := c.Exec(`UPDATE users SET address = ?, invites_left = 2 WHERE id = ?`, "merge-primary-address", primary.ID)
+	require.NoError(t, err)
+	_, err = c.Exec(`UPDATE users SET address = ?, invites_left = 3 WHERE id = ?`, "merge-secondary-address", secondary.ID)
+	require.NoError(t, err)
+	_, err = c.Exec(`UPDATE users SET referred_by = ? WHERE id = ?`, secondary.ID, referred.ID)
+	require.NoError(t, err)
+	// the primary user was referred by their other account
+	_, err = c.Exec(`UPDATE users SET referred_by = ? WHERE id = ?`, secondary.ID, primary.ID)
+	require.NoError(t, err)
+	comment := createTestComment(t, c, 917303, "merge-secondary-address", time.Now())
+
+	require.NoError(t, c.MergeUsers(primary.ID, secondary.ID))
+
+	moved, err := c.CommentByID(comment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "merge-primary-address", moved.Creator)
+
+	referredAfter, err := c.UserByID(referred.ID)
+	require.NoError(t, err)
+	assert.Equal(t, primary.ID, referredAfter.ReferredBy)
+
+	primaryAfter, err := c.UserByID(primary.ID)
+	require.NoError(t, err)
+	assert.Zero(t, primaryAfter.ReferredBy)
+	assert.Equal(t, int64(5), primaryAfter.InvitesLeft)
+
+	secondaryAfter := new(User)
+	require.NoError(t, c.Model(secondaryAfter).Where("id = ?", secondary.ID).Select())
+	assert.NotNil(t, secondaryAfter.DeletedAt)
+	assert.Zero(t, secondaryAfter.InvitesLeft)
+
+	// the secondary user is gone, so merging again fails
+	assert.Error(t, c.MergeUsers(primary.ID, secondary.ID))
+}
@@ -0,0 +1,541 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanAttemptVerification(t *testing.T) {
+	now := time.Now()
+	cooldown := 5 * time.Minute
+	window := 7 * 24 * time.Hour
+	tests := []struct {
+		name        string
+		lastAttempt time.Time
+		count       int
+		maxAttempts int
+		expected    bool
+	}{
+		{"never attempted", time.Time{}, 0, 3, true},
+		{"within cooldown", now.Add(-cooldown + time.Second), 1, 3, false},
+		{"at cooldown boundary", now.Add(-cooldown), 1, 3, true},
+		{"after cooldown", now.Add(-cooldown - time.Second), 1, 3, true},
+		{"at max attempts", now.Add(-time.Hour), 3, 3, false},
+		{"below max attempts", now.Add(-time.Hour), 2, 3, true},
+		{"no attempts limit", now.Add(-time.Hour), 100, 0, true},
+		{"max attempts just inside the window", now.Add(-window + time.Second), 3, 3, false},
+		{"max attempts at the window boundary", now.Add(-window), 3, 3, true},
+	}
+	for _, tt := range tests {
+		user := &User{LastVerificationAttemptAt: tt.lastAttempt, VerificationAttemptCount: tt.count}
+		assert.Equal(t, tt.expected, canAttemptVerification(user, now, cooldown, window, tt.maxAttempts), tt.name)
+	}
+}
+
+func TestUsernamePrefixPattern(t *testing.T) {
+	assert.Equal(t, "shane%", usernamePrefixPattern("shane"))
+	assert.Equal(t, "o'brien%", usernamePrefixPattern("o'brien"))
+	assert.Equal(t, `100\%%`, usernamePrefixPattern("100%"))
+	assert.Equal(t, `d\_truth%`, usernamePrefixPattern("d_truth"))
+	assert.Equal(t, `back\\slash%`, usernamePrefixPattern(`back\slash`))
+}
+
+func TestUsernameSearchLimit(t *testing.T) {
+	assert.Equal(t, 5, usernameSearchLimit(0))
+	assert.Equal(t, 10, usernameSearchLimit(10))
+	assert.Equal(t, 25, usernameSearchLimit(25))
+	assert.Equal(t, 25, usernameSearchLimit(100))
+}
+
+func TestUserGroupIsValid(t *testing.T) {
+	for _, group := range []UserGroup{UserGroupUser, UserGroupEmployee, UserGroupTruStoryDebater, UserGroupResearchAnalyst} {
+		assert.True(t, group.IsValid(), group.String())
+	}
+	assert.False(t, UserGroup(-1).IsValid())
+	assert.False(t, UserGroup(len(userGroupTypeName)).IsValid())
+}
+
+func TestInviteLedgerEntries(t *testing.T) {
+	userIDs := []int64{3, 7, 11}
+	entries := inviteLedgerEntries(userIDs, 5)
+	assert.Len(t, entries, len(userIDs))
+	for i, entry := range entries {
+		assert.Equal(t, userIDs[i], entry.UserID)
+		assert.Equal(t, int64(5), entry.Amount)
+		assert.Equal(t, RewardLedgerEntryDirectionCredit, entry.Direction)
+		assert.Equal(t, RewardLedgerEntryCurrencyInvite, entry.Currency)
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	valid := UserProfile{FullName: "Shane", Username: "shanev", Bio: "bio"}
+	assert.NoError(t, validateProfile(&valid))
+
+	tests := []struct {
+		profile UserProfile
+		field   string
+		message string
+	}{
+		{UserProfile{Username: "shanev"}, "full_name", "name cannot be left blank"},
+		{UserProfile{FullName: "Shane"}, "username", "username cannot be left blank"},
+		{UserProfile{FullName: "Shane", Username: "shanev", Bio: strings.Repeat("a", 161)}, "bio", "the bio is too long"},
+	}
+	for _, tt := range tests {
+		err := validateProfile(&tt.profile)
+		validationErr, ok := err.(ValidationError)
+		assert.True(t, ok)
+		assert.Equal(t, tt.field, validationErr.Field)
+		assert.Equal(t, tt.message, err.Error())
+	}
+}
+
+func TestUsernameTakenByOther(t *testing.T) {
+	// a brand-new username has no owner
+	assert.False(t, usernameTakenByOther(nil, 1))
+	// keeping your own username
+	assert.False(t, usernameTakenByOther(&User{ID: 1}, 1))
+	assert.True(t, usernameTakenByOther(&User{ID: 2}, 1))
+}
+
+func TestStaleUnverifiedCutoff(t *testing.T) {
+	now := time.Date(2019, 10, 31, 12, 0, 0, 0, time.UTC)
+	cutoff := staleUnverifiedCutoff(now, 30)
+	assert.Equal(t, time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC), cutoff)
+
+	// users straddling the threshold
+	assert.True(t, now.AddDate(0, 0, -31).Before(cutoff))
+	assert.True(t, cutoff.Add(-time.Second).Before(cutoff))
+	assert.False(t, cutoff.Before(cutoff))
+	assert.False(t, now.AddDate(0, 0, -29).Before(cutoff))
+}
+
+func TestHasCompletedJourney(t *testing.T) {
+	threeSteps := User{Meta: UserMeta{Journey: []UserJourneyStep{
+		JourneyStepSignedUp,
+		JourneyStepOneArgument,
+		JourneyStepGivenOneAgree,
+	}}}
+	assert.False(t, HasCompletedJourney(threeSteps))
+	assert.False(t, HasCompletedJourney(User{}))
+	assert.False(t, HasCompletedJourney(User{Meta: UserMeta{Journey: []UserJourneyStep{}}}))
+
+	fourSteps := threeSteps
+	fourSteps.Meta.Journey = append(threeSteps.Meta.Journey, JourneyStepReceiveFiveAgrees)
+	assert.True(t, HasCompletedJourney(fourSteps))
+}
+
+func TestCheckUsernameChange(t *testing.T) {
+	now := time.Date(2019, 10, 31, 12, 0, 0, 0, time.UTC)
+	cooldown := 30 * 24 * time.Hour
+	changedAt := now.AddDate(0, 0, -10)
+
+	// a user who never changed their username
+	user := &User{Username: "shane"}
+	assert.NoError(t, checkUsernameChange(user, "shanev", now, cooldown))
+
+	// inside the cooldown, only the username is blocked
+	user = &User{Username: "shanev", LastUsernameChangedAt: &changedAt}
+	err := checkUsernameChange(user, "shane", now, cooldown)
+	assert.Equal(t, "username", err.(ValidationError).Field)
+	assert.Contains(t, err.Error(), "November 20, 2019 12:00 UTC")
+	assert.NoError(t, checkUsernameChange(user, "shanev", now, cooldown))
+
+	// after the cooldown
+	assert.NoError(t, checkUsernameChange(user, "shane", changedAt.Add(cooldown), cooldown))
+	assert.NoError(t, checkUsernameChange(user, "shane", now.AddDate(0, 1, 0), cooldown))
+}
+
+func TestIsReservedUsername(t *testing.T) {
+	reserved := []string{"admin", "support"}
+	// exact matches, ignoring case
+	assert.True(t, isReservedUsername("admin", reserved))
+	assert.True(t, isReservedUsername("Support", reserved))
+	// anything related to the brand
+	assert.True(t, isReservedUsername("TruStory", reserved))
+	assert.True(t, isReservedUsername("the_trustory_team", reserved))
+	// allowed names
+	assert.False(t, isReservedUsername("shanev", reserved))
+	assert.False(t, isReservedUsername("admin_shane", reserved))
+	assert.False(t, isReservedUsername("admin", nil))
+}
+
+func TestUnreservedUsername(t *testing.T) {
+	assert.Equal(t, "shanev", unreservedUsername("shanev"))
+	assert.Equal(t, "Fan", unreservedUsername("TruStoryFan"))
+	assert.Equal(t, fallbackUsername, unreservedUsername("trustory"))
+}
+
+func TestNotificationPreferencesEnabled(t *testing.T) {
+	var unset NotificationPreferences
+	assert.True(t, unset.Enabled(NotificationCommentAction))
+
+	preferences := NotificationPreferences{NotificationCommentAction: false, NotificationGift: true}
+	assert.False(t, preferences.Enabled(NotificationCommentAction))
+	assert.True(t, preferences.Enabled(NotificationGift))
+	assert.True(t, preferences.Enabled(NotificationRewardTruUnlocked))
+}
+
+func TestDeleteUserAllowsReRegistration(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	deleted := createTestUser(t, c, "reregistered")
+	assert.Error(t, c.AddUser(&User{FullName: "again", Username: "someoneelse", Email: "reregistered@trustory.io"}))
+
+	assert.NoError(t, c.DeleteUser(deleted.ID))
+	assert.Error(t, c.DeleteUser(deleted.ID))
+
+	user, err := c.UserByEmail("reregistered@trustory.io")
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+	user, err = c.UserByUsername("reregistered")
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+
+	registered := createTestUser(t, c, "reregistered")
+	assert.NotEqual(t, deleted.ID, registered.ID)
+	user, err = c.UserByEmail("reregistered@trustory.io")
+	assert.NoError(t, err)
+	assert.Equal(t, registered.ID, user.ID)
+}
+
+func TestDeleteAccountAndRestoreUser(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	user := createTestUser(t, c, "restored")
+	assert.NoError(t, c.DeleteAccount(user.ID, "https://trustory.io/deleted.png"))
+	deleted, err := c.UserByID(user.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, deleted.DeletedAt)
+	assert.NotNil(t, deleted.SessionsRevokedAt)
+	assert.Equal(t, DeletedUserName, deleted.Username)
+	assert.Equal(t, DeletedUserName, deleted.FullName)
+	assert.Equal(t, "restored", deleted.AnonymizedProfile.Username)
+
+	// a deleted account can't be deleted again, leaving it untouched
+	assert.Error(t, c.DeleteAccount(user.ID, "https://trustory.io/other.png"))
+
+	assert.NoError(t, c.RestoreUser(user.ID))
+	restored, err := c.UserByID(user.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	assert.Nil(t, restored.AnonymizedProfile)
+	assert.Equal(t, "restored", restored.Username)
+	assert.Equal(t, "restored", restored.FullName)
+	// sessions from before the deletion stay revoked
+	assert.NotNil(t, restored.SessionsRevokedAt)
+	assert.Error(t, c.RestoreUser(user.ID))
+}
+
+func TestRestoreUserWithTakenUsername(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	user := createTestUser(t, c, "takenwhiledeleted")
+	assert.NoError(t, c.DeleteAccount(user.ID, ""))
+	createTestUser(t, c, "takenwhiledeleted")
+
+	assert.Error(t, c.RestoreUser(user.ID))
+	stillDeleted, err := c.UserByID(user.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, stillDeleted.DeletedAt)
+}
+
+func TestUsersByGroup(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	alice := createTestUser(t, c, "groupalice")
+	bob := createTestUser(t, c, "groupbob")
+	carol := createTestUser(t, c, "groupcarol")
+	dave := createTestUser(t, c, "groupdave")
+	erin := createTestUser(t, c, "grouperin")
+	for _, user := range []*User{alice, carol, dave} {
+		_, err := c.SetUserGroup(user.ID, UserGroupEmployee)
+		require.NoError(t, err)
+	}
+	_, err := c.SetUserGroup(erin.ID, UserGroupResearchAnalyst)
+	require.NoError(t, err)
+	require.NoError(t, c.DeleteUser(dave.ID))
+
+	ours := func(group UserGroup) []int64 {
+		t.Helper()
+		users, err := c.UsersByGroup(group)
+		require.NoError(t, err)
+		ids := make([]int64, 0)
+		for _, user := range users {
+			switch user.ID {
+			case alice.ID, bob.ID, carol.ID, dave.ID, erin.ID:
+				assert.Equal(t, group, user.UserGroup)
+				ids = append(ids, user.ID)
+			}
+		}
+		return ids
+	}
+	// deleted users are left out, and the others are ordered by id
+	assert.Equal(t, []int64{alice.ID, carol.ID}, ours(UserGroupEmployee))
+	assert.Equal(t, []int64{bob.ID}, ours(UserGroupUser))
+	assert.Equal(t, []int64{erin.ID}, ours(UserGroupResearchAnalyst))
+	assert.Empty(t, ours(UserGroupTruStoryDebater))
+}
+
+func TestEmailChange(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	alice := createTestUser(t, c, "emailalice")
+	createTestUser(t, c, "emailbob")
+	email := func(id int64) string {
+		t.Helper()
+		user, err := c.UserByID(id)
+		require.NoError(t, err)
+		return user.Email
+	}
+
+	_, err := c.InitiateEmailChange(alice.ID, "not an email")
+	assert.EqualError(t, err, "invalid email")
+	// taken by another user, or one of its aliases
+	_, err = c.InitiateEmailChange(alice.ID, "EmailBob@trustory.io")
+	assert.EqualError(t, err, "a user already exists with same email")
+	_, err = c.InitiateEmailChange(alice.ID, "emailbob+alias@trustory.io")
+	assert.EqualError(t, err, "a user already exists with same email")
+
+	token, err := c.InitiateEmailChange(alice.ID, " Alice.New@trustory.io ")
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.EqualError(t, c.ConfirmEmailChange(alice.ID, ""), "invalid token")
+	assert.EqualError(t, c.ConfirmEmailChange(alice.ID, "bad"+token), "invalid token")
+	assert.Equal(t, "emailalice@trustory.io", email(alice.ID))
+
+	require.NoError(t, c.ConfirmEmailChange(alice.ID, token))
+	user, err := c.UserByID(alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice.new@trustory.io", user.Email)
+	assert.Empty(t, user.PendingEmail)
+	assert.Empty(t, user.PendingEmailToken)
+	// a token can only be used once
+	assert.EqualError(t, c.ConfirmEmailChange(alice.ID, token), "invalid token")
+
+	// the email was taken by someone else before the change was confirmed
+	token, err = c.InitiateEmailChange(alice.ID, "emailcarol@trustory.io")
+	require.NoError(t, err)
+	createTestUser(t, c, "emailcarol")
+	assert.EqualError(t, c.ConfirmEmailChange(alice.ID, token), "a user already exists with same email")
+	assert.Equal(t, "alice.new@trustory.io", email(alice.ID))
+}
+
+func TestUpdateProfileToNewUsername(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	alice := createTestUser(t, c, "profilealice")
+	bob := createTestUser(t, c, "profilebob")
+
+	profile := &UserProfile{FullName: "Alice", Username: "profilealice2", Bio: "new bio", AvatarURL: "https://trustory.io/alice.png"}
+	require.NoError(t, c.UpdateProfile(alice.ID, profile, time.Hour))
+	user, err := c.UserByID(alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "profilealice2", user.Username)
+	assert.Equal(t, "Alice", user.FullName)
+	assert.Equal(t, "new bio", user.Bio)
+	assert.Equal(t, "https://trustory.io/alice.png", user.AvatarURL)
+	assert.NotNil(t, user.LastUsernameChangedAt)
+
+	// the other user is untouched
+	user, err = c.UserByID(bob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "profilebob", user.Username)
+
+	// taken by another user
+	err = c.UpdateProfile(bob.ID, &UserProfile{FullName: "Bob", Username: "profilealice2"}, time.Hour)
+	assert.Error(t, err)
+	// changed again within the cooldown
+	err = c.UpdateProfile(alice.ID, &UserProfile{FullName: "Alice", Username: "profilealice3"}, time.Hour)
+	assert.Error(t, err)
+	user, err = c.UserByID(alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "profilealice2", user.Username)
+}
+
+func TestStaleUnverifiedUsers(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -30)
+	lastAuthenticated := now.AddDate(0, 0, -40)
+	add := func(username string, createdAt time.Time, user User) int64 {
+		t.Helper()
+		user.FullName = username
+		user.Username = username
+		user.Email = username + "@trustory.io"
+		user.CreatedAt = createdAt
+		require.NoError(t, c.AddUser(&user))
+		return user.ID
+	}
+	old := cutoff.AddDate(0, 0, -1)
+	stale := add("staleold", old, User{})
+	// users straddling the threshold
+	justStale := add("stalejust", cutoff.Add(-time.Minute), User{})
+	notYetStale := add("stalenotyet", cutoff.Add(time.Minute), User{})
+	verified := add("staleverified", old, User{VerifiedAt: old})
+	authenticated := add("staleauthenticated", old, User{LastAuthenticatedAt: &lastAuthenticated})
+	blacklisted := add("staleblacklisted", old, User{BlacklistedAt: old})
+	deleted := add("staledeleted", old, User{})
+	require.NoError(t, c.DeleteUser(deleted))
+
+	users, err := c.StaleUnverifiedUsers(30)
+	require.NoError(t, err)
+	ids := make(map[int64]bool)
+	for _, user := range users {
+		ids[user.ID] = true
+	}
+	assert.True(t, ids[stale])
+	assert.True(t, ids[justStale])
+	assert.False(t, ids[notYetStale])
+	assert.False(t, ids[verified])
+	assert.False(t, ids[authenticated])
+	assert.False(t, ids[blacklisted])
+	assert.False(t, ids[deleted])
+}
+
+func TestUsersWithIncompleteJourney(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	setMeta := func(id int64, meta string) {
+		t.Helper()
+		_, err := c.Exec("UPDATE users SET meta = ? WHERE id = ?", meta, id)
+		require.NoError(t, err)
+	}
+	missingMeta := createTestUser(t, c, "journeymissing")
+	_, err := c.Exec("UPDATE users SET meta = NULL WHERE id = ?", missingMeta.ID)
+	require.NoError(t, err)
+	noJourney := createTestUser(t, c, "journeynone")
+	setMeta(noJourney.ID, `{}`)
+	empty := createTestUser(t, c, "journeyempty")
+	setMeta(empty.ID, `{"journey": []}`)
+	notArray := createTestUser(t, c, "journeynotarray")
+	setMeta(notArray.ID, `{"journey": "signed_up"}`)
+	partial := createTestUser(t, c, "journeypartial")
+	setMeta(partial.ID, `{"journey": ["signed_up", "one_argument"]}`)
+	full := createTestUser(t, c, "journeyfull")
+	setMeta(full.ID, `{"journey": ["signed_up", "one_argument", "given_one_agree", "received_five_agrees"]}`)
+
+	users, err := c.UsersWithIncompleteJourney()
+	require.NoError(t, err)
+	ids := make(map[int64]bool)
+	for _, user := range users {
+		ids[user.ID] = true
+	}
+	assert.True(t, ids[missingMeta.ID])
+	assert.True(t, ids[noJourney.ID])
+	assert.True(t, ids[empty.ID])
+	assert.True(t, ids[notArray.ID])
+	assert.True(t, ids[partial.ID])
+	assert.False(t, ids[full.ID])
+}
+
+func TestRegisterUserFailureKeepsInvite(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	referrer := createTestUser(t, c, "registerreferrer")
+	_, err := c.Exec("UPDATE users SET address = ? WHERE id = ?", "register-referrer-address", referrer.ID)
+	require.NoError(t, err)
+	require.NoError(t, c.GrantInvites(referrer.ID, 2))
+	existing := createTestUser(t, c, "registerexisting")
+
+	invitesLeft := func() int64 {
+		t.Helper()
+		user, err := c.UserByID(referrer.ID)
+		require.NoError(t, err)
+		return user.InvitesLeft
+	}
+
+	// the user can't be added with a taken email, so the invite must not be consumed
+	taken := &User{FullName: "Taken", Username: "registertaken", Email: existing.Email, Password: "password"}
+	assert.Error(t, c.RegisterUser(taken, "register-referrer-address", ""))
+	assert.EqualValues(t, 2, invitesLeft())
+
+	registered := &User{FullName: "Registered", Username: "registered", Email: "registered@trustory.io", Password: "password"}
+	require.NoError(t, c.RegisterUser(registered, "register-referrer-address", ""))
+	assert.EqualValues(t, 1, invitesLeft())
+	assert.Equal(t, referrer.ID, registered.ReferredBy)
+}
+
+func TestSetUserGroup(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	user := createTestUser(t, c, "setgroupuser")
+	previous := UserGroup(UserGroupUser)
+	for _, group := range []UserGroup{UserGroupEmployee, UserGroupTruStoryDebater, UserGroupResearchAnalyst, UserGroupUser} {
+		got, err := c.SetUserGroup(user.ID, group)
+		require.NoError(t, err, group.String())
+		assert.Equal(t, previous, got, group.String())
+		stored, err := c.UserByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, group, stored.UserGroup)
+		previous = group
+	}
+
+	// out of range groups are rejected and leave the user untouched
+	_, err := c.SetUserGroup(user.ID, UserGroup(len(userGroupTypeName)))
+	assert.Error(t, err)
+	_, err = c.SetUserGroup(user.ID, UserGroup(-1))
+	assert.Error(t, err)
+	stored, err := c.UserByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, UserGroup(UserGroupUser), stored.UserGroup)
+
+	_, err = c.SetUserGroup(-1, UserGroupEmployee)
+	assert.EqualError(t, err, "invalid user")
+}
+
+func TestInactiveUsersSince(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	since := time.Now().UTC().AddDate(0, 0, -30).Truncate(time.Second)
+	add := func(username string, lastAuthenticatedAt *time.Time, verified bool) *User {
+		t.Helper()
+		user := createTestUser(t, c, username)
+		_, err := c.Exec("UPDATE users SET created_at = ?, last_authenticated_at = ? WHERE id = ?", since.AddDate(0, 0, -60), lastAuthenticatedAt, user.ID)
+		require.NoError(t, err)
+		if verified {
+			_, err = c.Exec("UPDATE users SET verified_at = NOW() WHERE id = ?", user.ID)
+			require.NoError(t, err)
+		}
+		return user
+	}
+	dormant := since.AddDate(0, 0, -10)
+	recent := since.AddDate(0, 0, 20)
+	neverLoggedIn := add("inactivenever", nil, true)
+	dormantUser := add("inactivedormant", &dormant, true)
+	recentUser := add("inactiverecent", &recent, true)
+	atCutoff := add("inactiveatcutoff", &since, true)
+	unverified := add("inactiveunverified", &dormant, false)
+	newUser := createTestUser(t, c, "inactivenew")
+	_, err := c.Exec("UPDATE users SET verified_at = NOW() WHERE id = ?", newUser.ID)
+	require.NoError(t, err)
+
+	users, err := c.InactiveUsersSince(since)
+	require.NoError(t, err)
+	ids := make(map[int64]bool)
+	for _, user := range users {
+		ids[user.ID] = true
+	}
+	assert.True(t, ids[neverLoggedIn.ID])
+	assert.True(t, ids[dormantUser.ID])
+	assert.False(t, ids[recentUser.ID])
+	assert.False(t, ids[atCutoff.ID])
+	assert.False(t, ids[unverified.ID])
+	// created after the cutoff, so not inactive yet
+	assert.False(t, ids[newUser.ID])
+}
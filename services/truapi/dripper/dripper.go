@@ -29,6 +29,8 @@ type Dripper struct {
 	Endpoint         string
 	APIKey           string
 	WorkflowRegistry map[string]*Workflow
+	// HTTPClient is the client calling the Mailchimp API
+	HTTPClient *http.Client
 }
 
 // MailchimpError represents the error from the Mailchimp API
@@ -60,6 +62,7 @@ func NewVanillaDripper(key string) (*Dripper, error) {
 		Endpoint:         strings.Replace(MailchimpAPIEndpoint, "REGION", parts[1], -1),
 		APIKey:           key,
 		WorkflowRegistry: make(map[string]*Workflow),
+		HTTPClient:       getHTTPClient(),
 	}
 
 	return dripper, nil
@@ -127,7 +130,7 @@ func (workflow *Workflow) Subscribe(email string) error {
 	if err != nil {
 		return err
 	}
-	response, err := getHTTPClient().Do(request)
+	response, err := workflow.Dripper.HTTPClient.Do(request)
 	if err != nil {
 		return err
 	}
@@ -173,7 +176,7 @@ func (workflow *Workflow) addToAudience(email string) error {
 	if err != nil {
 		return err
 	}
-	response, err := getHTTPClient().Do(request)
+	response, err := workflow.Dripper.HTTPClient.Do(request)
 	if err != nil {
 		return err
 	}
@@ -203,7 +206,7 @@ func (workflow *Workflow) getRecipients() (*WorkflowRecipients, error) {
 	if err != nil {
 		return nil, err
 	}
-	response, err := getHTTPClient().Do(request)
+	response, err := workflow.Dripper.HTTPClient.Do(request)
 	if err != nil {
 		return nil, err
 	}
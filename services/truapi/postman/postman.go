@@ -3,14 +3,12 @@ package postman
 import (
 	"html/template"
 
-	
 	"github.com/TruStory/octopus/services/truapi/context"
-	
+
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ses"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	packr "github.com/gobuffalo/packr/v2"
 )
 
 // Postman is the client
@@ -20,6 +18,9 @@ type Postman struct {
 	CharSet  string
 	SES      *ses.SES
 	Messages map[string]*template.Template
+	// Templates holds every loaded template keyed by locale and name
+	Templates     map[string]*template.Template
+	DefaultLocale string
 }
 
 // Message represents an email that can be sent
@@ -32,24 +33,22 @@ type Message struct {
 
 // NewVanillaPostman creates the client without the truapi dependency
 func NewVanillaPostman(region, sender, key, secret string) (*Postman, error) {
+	return NewPostmanWithTemplates(region, sender, key, secret, TemplateOptions{})
+}
+
+// NewPostmanWithTemplates creates the client using the templates configured in the options
+func NewPostmanWithTemplates(region, sender, key, secret string, options TemplateOptions) (*Postman, error) {
+	if options.DefaultLocale == "" {
+		options.DefaultLocale = defaultLocale
+	}
 	// setting up all message templates
-	box := packr.New("Email Templates", "./templates")
-	templates := []string{
-		"register", "invitation", "password-reset", "email-confirmation",
+	templates, err := loadTemplates(options)
+	if err != nil {
+		return nil, err
 	}
 	messages := make(map[string]*template.Template)
-	for _, templateName := range templates {
-		templateFilename := templateName + ".html.tmpl"
-		filename, err := box.FindString(templateFilename)
-		if err != nil {
-			return nil, err
-		}
-		parsedTemplate, err := template.New(templateFilename).Parse(filename)
-		if err != nil {
-			return nil, err
-		}
-
-		messages[templateName] = parsedTemplate
+	for _, templateName := range defaultTemplates {
+		messages[templateName] = templates[templateKey(options.DefaultLocale, templateName)]
 	}
 	session, err := session.NewSession(&aws.Config{
 		Region:      aws.String(region),
@@ -61,17 +60,23 @@ func NewVanillaPostman(region, sender, key, secret string) (*Postman, error) {
 
 	// returning the client
 	return &Postman{
-		Region:   region,
-		Sender:   sender,
-		CharSet:  "UTF-8",
-		SES:      ses.New(session),
-		Messages: messages,
+		Region:        region,
+		Sender:        sender,
+		CharSet:       "UTF-8",
+		SES:           ses.New(session),
+		Messages:      messages,
+		Templates:     templates,
+		DefaultLocale: options.DefaultLocale,
 	}, nil
 }
 
 // NewPostman creates the client to deliver SES emails
 func NewPostman(config context.Config) (*Postman, error) {
-	return NewVanillaPostman(config.AWS.Region, config.AWS.Sender, config.AWS.AccessKey, config.AWS.AccessSecret)
+	return NewPostmanWithTemplates(config.AWS.Region, config.AWS.Sender, config.AWS.AccessKey, config.AWS.AccessSecret, TemplateOptions{
+		Dir:           config.Postman.TemplatesDir,
+		DefaultLocale: config.Postman.DefaultLocale,
+		Required:      config.Postman.RequiredTemplates,
+	})
 }
 
 // Deliver sends the email to the designated recipient
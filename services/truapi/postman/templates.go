@@ -0,0 +1,182 @@
+package postman
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	packr "github.com/gobuffalo/packr/v2"
+	"github.com/russross/blackfriday/v2"
+)
+
+const (
+	templateExtension    = ".html.tmpl"
+	defaultLocale        = "en"
+	subjectTemplateBlock = "subject"
+)
+
+// defaultTemplates are the templates embedded in the binary, always available in the default locale
+var defaultTemplates = []string{
+	"register", "invitation", "password-reset", "email-confirmation",
+}
+
+// ErrTemplateNotFound is returned when no template matches the name in any of the fallback locales
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// TemplateOptions configures where templates are loaded from
+type TemplateOptions struct {
+	// Dir holds templates overriding the embedded ones: <name>.html.tmpl for the default locale
+	// and <locale>/<name>.html.tmpl for every other locale
+	Dir string
+	// DefaultLocale is the locale of the embedded templates and the last fallback
+	DefaultLocale string
+	// Required are template names that must exist in the default locale on top of the embedded ones
+	Required []string
+}
+
+// TemplateData is the data every templated email is rendered with
+type TemplateData struct {
+	// Subject is used when the template doesn't define a "subject" block
+	Subject string
+	Name    string
+	Link    string
+	Vars    map[string]string
+}
+
+// templateKey identifies a template in a locale
+func templateKey(locale, name string) string {
+	return strings.ToLower(locale) + "/" + name
+}
+
+// localeFallbacks returns the locales to look a template up in, from the most to the least specific
+func localeFallbacks(locale, defaultLocale string) []string {
+	locales := make([]string, 0, 3)
+	if locale != "" {
+		locales = append(locales, locale)
+		if i := strings.IndexAny(locale, "-_"); i > 0 {
+			locales = append(locales, locale[:i])
+		}
+	}
+	return append(locales, defaultLocale)
+}
+
+// loadTemplates parses the embedded templates and then the ones in the configured directory,
+// failing if any required template is missing
+func loadTemplates(options TemplateOptions) (map[string]*template.Template, error) {
+	if options.DefaultLocale == "" {
+		options.DefaultLocale = defaultLocale
+	}
+	templates := make(map[string]*template.Template)
+
+	box := packr.New("Email Templates", "./templates")
+	for _, name := range defaultTemplates {
+		content, err := box.FindString(name + templateExtension)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := template.New(name + templateExtension).Parse(content)
+		if err != nil {
+			return nil, err
+		}
+		templates[templateKey(options.DefaultLocale, name)] = parsed
+	}
+
+	if options.Dir != "" {
+		err := filepath.Walk(options.Dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !strings.HasSuffix(info.Name(), templateExtension) {
+				return nil
+			}
+			rel, err := filepath.Rel(options.Dir, path)
+			if err != nil {
+				return err
+			}
+			locale := options.DefaultLocale
+			parts := strings.Split(filepath.ToSlash(rel), "/")
+			switch len(parts) {
+			case 1:
+			case 2:
+				locale = parts[0]
+			default:
+				return fmt.Errorf("unexpected email template location %s", path)
+			}
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSuffix(info.Name(), templateExtension)
+			parsed, err := template.New(info.Name()).Parse(string(content))
+			if err != nil {
+				return err
+			}
+			templates[templateKey(locale, name)] = parsed
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range options.Required {
+		if _, ok := templates[templateKey(options.DefaultLocale, name)]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+	}
+
+	return templates, nil
+}
+
+// lookupTemplate finds the template for the locale, falling back to its language and then to the default locale
+func (postman *Postman) lookupTemplate(name, locale string) (*template.Template, error) {
+	for _, l := range localeFallbacks(locale, postman.DefaultLocale) {
+		if t, ok := postman.Templates[templateKey(l, name)]; ok {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+}
+
+// Render renders the named template in the locale, returning the subject and the HTML body
+func (postman *Postman) Render(name, locale string, data TemplateData) (string, string, error) {
+	t, err := postman.lookupTemplate(name, locale)
+	if err != nil {
+		return "", "", err
+	}
+
+	subject := data.Subject
+	if t.Lookup(subjectTemplateBlock) != nil {
+		var s bytes.Buffer
+		if err := t.ExecuteTemplate(&s, subjectTemplateBlock, data); err != nil {
+			return "", "", err
+		}
+		subject = strings.TrimSpace(s.String())
+	}
+
+	var body bytes.Buffer
+	if err := t.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+
+	return subject, string(blackfriday.Run(body.Bytes())), nil
+}
+
+// SendTemplated renders the named template in the locale and delivers it to the recipient
+func (postman *Postman) SendTemplated(to, name, locale string, data TemplateData) error {
+	subject, body, err := postman.Render(name, locale, data)
+	if err != nil {
+		return err
+	}
+
+	return postman.Deliver(Message{
+		To:      []string{to},
+		Subject: subject,
+		Body:    body,
+	})
+}
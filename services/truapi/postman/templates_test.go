@@ -0,0 +1,52 @@
+package postman
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleFallbacks(t *testing.T) {
+	assert.Equal(t, []string{"pt-BR", "pt", "en"}, localeFallbacks("pt-BR", "en"))
+	assert.Equal(t, []string{"es", "en"}, localeFallbacks("es", "en"))
+	assert.Equal(t, []string{"en"}, localeFallbacks("", "en"))
+}
+
+func TestTemplates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "postman")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "es"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "welcome.html.tmpl"),
+		[]byte(`{{ define "subject" }}Welcome {{ .Name }}{{ end }}Hi {{ .Name }}, [start]({{ .Link }})`), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "es", "welcome.html.tmpl"),
+		[]byte(`{{ define "subject" }}Bienvenido {{ .Name }}{{ end }}Hola {{ .Name }}`), 0644))
+
+	templates, err := loadTemplates(TemplateOptions{Dir: dir, Required: []string{"welcome"}})
+	assert.NoError(t, err)
+	postman := &Postman{Templates: templates, DefaultLocale: defaultLocale}
+
+	subject, body, err := postman.Render("welcome", "es-MX", TemplateData{Name: "Shane"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bienvenido Shane", subject)
+	assert.Contains(t, body, "Hola Shane")
+
+	subject, body, err = postman.Render("welcome", "fr", TemplateData{Name: "Shane", Link: "https://trustory.io"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Welcome Shane", subject)
+	assert.Contains(t, body, `href="https://trustory.io"`)
+
+	// embedded templates are always available
+	_, err = postman.lookupTemplate("password-reset", "es")
+	assert.NoError(t, err)
+
+	_, _, err = postman.Render("missing", "en", TemplateData{})
+	assert.Error(t, err)
+
+	// fails fast when a required template is missing
+	_, err = loadTemplates(TemplateOptions{Dir: dir, Required: []string{"goodbye"}})
+	assert.Error(t, err)
+}
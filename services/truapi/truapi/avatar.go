@@ -0,0 +1,18 @@
+package truapi
+
+import (
+	"strings"
+)
+
+// defaultAvatarSize is the size Twitter avatars are requested in, instead of their 73x73 `_bigger` variant
+const defaultAvatarSize = "200x200"
+
+// upgradeAvatarURL requests Twitter avatars in the configured size, and over https
+func (ta *TruAPI) upgradeAvatarURL(avatarURL string) string {
+	size := ta.APIContext.Config.App.AvatarSize
+	if size == "" {
+		size = defaultAvatarSize
+	}
+	avatarURL = strings.Replace(avatarURL, "_bigger", "_"+size, 1)
+	return strings.Replace(avatarURL, "http://", "https://", 1)
+}
@@ -0,0 +1,27 @@
+package truapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+)
+
+func TestUpgradeAvatarURL(t *testing.T) {
+	ta := &TruAPI{}
+	assert.Equal(t,
+		"https://pbs.twimg.com/profile_images/1/abc_200x200.jpg",
+		ta.upgradeAvatarURL("http://pbs.twimg.com/profile_images/1/abc_bigger.jpg"))
+	assert.Equal(t,
+		"https://pbs.twimg.com/profile_images/1/abc_200x200.jpg",
+		ta.upgradeAvatarURL("https://pbs.twimg.com/profile_images/1/abc_bigger.jpg"))
+	// not a Twitter avatar
+	assert.Equal(t, "https://trustory.s3.amazonaws.com/avatar.png", ta.upgradeAvatarURL("https://trustory.s3.amazonaws.com/avatar.png"))
+	assert.Equal(t, "", ta.upgradeAvatarURL(""))
+
+	ta.APIContext = truCtx.TruAPIContext{Config: truCtx.Config{App: truCtx.AppConfig{AvatarSize: "400x400"}}}
+	assert.Equal(t,
+		"https://pbs.twimg.com/profile_images/1/abc_400x400.jpg",
+		ta.upgradeAvatarURL("http://pbs.twimg.com/profile_images/1/abc_bigger.jpg"))
+}
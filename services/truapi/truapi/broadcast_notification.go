@@ -6,23 +6,24 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
 )
 
 func (ta *TruAPI) sendBroadcastNotification(n BroadcastNotificationRequest) {
 	if !ta.notificationsInitialized || ta.broadcastNotificationsCh == nil {
 		return
 	}
-	ta.broadcastNotificationsCh <- n
+	// never block the caller on notification delivery, overflow goes to the outbox
+	select {
+	case ta.broadcastNotificationsCh <- n:
+	default:
+		ta.broadcastOutbox.push(n)
+	}
 }
 
 func (ta *TruAPI) runBroadcastNotificationSender(notifications <-chan BroadcastNotificationRequest, pushEndpoint string) {
 	pushURL := fmt.Sprintf("%s/%s", strings.TrimRight(strings.TrimSpace(pushEndpoint), "/"), "sendBroadcastNotification")
 
 	for n := range notifications {
-		httpClient := &http.Client{
-			Timeout: time.Second * 10,
-		}
 		b, err := json.Marshal(&n)
 		if err != nil {
 			fmt.Println("error encoding broadcast notification request", err)
@@ -34,7 +35,7 @@ func (ta *TruAPI) runBroadcastNotificationSender(notifications <-chan BroadcastN
 		}
 		request.Header.Add("Accept", "application/json")
 		request.Header.Add("Content-Type", "application/json")
-		resp, err := httpClient.Do(request)
+		resp, err := ta.httpClient.Do(request)
 		if err != nil {
 			fmt.Println("error sending broadcast notification request", err)
 			continue
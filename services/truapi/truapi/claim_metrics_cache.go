@@ -0,0 +1,110 @@
+package truapi
+
+import (
+	"context"
+	"sync"
+
+	"github.com/TruStory/truchain/x/claim"
+	"github.com/TruStory/truchain/x/staking"
+)
+
+// metricsQueryWorkers is the number of concurrent chain queries made while prefetching metrics data
+const metricsQueryWorkers = 8
+
+// claimMetricsCache memoizes claim arguments and stakes for a single metrics request,
+// so each claim is queried at most once no matter how many times it is reported on.
+type claimMetricsCache struct {
+	ta        *TruAPI
+	mu        sync.Mutex
+	arguments map[uint64][]staking.Argument
+	stakes    map[uint64][]staking.Stake
+}
+
+func (ta *TruAPI) newClaimMetricsCache() *claimMetricsCache {
+	return &claimMetricsCache{
+		ta:        ta,
+		arguments: make(map[uint64][]staking.Argument),
+		stakes:    make(map[uint64][]staking.Stake),
+	}
+}
+
+// claimArguments returns the arguments of a claim
+func (c *claimMetricsCache) claimArguments(ctx context.Context, claimID uint64) ([]staking.Argument, error) {
+	c.mu.Lock()
+	arguments, ok := c.arguments[claimID]
+	c.mu.Unlock()
+	if ok {
+		return arguments, nil
+	}
+	arguments, err := c.ta.getClaimArguments(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.arguments[claimID] = arguments
+	c.mu.Unlock()
+	return arguments, nil
+}
+
+// claimStakes returns the stakes of a claim, querying them from its memoized arguments
+func (c *claimMetricsCache) claimStakes(ctx context.Context, claimID uint64) ([]staking.Stake, error) {
+	c.mu.Lock()
+	stakes, ok := c.stakes[claimID]
+	c.mu.Unlock()
+	if ok {
+		return stakes, nil
+	}
+	arguments, err := c.claimArguments(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+	stakes = make([]staking.Stake, 0)
+	for _, argument := range arguments {
+		stakes = append(stakes, c.ta.claimArgumentStakesResolver(ctx, argument)...)
+	}
+	c.mu.Lock()
+	c.stakes[claimID] = stakes
+	c.mu.Unlock()
+	return stakes, nil
+}
+
+// prefetch loads the arguments and stakes of the claims using a bounded pool of workers
+func (c *claimMetricsCache) prefetch(ctx context.Context, claims []claim.Claim) error {
+	claimsCh := make(chan claim.Claim)
+	errCh := make(chan error, metricsQueryWorkers)
+	var wg sync.WaitGroup
+	for i := 0; i < metricsQueryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q := range claimsCh {
+				_, err := c.claimStakes(ctx, q.ID)
+				if err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+
+	var err error
+	for _, q := range claims {
+		select {
+		case claimsCh <- q:
+			continue
+		case err = <-errCh:
+		}
+		break
+	}
+	close(claimsCh)
+	wg.Wait()
+	if err != nil {
+		return err
+	}
+	select {
+	case err = <-errCh:
+		return err
+	default:
+		return nil
+	}
+}
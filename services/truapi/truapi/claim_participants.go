@@ -0,0 +1,130 @@
+package truapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TruStory/truchain/x/claim"
+	"github.com/TruStory/truchain/x/staking"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+// ParticipantRole is a way of taking part in a claim
+type ParticipantRole string
+
+// the roles a participant can have in a claim, listed in this order
+const (
+	ParticipantRoleCreator        ParticipantRole = "creator"
+	ParticipantRoleArgumentAuthor ParticipantRole = "argument_author"
+	ParticipantRoleBacker         ParticipantRole = "backer"
+	ParticipantRoleChallenger     ParticipantRole = "challenger"
+	ParticipantRoleAgreer         ParticipantRole = "agreer"
+	ParticipantRoleCommenter      ParticipantRole = "commenter"
+)
+
+var participantRolesOrder = []ParticipantRole{
+	ParticipantRoleCreator,
+	ParticipantRoleArgumentAuthor,
+	ParticipantRoleBacker,
+	ParticipantRoleChallenger,
+	ParticipantRoleAgreer,
+	ParticipantRoleCommenter,
+}
+
+// ClaimParticipant is a participant of a claim along with all the ways they took part in it
+type ClaimParticipant struct {
+	AppAccount AppAccount
+	Roles      []ParticipantRole
+}
+
+// participantRoles are the roles of a participant, by address
+type participantRoles struct {
+	address string
+	roles   map[ParticipantRole]bool
+}
+
+// claimParticipantRoles lists the participants of a claim once each, in the order they first show up in,
+// with all of their roles. Backers and challengers staked on a side of the claim, by writing an argument
+// or by agreeing with one.
+func claimParticipantRoles(c claim.Claim, arguments []staking.Argument, stakes []staking.Stake, comments []db.Comment) []participantRoles {
+	participants := make([]participantRoles, 0)
+	indexes := make(map[string]int)
+	add := func(address string, role ParticipantRole) {
+		i, ok := indexes[address]
+		if !ok {
+			i = len(participants)
+			indexes[address] = i
+			participants = append(participants, participantRoles{address: address, roles: make(map[ParticipantRole]bool)})
+		}
+		participants[i].roles[role] = true
+	}
+
+	add(c.Creator.String(), ParticipantRoleCreator)
+	sides := make(map[uint64]staking.StakeType, len(arguments))
+	for _, argument := range arguments {
+		sides[argument.ID] = argument.StakeType
+		add(argument.Creator.String(), ParticipantRoleArgumentAuthor)
+	}
+	for _, stake := range stakes {
+		if stake.Type == staking.StakeUpvote {
+			add(stake.Creator.String(), ParticipantRoleAgreer)
+		}
+		switch sides[stake.ArgumentID] {
+		case staking.StakeBacking:
+			add(stake.Creator.String(), ParticipantRoleBacker)
+		case staking.StakeChallenge:
+			add(stake.Creator.String(), ParticipantRoleChallenger)
+		}
+	}
+	for _, comment := range comments {
+		add(comment.Creator, ParticipantRoleCommenter)
+	}
+	return participants
+}
+
+// sortedRoles returns the roles of a participant in the order they're listed in
+func (p participantRoles) sortedRoles() []ParticipantRole {
+	roles := make([]ParticipantRole, 0, len(p.roles))
+	for _, role := range participantRolesOrder {
+		if p.roles[role] {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// claimParticipantsWithRolesResolver returns the participants of a claim, each once with all of their roles
+func (ta *TruAPI) claimParticipantsWithRolesResolver(ctx context.Context, q claim.Claim) []ClaimParticipant {
+	loaders, ok := getDataLoaders(ctx)
+	if !ok {
+		fmt.Println("loaders not present")
+		return nil
+	}
+	arguments := ta.claimArgumentsResolver(ctx, queryClaimArgumentParams{ClaimID: q.ID})
+	stakes := make([]staking.Stake, 0)
+	for _, argument := range arguments {
+		stakes = append(stakes, ta.claimArgumentStakesResolver(ctx, argument)...)
+	}
+	comments, err := ta.DBClient.CommentsByClaimID(q.ID)
+	if err != nil {
+		fmt.Println("claimParticipantsWithRolesResolver err: ", err)
+		return []ClaimParticipant{}
+	}
+
+	participantsRoles := claimParticipantRoles(q, arguments, stakes, comments)
+	addresses := make([]string, 0, len(participantsRoles))
+	for _, p := range participantsRoles {
+		addresses = append(addresses, p.address)
+	}
+	accounts, errs := loaders.appAccountLoader.LoadAll(addresses)
+	participants := make([]ClaimParticipant, 0, len(participantsRoles))
+	for i, p := range participantsRoles {
+		if errs[i] != nil || accounts[i] == nil {
+			fmt.Println("claimParticipantsWithRolesResolver err: ", errs[i])
+			continue
+		}
+		participants = append(participants, ClaimParticipant{AppAccount: *accounts[i], Roles: p.sortedRoles()})
+	}
+	return participants
+}
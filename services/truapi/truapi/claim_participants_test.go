@@ -0,0 +1,57 @@
+package truapi
+
+import (
+	"testing"
+
+	"github.com/TruStory/truchain/x/claim"
+	"github.com/TruStory/truchain/x/staking"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+func TestClaimParticipantRoles(t *testing.T) {
+	address := func(name string) sdk.AccAddress { return sdk.AccAddress([]byte(name)) }
+	c := claim.Claim{ID: 1, Creator: address("alice")}
+	arguments := []staking.Argument{
+		{ID: 10, ClaimID: 1, Creator: address("bob"), StakeType: staking.StakeBacking},
+		{ID: 11, ClaimID: 1, Creator: address("carol"), StakeType: staking.StakeChallenge},
+	}
+	stakes := []staking.Stake{
+		{ArgumentID: 10, Type: staking.StakeBacking, Creator: address("bob")},
+		{ArgumentID: 11, Type: staking.StakeChallenge, Creator: address("carol")},
+		// bob argued for the claim, and agreed with an argument against it
+		{ArgumentID: 11, Type: staking.StakeUpvote, Creator: address("bob")},
+		{ArgumentID: 10, Type: staking.StakeUpvote, Creator: address("dave")},
+	}
+	comments := []db.Comment{
+		{ClaimID: 1, Creator: address("alice").String()},
+		{ClaimID: 1, Creator: address("erin").String()},
+		{ClaimID: 1, Creator: address("erin").String()},
+	}
+
+	participants := claimParticipantRoles(c, arguments, stakes, comments)
+	roles := make(map[string][]ParticipantRole)
+	order := make([]string, 0)
+	for _, p := range participants {
+		roles[p.address] = p.sortedRoles()
+		order = append(order, p.address)
+	}
+
+	// everyone appears once
+	assert.Equal(t, []string{
+		address("alice").String(),
+		address("bob").String(),
+		address("carol").String(),
+		address("dave").String(),
+		address("erin").String(),
+	}, order)
+	assert.Equal(t, []ParticipantRole{ParticipantRoleCreator, ParticipantRoleCommenter}, roles[address("alice").String()])
+	assert.Equal(t, []ParticipantRole{
+		ParticipantRoleArgumentAuthor, ParticipantRoleBacker, ParticipantRoleChallenger, ParticipantRoleAgreer,
+	}, roles[address("bob").String()])
+	assert.Equal(t, []ParticipantRole{ParticipantRoleArgumentAuthor, ParticipantRoleChallenger}, roles[address("carol").String()])
+	assert.Equal(t, []ParticipantRole{ParticipantRoleBacker, ParticipantRoleAgreer}, roles[address("dave").String()])
+	assert.Equal(t, []ParticipantRole{ParticipantRoleCommenter}, roles[address("erin").String()])
+}
@@ -0,0 +1,143 @@
+package truapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/TruStory/truchain/x/claim"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+const (
+	defaultClaimSearchLimit         = 20
+	maxClaimSearchLimit             = 100
+	defaultClaimSearchIndexInterval = 10 // minutes
+)
+
+type querySearchClaimsParams struct {
+	Query  string `graphql:"query"`
+	Limit  int64  `graphql:"limit,optional"`
+	Offset int64  `graphql:"offset,optional"`
+}
+
+// searchClaimsResolver searches the claim bodies, returning the claims best matching the query first.
+// Claims are paginated with limit and offset, on the ranked search results.
+func (ta *TruAPI) searchClaimsResolver(ctx context.Context, q querySearchClaimsParams) []claim.Claim {
+	if strings.TrimSpace(q.Query) == "" {
+		return []claim.Claim{}
+	}
+	limit := int(q.Limit)
+	if limit <= 0 {
+		limit = defaultClaimSearchLimit
+	}
+	if limit > maxClaimSearchLimit {
+		limit = maxClaimSearchLimit
+	}
+	offset := int(q.Offset)
+	if offset < 0 {
+		offset = 0
+	}
+
+	results, err := ta.DBClient.SearchClaims(q.Query, limit, offset)
+	if err != nil {
+		fmt.Println("searchClaimsResolver err: ", err)
+		return []claim.Claim{}
+	}
+	if len(results) == 0 {
+		return []claim.Claim{}
+	}
+	ids := make([]uint64, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, uint64(result.ClaimID))
+	}
+
+	queryRoute := path.Join(claim.QuerierRoute, claim.QueryClaimsByIDs)
+	res, err := ta.QueryWithContext(ctx, queryRoute, claim.QueryClaimsParams{IDs: ids}, claim.ModuleCodec)
+	if err != nil {
+		fmt.Println("searchClaimsResolver err: ", err)
+		return []claim.Claim{}
+	}
+	claims := make([]claim.Claim, 0)
+	err = claim.ModuleCodec.UnmarshalJSON(res, &claims)
+	if err != nil {
+		fmt.Println("searchClaimsResolver err: ", err)
+		return []claim.Claim{}
+	}
+
+	unflaggedClaims, err := ta.filterFlaggedClaims(claims)
+	if err != nil {
+		fmt.Println("searchClaimsResolver err: ", err)
+		return []claim.Claim{}
+	}
+	return claimsInOrder(unflaggedClaims, ids)
+}
+
+// claimsInOrder orders the claims as the given ids, dropping the ids without a claim
+func claimsInOrder(claims []claim.Claim, ids []uint64) []claim.Claim {
+	byID := make(map[uint64]claim.Claim, len(claims))
+	for _, c := range claims {
+		byID[c.ID] = c
+	}
+	ordered := make([]claim.Claim, 0, len(ids))
+	for _, id := range ids {
+		if c, ok := byID[id]; ok {
+			ordered = append(ordered, c)
+		}
+	}
+	return ordered
+}
+
+// claimSearchIndexer mirrors the bodies of all the claims into the search index, on start and then
+// periodically so that edited claims are picked up. New claims are also indexed as they're created.
+func (ta *TruAPI) claimSearchIndexer() {
+	interval := defaultClaimSearchIndexInterval
+	if ta.APIContext.Config.ClaimSearch.IndexInterval > 0 {
+		interval = ta.APIContext.Config.ClaimSearch.IndexInterval
+	}
+	log.Printf("claim search: indexing claims every %d minutes\n", interval)
+	ta.indexAllClaims()
+	ticker := time.NewTicker(time.Duration(interval) * time.Minute)
+	for range ticker.C {
+		ta.indexAllClaims()
+	}
+}
+
+func (ta *TruAPI) indexAllClaims() {
+	res, err := ta.Query(path.Join(claim.QuerierRoute, claim.QueryClaims), struct{}{}, claim.ModuleCodec)
+	if err != nil {
+		log.Println("an error occurred querying claims to index", err)
+		return
+	}
+	claims := make([]claim.Claim, 0)
+	err = claim.ModuleCodec.UnmarshalJSON(res, &claims)
+	if err != nil {
+		log.Println("an error occurred decoding claims to index", err)
+		return
+	}
+	ta.indexClaims(claims)
+}
+
+func (ta *TruAPI) indexClaims(claims []claim.Claim) {
+	err := ta.DBClient.UpsertClaimSearchDocuments(claimSearchDocuments(claims))
+	if err != nil {
+		log.Println("an error occurred indexing claims", err)
+	}
+}
+
+// claimSearchDocuments maps claims to the documents mirroring them in the search index
+func claimSearchDocuments(claims []claim.Claim) []db.ClaimSearchDocument {
+	documents := make([]db.ClaimSearchDocument, 0, len(claims))
+	for _, c := range claims {
+		documents = append(documents, db.ClaimSearchDocument{
+			ClaimID:     int64(c.ID),
+			CommunityID: c.CommunityID,
+			Body:        c.Body,
+		})
+	}
+	return documents
+}
@@ -0,0 +1,31 @@
+package truapi
+
+import (
+	"testing"
+
+	"github.com/TruStory/truchain/x/claim"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+func TestClaimsInOrder(t *testing.T) {
+	claims := []claim.Claim{{ID: 1}, {ID: 2}, {ID: 3}}
+	ids := func(claims []claim.Claim) []uint64 {
+		ids := make([]uint64, 0, len(claims))
+		for _, c := range claims {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	// the ranked order of the search results is kept, and flagged or missing claims are dropped
+	assert.Equal(t, []uint64{3, 1, 2}, ids(claimsInOrder(claims, []uint64{3, 1, 2})))
+	assert.Equal(t, []uint64{2, 1}, ids(claimsInOrder(claims, []uint64{4, 2, 1})))
+	assert.Empty(t, claimsInOrder(nil, []uint64{1}))
+}
+
+func TestClaimSearchDocuments(t *testing.T) {
+	claims := []claim.Claim{{ID: 7, CommunityID: "crypto", Body: "Bitcoin is money"}}
+	assert.Equal(t, []db.ClaimSearchDocument{{ClaimID: 7, CommunityID: "crypto", Body: "Bitcoin is money"}}, claimSearchDocuments(claims))
+}
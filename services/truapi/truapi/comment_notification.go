@@ -7,14 +7,18 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
 )
 
 func (ta *TruAPI) sendCommentNotification(n CommentNotificationRequest) {
 	if !ta.notificationsInitialized || ta.commentsNotificationsCh == nil {
 		return
 	}
-	ta.commentsNotificationsCh <- n
+	// never block the caller on notification delivery, overflow goes to the outbox
+	select {
+	case ta.commentsNotificationsCh <- n:
+	default:
+		ta.commentsOutbox.push(n)
+	}
 }
 
 func (ta *TruAPI) runCommentNotificationSender(notifications <-chan CommentNotificationRequest, endpoint string) {
@@ -40,16 +44,13 @@ func (ta *TruAPI) runCommentNotificationSender(notifications <-chan CommentNotif
 			fmt.Println("error encoding comment notification request", err)
 			continue
 		}
-		httpClient := &http.Client{
-			Timeout: time.Second * 10,
-		}
 		request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(b))
 		if err != nil {
 			fmt.Println("error creating http request", err)
 		}
 		request.Header.Add("Accept", "application/json")
 		request.Header.Add("Content-Type", "application/json")
-		resp, err := httpClient.Do(request)
+		resp, err := ta.httpClient.Do(request)
 		if err != nil {
 			fmt.Println("error sending comment notification request", err)
 			continue
@@ -0,0 +1,153 @@
+package truapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+const (
+	defaultCommentTreeMaxDepth = 10
+	defaultCommentTreeMaxSize  = 500
+	defaultCommentTreeLimit    = 20
+)
+
+// DeletedCommentBody replaces the body of deleted comments kept in the tree for their replies
+const DeletedCommentBody = "[deleted]"
+
+type queryCommentTreeParams struct {
+	ClaimID int64 `graphql:"claimId"`
+	Limit   int64 `graphql:"limit,optional"`
+	Offset  int64 `graphql:"offset,optional"`
+}
+
+// CommentTreeNode is a comment together with its nested replies
+type CommentTreeNode struct {
+	Comment   db.Comment
+	Deleted   bool
+	Reactions []db.ReactionsCount
+	Replies   []*CommentTreeNode
+	// Truncated is set when replies were left out because of the depth or size caps
+	Truncated bool
+}
+
+// CommentTree is a page of top level comments of a claim with their nested replies
+type CommentTree struct {
+	Comments      []*CommentTreeNode
+	TotalTopLevel int
+	Truncated     bool
+}
+
+// buildCommentTree nests comments under their parents and returns a page of top level comments.
+// Replies deeper than maxDepth and comments past maxSize are left out and their parents marked as truncated.
+func buildCommentTree(comments []db.Comment, offset, limit, maxDepth, maxSize int) CommentTree {
+	children := make(map[int64][]db.Comment)
+	ids := make(map[int64]bool)
+	for _, comment := range comments {
+		ids[comment.ID] = true
+	}
+	roots := make([]db.Comment, 0)
+	for _, comment := range comments {
+		// replies to unknown parents are shown at the top level rather than lost
+		if comment.ParentID == 0 || !ids[comment.ParentID] {
+			roots = append(roots, comment)
+			continue
+		}
+		children[comment.ParentID] = append(children[comment.ParentID], comment)
+	}
+
+	tree := CommentTree{Comments: make([]*CommentTreeNode, 0), TotalTopLevel: len(roots)}
+	start, end := pageBounds(len(roots), offset, limit)
+
+	size := 0
+	var build func(comment db.Comment, depth int) *CommentTreeNode
+	build = func(comment db.Comment, depth int) *CommentTreeNode {
+		size++
+		node := &CommentTreeNode{
+			Comment:   comment,
+			Reactions: make([]db.ReactionsCount, 0),
+			Replies:   make([]*CommentTreeNode, 0),
+		}
+		if comment.DeletedAt != nil && !comment.DeletedAt.IsZero() {
+			node.Deleted = true
+			node.Comment.Body = DeletedCommentBody
+			node.Comment.Creator = ""
+		}
+		for _, reply := range children[comment.ID] {
+			if depth+1 >= maxDepth || size >= maxSize {
+				node.Truncated = true
+				tree.Truncated = true
+				break
+			}
+			node.Replies = append(node.Replies, build(reply, depth+1))
+		}
+		return node
+	}
+	for _, root := range roots[start:end] {
+		if size >= maxSize {
+			tree.Truncated = true
+			break
+		}
+		tree.Comments = append(tree.Comments, build(root, 0))
+	}
+	return tree
+}
+
+// commentTreeNodes flattens the tree into its nodes
+func commentTreeNodes(nodes []*CommentTreeNode) []*CommentTreeNode {
+	flattened := make([]*CommentTreeNode, 0)
+	for _, node := range nodes {
+		flattened = append(flattened, node)
+		flattened = append(flattened, commentTreeNodes(node.Replies)...)
+	}
+	return flattened
+}
+
+func (ta *TruAPI) commentTreeResolver(ctx context.Context, q queryCommentTreeParams) CommentTree {
+	maxDepth := ta.APIContext.Config.Params.CommentTreeMaxDepth
+	if maxDepth == 0 {
+		maxDepth = defaultCommentTreeMaxDepth
+	}
+	maxSize := ta.APIContext.Config.Params.CommentTreeMaxSize
+	if maxSize == 0 {
+		maxSize = defaultCommentTreeMaxSize
+	}
+	limit := int(q.Limit)
+	if limit == 0 {
+		limit = defaultCommentTreeLimit
+	}
+
+	// comment bodies come back with mentions already translated to profile links
+	comments, err := ta.DBClient.ClaimLevelComments(uint64(q.ClaimID))
+	if err != nil {
+		fmt.Println("commentTreeResolver err: ", err)
+		return CommentTree{Comments: make([]*CommentTreeNode, 0)}
+	}
+	tree := buildCommentTree(comments, int(q.Offset), limit, maxDepth, maxSize)
+
+	nodes := commentTreeNodes(tree.Comments)
+	ids := make([]int64, 0, len(nodes))
+	creators := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.Comment.ID)
+		if !node.Deleted {
+			creators = append(creators, node.Comment.Creator)
+		}
+	}
+	reactions, err := ta.DBClient.ReactionsCountByReactionables(db.CommentReactionable, ids)
+	if err != nil {
+		fmt.Println("commentTreeResolver err: ", err)
+	}
+	for _, node := range nodes {
+		if counts, ok := reactions[node.Comment.ID]; ok {
+			node.Reactions = counts
+		}
+	}
+
+	// load the creators in a single batch
+	if loaders, ok := getDataLoaders(ctx); ok {
+		loaders.appAccountLoader.LoadAll(creators)
+	}
+	return tree
+}
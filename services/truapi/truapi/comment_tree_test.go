@@ -0,0 +1,54 @@
+package truapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCommentTree(t *testing.T) {
+	deletedAt := time.Now()
+	deleted := db.Comment{ID: 2, ParentID: 1, Body: "gone", Creator: "cosmos2"}
+	deleted.DeletedAt = &deletedAt
+	comments := []db.Comment{
+		{ID: 1, Body: "first", Creator: "cosmos1"},
+		deleted,
+		{ID: 3, ParentID: 2, Body: "reply to deleted", Creator: "cosmos3"},
+		{ID: 4, Body: "second", Creator: "cosmos1"},
+		{ID: 5, ParentID: 99, Body: "orphan", Creator: "cosmos1"},
+	}
+
+	tree := buildCommentTree(comments, 0, 10, 10, 100)
+	assert.Equal(t, 3, tree.TotalTopLevel)
+	assert.False(t, tree.Truncated)
+	assert.Len(t, tree.Comments, 3)
+	tombstone := tree.Comments[0].Replies[0]
+	assert.True(t, tombstone.Deleted)
+	assert.Equal(t, DeletedCommentBody, tombstone.Comment.Body)
+	assert.Empty(t, tombstone.Comment.Creator)
+	assert.Equal(t, int64(3), tombstone.Replies[0].Comment.ID)
+	assert.Equal(t, int64(5), tree.Comments[2].Comment.ID)
+	assert.Len(t, commentTreeNodes(tree.Comments), 5)
+
+	// paginates top level comments
+	page := buildCommentTree(comments, 1, 1, 10, 100)
+	assert.Equal(t, 3, page.TotalTopLevel)
+	assert.Len(t, page.Comments, 1)
+	assert.Equal(t, int64(4), page.Comments[0].Comment.ID)
+	assert.Len(t, buildCommentTree(comments, 5, 10, 10, 100).Comments, 0)
+	assert.Len(t, buildCommentTree(comments, -1, 1, 10, 100).Comments, 1)
+	assert.Len(t, buildCommentTree(comments, 0, -1, 10, 100).Comments, 0)
+
+	// caps the depth
+	shallow := buildCommentTree(comments, 0, 10, 2, 100)
+	assert.True(t, shallow.Truncated)
+	assert.True(t, shallow.Comments[0].Replies[0].Truncated)
+	assert.Len(t, shallow.Comments[0].Replies[0].Replies, 0)
+
+	// caps the size
+	small := buildCommentTree(comments, 0, 10, 10, 2)
+	assert.True(t, small.Truncated)
+	assert.Len(t, commentTreeNodes(small.Comments), 2)
+}
@@ -0,0 +1,158 @@
+package truapi
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/TruStory/octopus/services/truapi/truapi/render"
+)
+
+// csvWriter writes CSV exports with every cell neutralized against formula injection,
+// optionally gzip compressed
+type csvWriter struct {
+	*csv.Writer
+	gzip *gzip.Writer
+	// body is the response the records are written to, nil when not writing a response
+	body *responseBody
+	err  error
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{Writer: csv.NewWriter(w)}
+}
+
+// headerWriter is a response that can be written incrementally
+type headerWriter interface {
+	io.Writer
+	Header() http.Header
+}
+
+// newCSVResponseWriter writes a CSV response, gzip compressed when the client accepts it.
+// The response must be completed with Close.
+func newCSVResponseWriter(w headerWriter, r *http.Request) *csvWriter {
+	w.Header().Add("Content-Type", "text/csv")
+	w.Header().Add("Vary", "Accept-Encoding")
+	body := &responseBody{w: w}
+	if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+		return &csvWriter{Writer: csv.NewWriter(body), body: body}
+	}
+	gz := gzip.NewWriter(&gzipResponse{w: w, body: body})
+	return &csvWriter{Writer: csv.NewWriter(gz), gzip: gz, body: body}
+}
+
+// Write sanitizes and writes a single record
+func (w *csvWriter) Write(record []string) error {
+	sanitized := make([]string, len(record))
+	for i, cell := range record {
+		sanitized[i] = sanitizeCSVCell(cell)
+	}
+	return w.Writer.Write(sanitized)
+}
+
+// Flush writes the buffered records through to the response
+func (w *csvWriter) Flush() {
+	w.Writer.Flush()
+	if w.gzip != nil && w.err == nil {
+		w.err = w.gzip.Flush()
+	}
+}
+
+// Error reports any error from a previous Write or Flush
+func (w *csvWriter) Error() error {
+	if err := w.Writer.Error(); err != nil {
+		return err
+	}
+	return w.err
+}
+
+// Close flushes the remaining records and ends the compressed stream, if any
+func (w *csvWriter) Close() error {
+	w.Flush()
+	if w.gzip != nil {
+		err := w.gzip.Close()
+		if w.err == nil {
+			w.err = err
+		}
+	}
+	return w.Error()
+}
+
+// Abort ends a failed export response. The error is rendered when nothing was sent yet, otherwise
+// the response is aborted so that the client gets a broken download rather than a corrupted file.
+func (w *csvWriter) Abort(rw http.ResponseWriter, r *http.Request, message string) {
+	if w.gzip != nil {
+		// release the compressor without sending anything more
+		w.gzip.Reset(ioutil.Discard)
+		_ = w.gzip.Close()
+	}
+	if w.body != nil && w.body.started {
+		panic(http.ErrAbortHandler)
+	}
+	render.Error(rw, r, message, http.StatusInternalServerError)
+}
+
+// responseBody records whether anything was written to the response
+type responseBody struct {
+	w       io.Writer
+	started bool
+}
+
+func (b *responseBody) Write(p []byte) (int, error) {
+	b.started = true
+	return b.w.Write(p)
+}
+
+// gzipResponse sets the gzip content encoding with the first compressed bytes, so that
+// an error rendered before anything was written isn't sent with it
+type gzipResponse struct {
+	w    headerWriter
+	body *responseBody
+}
+
+func (g *gzipResponse) Write(p []byte) (int, error) {
+	if !g.body.started {
+		g.w.Header().Set("Content-Encoding", "gzip")
+		g.w.Header().Del("Content-Length")
+	}
+	return g.body.Write(p)
+}
+
+// acceptsGzip tells whether an Accept-Encoding header allows a gzip response
+func acceptsGzip(acceptEncoding string) bool {
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(coding, ";")
+		if !strings.EqualFold(strings.TrimSpace(params[0]), "gzip") {
+			continue
+		}
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				return err == nil && q > 0
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// sanitizeCSVCell prefixes a cell that spreadsheets would evaluate as a formula with a single quote,
+// so user content like "=cmd|..." is displayed as text. Plain numbers, e.g. negative amounts, are kept as is.
+func sanitizeCSVCell(cell string) string {
+	if cell == "" {
+		return cell
+	}
+	switch cell[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		if _, err := strconv.ParseFloat(cell, 64); err == nil {
+			return cell
+		}
+		return "'" + cell
+	}
+	return cell
+}
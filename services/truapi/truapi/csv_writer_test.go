@@ -0,0 +1,64 @@
+package truapi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVWriterNeutralizesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	csvw := newCSVWriter(&buf)
+	row := UserClaimRow{ClaimID: 1, Claim: `=cmd|' /C calc'!A0`, Community: "crypto", Address: "@alice"}
+	err := csvw.Write([]string{"1", row.Claim, row.Community, row.Address, "+1", "-250", "-1+2"})
+	assert.NoError(t, err)
+	csvw.Flush()
+	assert.NoError(t, csvw.Error())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"1", `'=cmd|' /C calc'!A0`, "crypto", "'@alice", "+1", "-250", "'-1+2"},
+	}, records)
+}
+
+func TestSanitizeCSVCell(t *testing.T) {
+	assert.Equal(t, "", sanitizeCSVCell(""))
+	assert.Equal(t, "a claim", sanitizeCSVCell("a claim"))
+	assert.Equal(t, "'=SUM(A1:A2)", sanitizeCSVCell("=SUM(A1:A2)"))
+	assert.Equal(t, "'\t=1", sanitizeCSVCell("\t=1"))
+	assert.Equal(t, "-1.5", sanitizeCSVCell("-1.5"))
+}
+
+func TestCSVWriterAbortBeforeSending(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	csvw := newCSVResponseWriter(w, r)
+	assert.NoError(t, csvw.Write([]string{"id"}))
+
+	csvw.Abort(w, r, "boom")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Body.String(), "boom")
+}
+
+func TestCSVWriterAbortAfterSending(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	csvw := newCSVResponseWriter(w, r)
+	assert.NoError(t, csvw.Write([]string{"id"}))
+	csvw.Flush()
+	sent := w.Body.Len()
+	assert.NotZero(t, sent)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { csvw.Abort(w, r, "boom") })
+	// nothing is appended to the compressed stream
+	assert.Equal(t, sent, w.Body.Len())
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+}
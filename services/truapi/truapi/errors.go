@@ -6,6 +6,7 @@ import "errors"
 var (
 	ErrFlaggedStoryEnvVarParsing = errors.New("Error parsing flagged story environment variable")
 	Err400MissingParameter       = errors.New("Missing parameter")
+	Err400EmptyComment           = errors.New("Comment can't be empty")
 	Err401NotAuthenticated       = errors.New("User not authenticated")
 	Err403NotAuthorized          = errors.New("User not authorized")
 	Err404ResourceNotFound       = errors.New("Resource not found")
@@ -0,0 +1,54 @@
+package truapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/TruStory/octopus/services/truapi/truapi/cookies"
+)
+
+// FeatureFlag represents a client feature that can be toggled from the server
+type FeatureFlag struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// mergeFeatureFlags applies the overrides on top of the global flags and returns them sorted by name
+func mergeFeatureFlags(global, overrides map[string]bool) []FeatureFlag {
+	merged := make(map[string]bool, len(global)+len(overrides))
+	for name, enabled := range global {
+		merged[name] = enabled
+	}
+	for name, enabled := range overrides {
+		merged[name] = enabled
+	}
+
+	flags := make([]FeatureFlag, 0, len(merged))
+	for name, enabled := range merged {
+		flags = append(flags, FeatureFlag{Name: name, Enabled: enabled})
+	}
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Name < flags[j].Name
+	})
+	return flags
+}
+
+func (ta *TruAPI) featureFlagsResolver(ctx context.Context, _ Settings) []FeatureFlag {
+	config := ta.APIContext.Config.FeatureFlags
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil || len(config.GroupOverrides) == 0 {
+		return mergeFeatureFlags(config.Flags, nil)
+	}
+
+	u, err := ta.DBClient.UserByID(user.ID)
+	if err != nil {
+		fmt.Println("featureFlagsResolver err: ", err)
+		return mergeFeatureFlags(config.Flags, nil)
+	}
+	if u == nil {
+		return mergeFeatureFlags(config.Flags, nil)
+	}
+	return mergeFeatureFlags(config.Flags, config.GroupOverrides[strconv.Itoa(int(u.UserGroup))])
+}
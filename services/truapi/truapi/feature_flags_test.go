@@ -0,0 +1,21 @@
+package truapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeFeatureFlags(t *testing.T) {
+	global := map[string]bool{"leaderboard": true, "gifts": false}
+
+	flags := mergeFeatureFlags(global, nil)
+	assert.Equal(t, []FeatureFlag{{Name: "gifts", Enabled: false}, {Name: "leaderboard", Enabled: true}}, flags)
+
+	flags = mergeFeatureFlags(global, map[string]bool{"gifts": true, "beta_search": true})
+	assert.Equal(t, []FeatureFlag{
+		{Name: "beta_search", Enabled: true},
+		{Name: "gifts", Enabled: true},
+		{Name: "leaderboard", Enabled: true},
+	}, flags)
+}
@@ -0,0 +1,45 @@
+package truapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/TruStory/octopus/services/truapi/truapi/render"
+)
+
+// ActiveUsersMetricsResponse represents the daily, weekly and monthly active users
+type ActiveUsersMetricsResponse struct {
+	DailyActiveUsers   int64 `json:"dau"`
+	WeeklyActiveUsers  int64 `json:"wau"`
+	MonthlyActiveUsers int64 `json:"mau"`
+}
+
+// HandleActiveUsersMetrics returns the DAU/WAU/MAU counts.
+// With `include_activity=true` users who commented or agreed without logging in are counted as well.
+func (ta *TruAPI) HandleActiveUsersMetrics(w http.ResponseWriter, r *http.Request) {
+	count := ta.DBClient.ActiveUserCount
+	if r.FormValue("include_activity") == "true" {
+		count = ta.DBClient.ActiveParticipantCount
+	}
+
+	now := time.Now()
+	metrics := ActiveUsersMetricsResponse{}
+	var err error
+	metrics.DailyActiveUsers, err = count(now.AddDate(0, 0, -1))
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	metrics.WeeklyActiveUsers, err = count(now.AddDate(0, 0, -7))
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	metrics.MonthlyActiveUsers, err = count(now.AddDate(0, 0, -30))
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	render.Response(w, r, metrics, http.StatusOK)
+}
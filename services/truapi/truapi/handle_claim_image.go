@@ -47,6 +47,7 @@ func (ta *TruAPI) updateClaimImage(r *http.Request) chttp.Response {
 	claimImageURL := &db.ClaimImage{
 		ClaimID:       request.ClaimID,
 		ClaimImageURL: request.URL,
+		HasMedia:      request.URL != "",
 	}
 	err = ta.DBClient.AddClaimImage(claimImageURL)
 	if err != nil {
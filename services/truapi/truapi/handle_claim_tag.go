@@ -0,0 +1,88 @@
+package truapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/TruStory/octopus/services/truapi/chttp"
+	"github.com/TruStory/octopus/services/truapi/db"
+	"github.com/TruStory/octopus/services/truapi/truapi/cookies"
+)
+
+// ClaimTagRequest represents the JSON request for tagging or untagging a claim
+type ClaimTagRequest struct {
+	ClaimID int64  `json:"claim_id"`
+	Tag     string `json:"tag"`
+}
+
+// HandleClaimTag handles requests for claim tags
+func (ta *TruAPI) HandleClaimTag(r *http.Request) chttp.Response {
+	switch r.Method {
+	case http.MethodPost:
+		return ta.handleAddClaimTag(r)
+	case http.MethodDelete:
+		return ta.handleRemoveClaimTag(r)
+	default:
+		return chttp.SimpleErrorResponse(404, Err404ResourceNotFound)
+	}
+}
+
+// decodeClaimTagRequest decodes a claim tag request made by a claim admin, or returns the error response
+func (ta *TruAPI) decodeClaimTagRequest(r *http.Request) (*ClaimTagRequest, *cookies.AuthenticatedUser, chttp.Response) {
+	request := &ClaimTagRequest{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		return nil, nil, chttp.SimpleErrorResponse(400, err)
+	}
+	if request.ClaimID == 0 || db.NormalizeClaimTag(request.Tag) == "" {
+		return nil, nil, chttp.SimpleErrorResponse(400, errors.New("claim id and tag are required"))
+	}
+	if utf8.RuneCountInString(db.NormalizeClaimTag(request.Tag)) > db.MaxClaimTagLength {
+		return nil, nil, chttp.SimpleErrorResponse(400, fmt.Errorf("tag must be at most %d characters", db.MaxClaimTagLength))
+	}
+
+	user, ok := r.Context().Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil {
+		return nil, nil, chttp.SimpleErrorResponse(401, Err401NotAuthenticated)
+	}
+
+	if !ta.isClaimAdmin(r.Context(), user.Address) {
+		return nil, nil, chttp.SimpleErrorResponse(403, Err403NotAuthorized)
+	}
+	return request, user, nil
+}
+
+func (ta *TruAPI) handleAddClaimTag(r *http.Request) chttp.Response {
+	request, user, errResponse := ta.decodeClaimTagRequest(r)
+	if errResponse != nil {
+		return errResponse
+	}
+
+	claimTag, err := ta.DBClient.AddClaimTag(request.ClaimID, request.Tag, user.Address)
+	if err != nil {
+		return chttp.SimpleErrorResponse(500, err)
+	}
+	respBytes, err := json.Marshal(claimTag)
+	if err != nil {
+		return chttp.SimpleErrorResponse(500, err)
+	}
+
+	return chttp.SimpleResponse(200, respBytes)
+}
+
+func (ta *TruAPI) handleRemoveClaimTag(r *http.Request) chttp.Response {
+	request, _, errResponse := ta.decodeClaimTagRequest(r)
+	if errResponse != nil {
+		return errResponse
+	}
+
+	err := ta.DBClient.RemoveClaimTag(request.ClaimID, request.Tag)
+	if err != nil {
+		return chttp.SimpleErrorResponse(500, err)
+	}
+
+	return chttp.SimpleResponse(200, nil)
+}
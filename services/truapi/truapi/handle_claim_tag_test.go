@@ -0,0 +1,24 @@
+package truapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeClaimTagRequestRejectsInvalidTags(t *testing.T) {
+	ta := &TruAPI{}
+	decode := func(tag string) int {
+		body := fmt.Sprintf(`{"claim_id": 1, "tag": %q}`, tag)
+		_, _, errResponse := ta.decodeClaimTagRequest(commentRequest(http.MethodPost, "editor", body))
+		return errResponse.HTTPCode()
+	}
+
+	assert.Equal(t, http.StatusBadRequest, decode("  "))
+	// longer than the column allows
+	assert.Equal(t, http.StatusBadRequest, decode(strings.Repeat("a", 65)))
+	assert.Equal(t, http.StatusBadRequest, decode(strings.Repeat("é", 65)))
+}
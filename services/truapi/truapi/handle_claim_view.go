@@ -0,0 +1,48 @@
+package truapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+	"github.com/TruStory/octopus/services/truapi/truapi/render"
+)
+
+// HandleClaimView records that the viewer opened a claim, feeding the views of the claim metrics.
+// Authenticated viewers are recorded by address and anonymous ones by session, every view counting
+// towards the total while the unique views count each viewer once per day.
+func (ta *TruAPI) HandleClaimView(w http.ResponseWriter, r *http.Request) {
+	claimID, err := strconv.ParseInt(mux.Vars(r)["claimID"], 10, 64)
+	if err != nil {
+		render.Error(w, r, "invalid claim id", http.StatusBadRequest)
+		return
+	}
+	claim := ta.claimResolver(r.Context(), queryByClaimID{ID: uint64(claimID)})
+	if claim.ID == 0 {
+		render.Error(w, r, Err404ResourceNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	event := db.TrackEvent{
+		Event: TrackEventClaimOpened,
+		Meta: db.TrackEventMeta{
+			ClaimID:     &claimID,
+			CommunityID: &claim.CommunityID,
+		},
+	}
+	err = ta.identifyViewer(r, &event)
+	if err != nil {
+		render.Error(w, r, "no session to record the view", http.StatusBadRequest)
+		return
+	}
+	err = ta.DBClient.Add(&event)
+	if err != nil {
+		fmt.Println("error adding claim view", err)
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,15 +1,29 @@
 package truapi
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/go-pg/pg"
 
 	"github.com/TruStory/octopus/services/truapi/db"
 	"github.com/TruStory/octopus/services/truapi/truapi/cookies"
 	"github.com/TruStory/octopus/services/truapi/truapi/render"
 )
 
+const (
+	defaultCommentMaxLength = 5000
+
+	// comments created again with the same idempotency key are not posted twice
+	commentIdempotencyKeyHeader    = "Idempotency-Key"
+	commentIdempotencyKeyMaxLength = 255
+)
+
 // AddCommentRequest represents the JSON request for adding a comment
 type AddCommentRequest struct {
 	ParentID   int64  `json:"parent_id,omitempty"`
@@ -19,17 +33,44 @@ type AddCommentRequest struct {
 	Body       string `json:"body"`
 }
 
+// EditCommentRequest represents the JSON request for editing a comment
+type EditCommentRequest struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// DeleteCommentRequest represents the JSON request for deleting a comment
+type DeleteCommentRequest struct {
+	ID int64 `json:"id"`
+}
+
+// adminCheck tells whether an address can moderate content
+type adminCheck func(ctx context.Context, address string) bool
+
+// claimCommunityLookup returns the community of a claim, and false when there's no such claim
+type claimCommunityLookup func(ctx context.Context, claimID int64) (string, bool)
+
 // HandleComment handles requests for comments
 func (ta *TruAPI) HandleComment(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
-		ta.handleCreateComment(w, r)
+		ta.handleCreateComment(w, r, ta.claimCommunity)
+	case http.MethodPut:
+		ta.handleEditComment(w, r, ta.isClaimAdmin)
+	case http.MethodDelete:
+		ta.handleDeleteComment(w, r, ta.isClaimAdmin)
 	default:
 		render.Error(w, r, "Method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
-func (ta *TruAPI) handleCreateComment(w http.ResponseWriter, r *http.Request) {
+// claimCommunity looks the community of a claim up on chain
+func (ta *TruAPI) claimCommunity(ctx context.Context, claimID int64) (string, bool) {
+	claim := ta.claimResolver(ctx, queryByClaimID{ID: uint64(claimID)})
+	return claim.CommunityID, claim.ID != 0
+}
+
+func (ta *TruAPI) handleCreateComment(w http.ResponseWriter, r *http.Request, communityOf claimCommunityLookup) {
 	request := &AddCommentRequest{}
 	err := json.NewDecoder(r.Body).Decode(request)
 	if err != nil {
@@ -42,35 +83,169 @@ func (ta *TruAPI) handleCreateComment(w http.ResponseWriter, r *http.Request) {
 		render.Error(w, r, Err401NotAuthenticated.Error(), http.StatusUnauthorized)
 		return
 	}
-	claim := ta.claimResolver(r.Context(), queryByClaimID{ID: uint64(request.ClaimID)})
-	if claim.ID == 0 {
+	body, err := validateCommentBody(request.Body, ta.commentMaxLength())
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	idempotencyKey := strings.TrimSpace(r.Header.Get(commentIdempotencyKeyHeader))
+	if len(idempotencyKey) > commentIdempotencyKeyMaxLength {
+		render.Error(w, r, fmt.Sprintf("%s can't be longer than %d characters", commentIdempotencyKeyHeader, commentIdempotencyKeyMaxLength), http.StatusBadRequest)
+		return
+	}
+	communityID, ok := communityOf(r.Context(), request.ClaimID)
+	if !ok {
 		render.Error(w, r, "Invalid claim", http.StatusBadRequest)
 		return
 	}
 	comment := &db.Comment{
-		ParentID:    request.ParentID,
-		ClaimID:     request.ClaimID,
-		CommunityID: claim.CommunityID,
-		ArgumentID:  request.ArgumentID,
-		ElementID:   request.ElementID,
-		Body:        request.Body,
-		Creator:     user.Address,
-	}
-	err = ta.DBClient.AddComment(comment)
+		ParentID:       request.ParentID,
+		ClaimID:        request.ClaimID,
+		CommunityID:    communityID,
+		ArgumentID:     request.ArgumentID,
+		ElementID:      request.ElementID,
+		Body:           body,
+		Creator:        user.Address,
+		IdempotencyKey: idempotencyKey,
+	}
+	if idempotencyKey == "" {
+		err = ta.DBClient.AddComment(comment)
+	} else {
+		var created bool
+		created, err = ta.DBClient.AddCommentOnce(comment)
+		if err == nil && !created {
+			// a retry of a comment already created gets that comment back
+			render.JSON(w, r, comment, http.StatusOK)
+			return
+		}
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	mentioned, err := ta.DBClient.MentionedUserIDs(comment.Body)
+	if err != nil {
+		// the comment is saved, only mentioned users miss their notification
+		fmt.Println("could not resolve comment mentions err: ", err)
+	}
 	ta.sendCommentNotification(CommentNotificationRequest{
-		ID:         comment.ID,
-		ClaimID:    comment.ClaimID,
-		ArgumentID: comment.ArgumentID,
-		ElementID:  comment.ElementID,
-		Creator:    comment.Creator,
-		Timestamp:  time.Now(),
+		ID:               comment.ID,
+		ClaimID:          comment.ClaimID,
+		ArgumentID:       comment.ArgumentID,
+		ElementID:        comment.ElementID,
+		Creator:          comment.Creator,
+		Timestamp:        time.Now(),
+		MentionedUserIDs: mentionRecipients(mentioned, user.ID),
 	})
 
 	ta.sendCommentToSlack(*comment)
 
 	render.JSON(w, r, comment, http.StatusOK)
 }
+
+func (ta *TruAPI) handleEditComment(w http.ResponseWriter, r *http.Request, isAdmin adminCheck) {
+	request := &EditCommentRequest{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		render.Error(w, r, "Error parsing request", http.StatusBadRequest)
+		return
+	}
+	body, err := validateCommentBody(request.Body, ta.commentMaxLength())
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !ta.authorizeCommentChange(w, r, request.ID, isAdmin) {
+		return
+	}
+
+	comment, err := ta.DBClient.UpdateCommentBody(request.ID, body)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, comment, http.StatusOK)
+}
+
+func (ta *TruAPI) handleDeleteComment(w http.ResponseWriter, r *http.Request, isAdmin adminCheck) {
+	request := &DeleteCommentRequest{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		render.Error(w, r, "Error parsing request", http.StatusBadRequest)
+		return
+	}
+	if !ta.authorizeCommentChange(w, r, request.ID, isAdmin) {
+		return
+	}
+
+	err = ta.DBClient.DeleteComment(request.ID)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, request, http.StatusOK)
+}
+
+// authorizeCommentChange tells whether the authenticated user can change a comment, being its creator or an admin.
+// Otherwise it renders the error response.
+func (ta *TruAPI) authorizeCommentChange(w http.ResponseWriter, r *http.Request, id int64, isAdmin adminCheck) bool {
+	user, ok := r.Context().Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil {
+		render.Error(w, r, Err401NotAuthenticated.Error(), http.StatusUnauthorized)
+		return false
+	}
+	if id == 0 {
+		render.Error(w, r, Err400MissingParameter.Error(), http.StatusBadRequest)
+		return false
+	}
+	comment, err := ta.DBClient.CommentByID(id)
+	if err == pg.ErrNoRows || (err == nil && comment.DeletedAt != nil) {
+		render.Error(w, r, Err404ResourceNotFound.Error(), http.StatusNotFound)
+		return false
+	}
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if comment.Creator != user.Address && !isAdmin(r.Context(), user.Address) {
+		render.Error(w, r, Err403NotAuthorized.Error(), http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// commentMaxLength is the maximum number of characters in a comment
+func (ta *TruAPI) commentMaxLength() int {
+	maxLength := ta.APIContext.Config.Params.CommentMaxLength
+	if maxLength == 0 {
+		maxLength = defaultCommentMaxLength
+	}
+	return maxLength
+}
+
+// validateCommentBody returns the body without surrounding whitespace,
+// or an error if it's empty or longer than maxLength characters
+func validateCommentBody(body string, maxLength int) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", Err400EmptyComment
+	}
+	if utf8.RuneCountInString(body) > maxLength {
+		return "", fmt.Errorf("Comment can't be longer than %d characters", maxLength)
+	}
+	return body, nil
+}
+
+// mentionRecipients returns the mentioned users to notify once each, leaving out the author mentioning themselves
+func mentionRecipients(mentioned []int64, authorID int64) []int64 {
+	recipients := make([]int64, 0, len(mentioned))
+	notified := map[int64]bool{authorID: true}
+	for _, id := range mentioned {
+		if notified[id] {
+			continue
+		}
+		notified[id] = true
+		recipients = append(recipients, id)
+	}
+	return recipients
+}
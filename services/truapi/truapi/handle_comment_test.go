@@ -0,0 +1,248 @@
+package truapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TruStory/truchain/x/claim"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+	"github.com/TruStory/octopus/services/truapi/truapi/cookies"
+)
+
+// fakeCommentStore keeps comments in memory, returning deleted ones from ClaimLevelComments like the database
+type fakeCommentStore struct {
+	db.Datastore
+	comments map[int64]*db.Comment
+}
+
+func (s *fakeCommentStore) CommentByID(id int64) (*db.Comment, error) {
+	comment, ok := s.comments[id]
+	if !ok {
+		return nil, pg.ErrNoRows
+	}
+	return comment, nil
+}
+
+func (s *fakeCommentStore) UpdateCommentBody(id int64, body string) (*db.Comment, error) {
+	comment := s.comments[id]
+	comment.Body = body
+	comment.UpdatedAt = time.Now()
+	return comment, nil
+}
+
+func (s *fakeCommentStore) DeleteComment(id int64) error {
+	now := time.Now()
+	s.comments[id].DeletedAt = &now
+	return nil
+}
+
+func (s *fakeCommentStore) ClaimLevelComments(claimID uint64) ([]db.Comment, error) {
+	comments := make([]db.Comment, 0)
+	for id := int64(1); id <= int64(len(s.comments)); id++ {
+		comments = append(comments, *s.comments[id])
+	}
+	return comments, nil
+}
+
+func newCommentTestAPI() (*TruAPI, *fakeCommentStore) {
+	store := &fakeCommentStore{comments: map[int64]*db.Comment{
+		1: {ID: 1, ClaimID: 7, Body: "first", Creator: "author"},
+		2: {ID: 2, ClaimID: 7, Body: "second", Creator: "author"},
+	}}
+	return &TruAPI{DBClient: store}, store
+}
+
+func commentRequest(method, address, body string) *http.Request {
+	r := httptest.NewRequest(method, "/comments", strings.NewReader(body))
+	user := &cookies.AuthenticatedUser{ID: 1, Address: address}
+	return r.WithContext(context.WithValue(r.Context(), userContextKey, user))
+}
+
+func noAdmins(ctx context.Context, address string) bool { return false }
+
+func TestEditComment(t *testing.T) {
+	ta, store := newCommentTestAPI()
+
+	w := httptest.NewRecorder()
+	ta.handleEditComment(w, commentRequest(http.MethodPut, "author", `{"id": 1, "body": " fixed typo "}`), noAdmins)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var edited db.Comment
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&edited))
+	assert.Equal(t, "fixed typo", edited.Body)
+	assert.False(t, store.comments[1].UpdatedAt.IsZero())
+
+	w = httptest.NewRecorder()
+	ta.handleEditComment(w, commentRequest(http.MethodPut, "someone-else", `{"id": 1, "body": "hijacked"}`), noAdmins)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "fixed typo", store.comments[1].Body)
+
+	admins := func(ctx context.Context, address string) bool { return address == "admin" }
+	w = httptest.NewRecorder()
+	ta.handleEditComment(w, commentRequest(http.MethodPut, "admin", `{"id": 1, "body": "moderated"}`), admins)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	ta.handleEditComment(w, commentRequest(http.MethodPut, "author", `{"id": 3, "body": "missing"}`), noAdmins)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeleteCommentHidesIt(t *testing.T) {
+	ta, _ := newCommentTestAPI()
+	claimID := uint64(7)
+
+	w := httptest.NewRecorder()
+	ta.handleDeleteComment(w, commentRequest(http.MethodDelete, "someone-else", `{"id": 1}`), noAdmins)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Len(t, ta.commentsResolver(context.Background(), queryCommentsParams{ClaimID: &claimID}), 2)
+
+	w = httptest.NewRecorder()
+	ta.handleDeleteComment(w, commentRequest(http.MethodDelete, "author", `{"id": 1}`), noAdmins)
+	assert.Equal(t, http.StatusOK, w.Code)
+	comments := ta.commentsResolver(context.Background(), queryCommentsParams{ClaimID: &claimID})
+	assert.Len(t, comments, 1)
+	assert.Equal(t, int64(2), comments[0].ID)
+
+	// deleted comments can't be edited or deleted again
+	w = httptest.NewRecorder()
+	ta.handleEditComment(w, commentRequest(http.MethodPut, "author", `{"id": 1, "body": "back"}`), noAdmins)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = httptest.NewRecorder()
+	ta.handleDeleteComment(w, commentRequest(http.MethodDelete, "author", `{"id": 1}`), noAdmins)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeleteCommentKeepsRepliesThreaded(t *testing.T) {
+	store := &fakeCommentStore{comments: map[int64]*db.Comment{
+		1: {ID: 1, ClaimID: 7, Body: "first", Creator: "author"},
+		2: {ID: 2, ParentID: 1, ClaimID: 7, Body: "reply", Creator: "author"},
+		3: {ID: 3, ParentID: 2, ClaimID: 7, Body: "nested reply", Creator: "someone-else"},
+	}}
+	ta := &TruAPI{DBClient: store}
+	claimID := uint64(7)
+
+	for _, id := range []string{"1", "2"} {
+		w := httptest.NewRecorder()
+		ta.handleDeleteComment(w, commentRequest(http.MethodDelete, "author", `{"id": `+id+`}`), noAdmins)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	comments := ta.commentsResolver(context.Background(), queryCommentsParams{ClaimID: &claimID})
+	assert.Len(t, comments, 3)
+	for _, tombstone := range comments[:2] {
+		assert.Equal(t, DeletedCommentBody, tombstone.Body)
+		assert.Empty(t, tombstone.Creator)
+	}
+	assert.Equal(t, int64(1), comments[1].ParentID)
+	assert.Equal(t, "nested reply", comments[2].Body)
+	count, err := ta.claimCommentCountResolver(context.Background(), claim.Claim{ID: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *count)
+
+	// once the last reply is gone, so are the placeholders
+	w := httptest.NewRecorder()
+	ta.handleDeleteComment(w, commentRequest(http.MethodDelete, "someone-else", `{"id": 3}`), noAdmins)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, ta.commentsResolver(context.Background(), queryCommentsParams{ClaimID: &claimID}))
+}
+
+func TestMentionRecipients(t *testing.T) {
+	// "@alice @bob @author @alice", as resolved by the mention resolver
+	mentioned := []int64{2, 3, 1, 2}
+	assert.Equal(t, []int64{2, 3}, mentionRecipients(mentioned, 1))
+	assert.Empty(t, mentionRecipients(nil, 1))
+	assert.Empty(t, mentionRecipients([]int64{1}, 1))
+}
+
+func TestValidateCommentBody(t *testing.T) {
+	_, err := validateCommentBody("", 10)
+	assert.Equal(t, Err400EmptyComment, err)
+	_, err = validateCommentBody(" \n\t ", 10)
+	assert.Equal(t, Err400EmptyComment, err)
+
+	// the limit counts characters, after trimming
+	body, err := validateCommentBody("  héllo wörld \n", 11)
+	assert.NoError(t, err)
+	assert.Equal(t, "héllo wörld", body)
+
+	_, err = validateCommentBody("hello world!", 11)
+	assert.EqualError(t, err, "Comment can't be longer than 11 characters")
+}
+
+func (s *fakeCommentStore) AddComment(comment *db.Comment) error {
+	comment.ID = int64(len(s.comments) + 1)
+	comment.CreatedAt = time.Now()
+	s.comments[comment.ID] = comment
+	return nil
+}
+
+func (s *fakeCommentStore) AddCommentOnce(comment *db.Comment) (bool, error) {
+	for _, existing := range s.comments {
+		if existing.Creator == comment.Creator && existing.IdempotencyKey == comment.IdempotencyKey && existing.DeletedAt == nil {
+			*comment = *existing
+			return false, nil
+		}
+	}
+	return true, s.AddComment(comment)
+}
+
+func (s *fakeCommentStore) MentionedUserIDs(body string) ([]int64, error) {
+	return nil, nil
+}
+
+func TestCreateCommentIdempotency(t *testing.T) {
+	ta, store := newCommentTestAPI()
+	ta.httpClient = &http.Client{}
+	communityOf := func(ctx context.Context, claimID int64) (string, bool) { return "crypto", claimID == 7 }
+	create := func(address, key, body string) (int, db.Comment) {
+		r := commentRequest(http.MethodPost, address, body)
+		if key != "" {
+			r.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		ta.handleCreateComment(w, r, communityOf)
+		var comment db.Comment
+		_ = json.NewDecoder(w.Body).Decode(&comment)
+		return w.Code, comment
+	}
+
+	code, first := create("author", "retry-1", `{"claim_id": 7, "body": "new comment"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(3), first.ID)
+	assert.Equal(t, "crypto", first.CommunityID)
+	assert.Len(t, store.comments, 3)
+
+	// a retry gets the same comment back
+	code, retried := create("author", "retry-1", `{"claim_id": 7, "body": "new comment"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, first.ID, retried.ID)
+	assert.Len(t, store.comments, 3)
+
+	// another key creates another comment
+	code, second := create("author", "retry-2", `{"claim_id": 7, "body": "new comment"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(4), second.ID)
+
+	// keys are scoped per user
+	_, other := create("someone-else", "retry-1", `{"claim_id": 7, "body": "new comment"}`)
+	assert.Equal(t, int64(5), other.ID)
+
+	// a key is free again once its comment is deleted
+	store.comments[second.ID].DeletedAt = &second.CreatedAt
+	_, recreated := create("author", "retry-2", `{"claim_id": 7, "body": "new comment"}`)
+	assert.Equal(t, int64(6), recreated.ID)
+
+	// without a key, comments are always created
+	_, third := create("author", "", `{"claim_id": 7, "body": "new comment"}`)
+	assert.Equal(t, int64(7), third.ID)
+
+	code, _ = create("author", strings.Repeat("k", 256), `{"claim_id": 7, "body": "new comment"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Len(t, store.comments, 7)
+}
@@ -1,10 +1,13 @@
 package truapi
 
 import (
-	"encoding/csv"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,12 +18,19 @@ import (
 	"github.com/TruStory/truchain/x/staking"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/TruStory/octopus/services/truapi/chttp"
 	"github.com/TruStory/octopus/services/truapi/db"
 	"github.com/TruStory/octopus/services/truapi/truapi/render"
 )
 
 const metricsVersion = "20190911-01"
 
+// userBaseBatchSize is the number of users loaded at a time for the user base export
+const userBaseBatchSize = 1000
+
+// userBaseCursorHeader is the header carrying the id to pass as `after_id` for the next page of the user base
+const userBaseCursorHeader = "Next-After-Id"
+
 type UserCommunityMetrics struct {
 	Claims                  int
 	Arguments               int
@@ -84,9 +94,75 @@ func (m *Metrics) getUserCommunityMetric(address, communityID string) *UserCommu
 	return ucm
 }
 
-func (ta *TruAPI) getClaimArguments(claimID uint64) ([]staking.Argument, error) {
+// claimsBeforeTime returns all claims created before the given time
+func (ta *TruAPI) claimsBeforeTime(ctx context.Context, before time.Time) ([]claim.Claim, error) {
+	claims := make([]claim.Claim, 0)
+	result, err := ta.QueryWithContext(
+		ctx,
+		path.Join(claim.QuerierRoute, claim.QueryClaimsBeforeTime),
+		claim.QueryClaimsTimeParams{CreatedTime: before},
+		claim.ModuleCodec,
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = claim.ModuleCodec.UnmarshalJSON(result, &claims)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// collectClaimMetrics adds the participation of every user in a claim before the given time to the metrics
+func (ta *TruAPI) collectClaimMetrics(ctx context.Context, cache *claimMetricsCache, chainMetrics *Metrics, claim claim.Claim, before time.Time) error {
+	if !claim.CreatedTime.Before(before) {
+		return nil
+	}
+	argumentIDCreator := make(map[uint64]string)
+	ucm := chainMetrics.getUserCommunityMetric(claim.Creator.String(), claim.CommunityID)
+	ucm.Claims++
+	arguments, err := cache.claimArguments(ctx, claim.ID)
+	if err != nil {
+		return err
+	}
+	for _, argument := range arguments {
+		if !argument.CreatedTime.Before(before) {
+			continue
+		}
+		acm := chainMetrics.getUserCommunityMetric(argument.Creator.String(), claim.CommunityID)
+		acm.Arguments++
+		argumentIDCreator[argument.ID] = argument.Creator.String()
+	}
+	grace := time.Duration(ta.APIContext.Config.Metrics.StakeExpirationGrace) * time.Hour
+	stakes, err := cache.claimStakes(ctx, claim.ID)
+	if err != nil {
+		return err
+	}
+	for _, stake := range stakes {
+		if !stake.CreatedTime.Before(before) {
+			continue
+		}
+		scm := chainMetrics.getUserCommunityMetric(stake.Creator.String(), claim.CommunityID)
+		if !stake.Expired || notExpiredAt(before, stake.CreatedTime, stake.EndTime, grace) {
+			scm.PendingStake = scm.PendingStake.Add(stake.Amount)
+		}
+		if stake.Type == staking.StakeUpvote {
+			scm.StakedAgree = scm.StakedAgree.Add(stake.Amount)
+			chainMetrics.getUserCommunityMetric(argumentIDCreator[stake.ArgumentID], stake.CommunityID).AgreesReceived++
+			scm.AgreesGiven++
+		}
+
+		if stake.Type != staking.StakeUpvote {
+			scm.StakedArgument = scm.StakedArgument.Add(stake.Amount)
+		}
+		scm.Staked = scm.Staked.Add(stake.Amount)
+	}
+	return nil
+}
+
+func (ta *TruAPI) getClaimArguments(ctx context.Context, claimID uint64) ([]staking.Argument, error) {
 	queryRoute := path.Join(staking.ModuleName, staking.QueryClaimArguments)
-	res, err := ta.Query(queryRoute, staking.QueryClaimArgumentsParams{ClaimID: claimID}, staking.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryClaimArgumentsParams{ClaimID: claimID}, staking.ModuleCodec)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +175,9 @@ func (ta *TruAPI) getClaimArguments(claimID uint64) ([]staking.Argument, error)
 	return arguments, nil
 }
 
-func notExpiredAt(date, created, end time.Time) bool {
+// notExpiredAt tells whether a stake was still pending at the given date,
+// counting stakes that ended within the grace period as pending.
+func notExpiredAt(date, created, end time.Time, grace time.Duration) bool {
 	betaReleaseDate, err := time.Parse("2006-01-02", "2019-07-11")
 	if err != nil {
 		return false
@@ -113,7 +191,7 @@ func notExpiredAt(date, created, end time.Time) bool {
 	if date.Before(created) {
 		return false
 	}
-	if date.After(end) {
+	if date.After(end.Add(grace)) {
 		return false
 	}
 	if !created.Before(end) {
@@ -143,17 +221,7 @@ func (ta *TruAPI) HandleUsersMetrics(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get all claims
-	claims := make([]claim.Claim, 0)
-	result, err := ta.Query(
-		path.Join(claim.QuerierRoute, claim.QueryClaimsBeforeTime),
-		claim.QueryClaimsTimeParams{CreatedTime: beforeDate},
-		claim.ModuleCodec,
-	)
-	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	err = claim.ModuleCodec.UnmarshalJSON(result, &claims)
+	claims, err := ta.claimsBeforeTime(r.Context(), beforeDate)
 	if err != nil {
 		render.Error(w, r, err.Error(), http.StatusInternalServerError)
 		return
@@ -164,65 +232,36 @@ func (ta *TruAPI) HandleUsersMetrics(w http.ResponseWriter, r *http.Request) {
 	err = ta.DBClient.FindAll(&users)
 	if err != nil {
 		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
 	}
+	chainMetrics := &Metrics{UserMetrics: make(map[string]*UserMetrics)}
 
+	cache := ta.newClaimMetricsCache()
+	err = cache.prefetch(r.Context(), claims)
 	if err != nil {
 		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
 	}
-	chainMetrics := &Metrics{UserMetrics: make(map[string]*UserMetrics)}
-
 	for _, claim := range claims {
-		if !claim.CreatedTime.Before(beforeDate) {
-			continue
-		}
-		argumentIDCreator := make(map[uint64]string)
-		ucm := chainMetrics.getUserCommunityMetric(claim.Creator.String(), claim.CommunityID)
-		ucm.Claims++
-		arguments, err := ta.getClaimArguments(claim.ID)
+		err = ta.collectClaimMetrics(r.Context(), cache, chainMetrics, claim, beforeDate)
 		if err != nil {
 			render.Error(w, r, err.Error(), http.StatusInternalServerError)
-		}
-		for _, argument := range arguments {
-			if !argument.CreatedTime.Before(beforeDate) {
-				continue
-			}
-			acm := chainMetrics.getUserCommunityMetric(argument.Creator.String(), claim.CommunityID)
-			acm.Arguments++
-			argumentIDCreator[argument.ID] = argument.Creator.String()
-		}
-		stakes := ta.claimStakesResolver(r.Context(), claim)
-		for _, stake := range stakes {
-			if !stake.CreatedTime.Before(beforeDate) {
-				continue
-			}
-			scm := chainMetrics.getUserCommunityMetric(stake.Creator.String(), claim.CommunityID)
-			if !stake.Expired || notExpiredAt(beforeDate, stake.CreatedTime, stake.EndTime) {
-				scm.PendingStake = scm.PendingStake.Add(stake.Amount)
-			}
-			if stake.Type == staking.StakeUpvote {
-				scm.StakedAgree = scm.StakedAgree.Add(stake.Amount)
-				chainMetrics.getUserCommunityMetric(argumentIDCreator[stake.ArgumentID], stake.CommunityID).AgreesReceived++
-				scm.AgreesGiven++
-			}
-
-			if stake.Type != staking.StakeUpvote {
-				scm.StakedArgument = scm.StakedArgument.Add(stake.Amount)
-			}
-			scm.Staked = scm.Staked.Add(stake.Amount)
-
+			return
 		}
 	}
 	// Get all communities
 	queryRoute := path.Join(community.QuerierRoute, community.QueryCommunities)
-	res, err := ta.Query(queryRoute, struct{}{}, community.ModuleCodec)
+	res, err := ta.QueryWithContext(r.Context(), queryRoute, struct{}{}, community.ModuleCodec)
 	if err != nil {
 		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
 	communities := make([]community.Community, 0)
 	err = community.ModuleCodec.UnmarshalJSON(res, &communities)
 	if err != nil {
 		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
 	}
 	if len(communities) == 0 {
 		render.Error(w, r, "no communities found", http.StatusInternalServerError)
@@ -242,8 +281,7 @@ func (ta *TruAPI) HandleUsersMetrics(w http.ResponseWriter, r *http.Request) {
 		exported.TransactionStakeCreatorSlashed,
 		exported.TransactionStakeCuratorSlashed,
 	}
-	w.Header().Add("Content-Type", "text/csv")
-	csvw := csv.NewWriter(w)
+	csvw := newCSVResponseWriter(w, r)
 	header := []string{"job_date_time", "date", "address", "username", "balance",
 		"community", "community_name", "stake_earned",
 		"claims_created", "claims_opened", "unique_claims_opened",
@@ -254,13 +292,19 @@ func (ta *TruAPI) HandleUsersMetrics(w http.ResponseWriter, r *http.Request) {
 		"replies",
 		"arguments_opened", "unique_arguments_opened",
 	}
+	includeVersion := r.FormValue("include_version") == "true"
+	if includeVersion {
+		header = append([]string{"metrics_version"}, header...)
+	}
 	err = csvw.Write(header)
 	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		csvw.Abort(w, r, err.Error())
+		return
 	}
 	openedClaims, err := ta.DBClient.OpenedClaimsSummary(beforeDate)
 	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		csvw.Abort(w, r, err.Error())
+		return
 	}
 	for _, userOpenedClaims := range openedClaims {
 		userMetrics := chainMetrics.getUserCommunityMetric(userOpenedClaims.Address, userOpenedClaims.CommunityID)
@@ -271,7 +315,7 @@ func (ta *TruAPI) HandleUsersMetrics(w http.ResponseWriter, r *http.Request) {
 	openedArguments, err := ta.DBClient.OpenedArgumentsSummary(beforeDate)
 	if err != nil {
 		fmt.Println(err)
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		csvw.Abort(w, r, err.Error())
 		return
 	}
 	for _, userOpenedArguments := range openedArguments {
@@ -283,18 +327,24 @@ func (ta *TruAPI) HandleUsersMetrics(w http.ResponseWriter, r *http.Request) {
 	replies, err := ta.DBClient.UserRepliesStats(beforeDate)
 	if err != nil {
 		fmt.Println(err)
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		csvw.Abort(w, r, err.Error())
 		return
 	}
 	for _, userReplies := range replies {
 		userMetrics := chainMetrics.getUserCommunityMetric(userReplies.Address, userReplies.CommunityID)
 		userMetrics.Replies = userReplies.Replies
 	}
+	userTransactions, err := ta.transactionsByAddress(r.Context())
+	if err != nil {
+		fmt.Println(err)
+		csvw.Abort(w, r, err.Error())
+		return
+	}
 	for _, user := range users {
 		if user.Address == "" || !user.CreatedAt.Before(beforeDate) {
 			continue
 		}
-		transactions := ta.appAccountTransactionsResolver(r.Context(), queryByAddress{ID: user.Address})
+		transactions := userTransactions[user.Address]
 		balance := sdk.NewInt64Coin(app.StakeDenom, 0)
 		for _, transaction := range transactions {
 			if !transaction.CreatedTime.Before(beforeDate) {
@@ -309,10 +359,8 @@ func (ta *TruAPI) HandleUsersMetrics(w http.ResponseWriter, r *http.Request) {
 				continue
 			}
 			if transaction.CommunityID == "" {
-				render.Error(w, r,
-					fmt.Sprintf("transaction %s [%d] must contain community id",
-						transaction.Type.String(), transaction.ID),
-					http.StatusInternalServerError)
+				csvw.Abort(w, r, fmt.Sprintf("transaction %s [%d] must contain community id",
+					transaction.Type.String(), transaction.ID))
 				return
 			}
 
@@ -347,6 +395,11 @@ func (ta *TruAPI) HandleUsersMetrics(w http.ResponseWriter, r *http.Request) {
 		}
 		// "job_time", "date", "address", "username", "balance"
 		rowStart := []string{jobTime, beforeDate.Format(time.RFC3339Nano), user.Address, user.Username, balance.Amount.String()}
+		if includeVersion {
+			rowStart = append([]string{metricsVersion}, rowStart...)
+		}
+		// records append to rowStart, make sure each of them gets its own copy
+		rowStart = rowStart[:len(rowStart):len(rowStart)]
 
 		for _, community := range communities {
 			// 	"community", "community_name"
@@ -383,13 +436,16 @@ func (ta *TruAPI) HandleUsersMetrics(w http.ResponseWriter, r *http.Request) {
 			record = append(record, fmt.Sprintf("%d", m.UniqueArgumentsOpened))
 			err = csvw.Write(record)
 			if err != nil {
-				render.Error(w, r, err.Error(), http.StatusInternalServerError)
+				csvw.Abort(w, r, err.Error())
 				return
 			}
 		}
 		csvw.Flush()
 	}
-
+	err = csvw.Close()
+	if err != nil {
+		fmt.Println("HandleUsersMetrics err: ", err)
+	}
 }
 
 // HandleClaimMetrics returns metrics for claims
@@ -401,45 +457,12 @@ func (ta *TruAPI) HandleClaimMetrics(w http.ResponseWriter, r *http.Request) {
 		render.Error(w, r, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	date := r.FormValue("date")
-	if date == "" {
-		render.Error(w, r, "provide a valid date", http.StatusBadRequest)
-		return
-	}
-
-	beforeDate, err := time.Parse("2006-01-02", date)
+	dates, err := claimMetricsDates(r.FormValue("date"), r.FormValue("from"), r.FormValue("to"))
 	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		render.Error(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
-	// Get all claims
-	claims := make([]claim.Claim, 0)
-	result, err := ta.Query(
-		path.Join(claim.QuerierRoute, claim.QueryClaimsBeforeTime),
-		claim.QueryClaimsTimeParams{CreatedTime: beforeDate},
-		claim.ModuleCodec,
-	)
-	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	err = claim.ModuleCodec.UnmarshalJSON(result, &claims)
-	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	claimViewsStats, err := ta.DBClient.ClaimViewsStats(beforeDate)
-	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	claimRepliesStats, err := ta.DBClient.ClaimRepliesStats(beforeDate)
-	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	w.Header().Add("Content-Type", "text/csv")
-	csvw := csv.NewWriter(w)
+	csvw := newCSVResponseWriter(w, r)
 	header := []string{
 		"job_date_time", "date", "created_date", "flagged", "id", "community_id", "claim_name",
 		"arguments_created", "agrees_given",
@@ -458,41 +481,106 @@ func (ta *TruAPI) HandleClaimMetrics(w http.ResponseWriter, r *http.Request) {
 		"last_activiy_argument",
 		"last_activity_agree",
 	}
+	includeVersion := r.FormValue("include_version") == "true"
+	if includeVersion {
+		header = append([]string{"metrics_version"}, header...)
+	}
 	err = csvw.Write(header)
 	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		csvw.Abort(w, r, err.Error())
+		return
 	}
-	// claim stats
-	claimViewsStatsMappings := make(map[uint64]int)
-	for idx, c := range claimViewsStats {
-		claimViewsStatsMappings[uint64(c.ClaimID)] = idx
+	flaggedClaimsIDs, err := ta.DBClient.FlaggedStoriesIDs(ta.APIContext.Config.Flag.Admin, ta.APIContext.Config.Flag.Limit)
+	if err != nil {
+		csvw.Abort(w, r, err.Error())
+		return
 	}
-	getClaimViewsStats := func(claimID uint64) db.ClaimViewsStats {
-		index, ok := claimViewsStatsMappings[claimID]
-		if !ok {
-			return db.ClaimViewsStats{}
-		}
-		return claimViewsStats[index]
+	flaggedClaimsMappings := make(map[uint64]int)
+	for _, c := range flaggedClaimsIDs {
+		flaggedClaimsMappings[uint64(c)] = 1
 	}
-	claimRepliesStatsMappings := make(map[uint64]int)
-	for idx, c := range claimRepliesStats {
-		claimRepliesStatsMappings[uint64(c.ClaimID)] = idx
+	// everything is loaded once up to the last date, and each date sums what happened before it
+	lastDate := dates[len(dates)-1]
+	claims, err := ta.claimsBeforeTime(r.Context(), lastDate)
+	if err != nil {
+		csvw.Abort(w, r, err.Error())
+		return
 	}
-	getClaimRepliesStats := func(claimID uint64) db.ClaimRepliesStats {
-		index, ok := claimRepliesStatsMappings[claimID]
-		if !ok {
-			return db.ClaimRepliesStats{}
+	cache := ta.newClaimMetricsCache()
+	err = cache.prefetch(r.Context(), claims)
+	if err != nil {
+		csvw.Abort(w, r, err.Error())
+		return
+	}
+	viewsByDay, err := ta.DBClient.ClaimViewsStatsByDay(lastDate)
+	if err != nil {
+		csvw.Abort(w, r, err.Error())
+		return
+	}
+	repliesByDay, err := ta.DBClient.ClaimRepliesStatsByDay(lastDate)
+	if err != nil {
+		csvw.Abort(w, r, err.Error())
+		return
+	}
+	// each date is its own section, distinguished by the date column
+	for _, beforeDate := range dates {
+		stats := claimStatsBefore(viewsByDay, repliesByDay, beforeDate)
+		err = ta.writeClaimMetrics(r.Context(), cache, csvw, len(header), includeVersion, jobTime, beforeDate,
+			claims, stats, flaggedClaimsMappings)
+		if err != nil {
+			csvw.Abort(w, r, err.Error())
+			return
 		}
-		return claimRepliesStats[index]
 	}
-	flaggedClaimsIDs, err := ta.DBClient.FlaggedStoriesIDs(ta.APIContext.Config.Flag.Admin, ta.APIContext.Config.Flag.Limit)
+	err = csvw.Close()
 	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		fmt.Println("HandleClaimMetrics err: ", err)
 	}
-	flaggedClaimsMappings := make(map[uint64]int)
-	for _, c := range flaggedClaimsIDs {
-		flaggedClaimsMappings[uint64(c)] = 1
+}
+
+// claimStats holds the views and replies of claims before a date, by claim id
+type claimStats struct {
+	views   map[uint64]db.ClaimViewsStats
+	replies map[uint64]db.ClaimRepliesStats
+}
+
+// claimStatsBefore sums the daily views and replies of every claim on the days before the given date
+func claimStatsBefore(viewsByDay []db.ClaimDayViewsStats, repliesByDay []db.ClaimDayRepliesStats, beforeDate time.Time) claimStats {
+	stats := claimStats{
+		views:   make(map[uint64]db.ClaimViewsStats),
+		replies: make(map[uint64]db.ClaimRepliesStats),
+	}
+	for _, day := range viewsByDay {
+		if !day.Day.Before(beforeDate) {
+			continue
+		}
+		views := stats.views[uint64(day.ClaimID)]
+		views.ClaimID = day.ClaimID
+		views.UserViews += day.UserViews
+		views.UniqueUserViews += day.UniqueUserViews
+		views.AnonViews += day.AnonViews
+		views.UniqueAnonViews += day.UniqueAnonViews
+		views.UserArgumentsViews += day.UserArgumentsViews
+		views.UniqueUserArgumentsViews += day.UniqueUserArgumentsViews
+		views.AnonArgumentsViews += day.AnonArgumentsViews
+		views.UniqueAnonArgumentsViews += day.UniqueAnonArgumentsViews
+		stats.views[uint64(day.ClaimID)] = views
+	}
+	for _, day := range repliesByDay {
+		if !day.Day.Before(beforeDate) {
+			continue
+		}
+		replies := stats.replies[uint64(day.ClaimID)]
+		replies.ClaimID = day.ClaimID
+		replies.Replies += day.Replies
+		stats.replies[uint64(day.ClaimID)] = replies
 	}
+	return stats
+}
+
+// writeClaimMetrics writes the metrics of every claim created before the given date
+func (ta *TruAPI) writeClaimMetrics(ctx context.Context, cache *claimMetricsCache, csvw *csvWriter, columns int, includeVersion bool, jobTime string,
+	beforeDate time.Time, claims []claim.Claim, stats claimStats, flaggedClaimsMappings map[uint64]int) error {
 	for _, claim := range claims {
 		if !claim.CreatedTime.Before(beforeDate) {
 			continue
@@ -508,7 +596,10 @@ func (ta *TruAPI) HandleClaimMetrics(w http.ResponseWriter, r *http.Request) {
 		var lastActivityArgument time.Time
 		var lastActivityAgree time.Time
 		mapArguments := make(map[uint64]int)
-		arguments, err := ta.getClaimArguments(claim.ID)
+		arguments, err := cache.claimArguments(ctx, claim.ID)
+		if err != nil {
+			return err
+		}
 
 		for idx, argument := range arguments {
 			mapArguments[argument.ID] = idx
@@ -520,17 +611,17 @@ func (ta *TruAPI) HandleClaimMetrics(w http.ResponseWriter, r *http.Request) {
 			}
 			totalArguments++
 		}
+		stakes, err := cache.claimStakes(ctx, claim.ID)
 		if err != nil {
-			render.Error(w, r, err.Error(), http.StatusInternalServerError)
+			return err
 		}
-		stakes := ta.claimStakesResolver(r.Context(), claim)
 		for _, stake := range stakes {
 			if !stake.CreatedTime.Before(beforeDate) {
 				continue
 			}
 			i, ok := mapArguments[stake.ArgumentID]
 			if !ok {
-				render.Error(w, r, fmt.Sprintf("unable to find argument with id %d", stake.ArgumentID), http.StatusInternalServerError)
+				return fmt.Errorf("unable to find argument with id %d", stake.ArgumentID)
 			}
 			a := arguments[i]
 			if stake.Type == staking.StakeUpvote && lastActivityAgree.Before(stake.CreatedTime) {
@@ -560,8 +651,8 @@ func (ta *TruAPI) HandleClaimMetrics(w http.ResponseWriter, r *http.Request) {
 
 		}
 		body := strings.ReplaceAll(claim.Body, "\n", " ")
-		viewsStats := getClaimViewsStats(claim.ID)
-		repliesStats := getClaimRepliesStats(claim.ID)
+		viewsStats := stats.views[claim.ID]
+		repliesStats := stats.replies[claim.ID]
 		lastActivityArgumentDateString := ""
 		if !lastActivityArgument.IsZero() {
 			lastActivityArgumentDateString = lastActivityArgument.Format(time.RFC3339Nano)
@@ -598,33 +689,72 @@ func (ta *TruAPI) HandleClaimMetrics(w http.ResponseWriter, r *http.Request) {
 			lastActivityArgumentDateString,
 			lastActivityAgreeDateString,
 		}
-		if len(header) != len(row) {
-			render.Error(w, r, "header and row content mismatch", http.StatusInternalServerError)
-			return
+		if includeVersion {
+			row = append([]string{metricsVersion}, row...)
+		}
+		if columns != len(row) {
+			return errors.New("header and row content mismatch")
 		}
 		err = csvw.Write(row)
 		if err != nil {
-			render.Error(w, r, err.Error(), http.StatusInternalServerError)
-			return
+			return err
 		}
 		csvw.Flush()
 	}
+	return nil
 }
 
-func (ta *TruAPI) HandleUserClaims(w http.ResponseWriter, r *http.Request) {
-	jobTime := time.Now().UTC().Format("200601021504")
-	w.Header().Add("Content-Type", "text/csv")
-	csvw := csv.NewWriter(w)
-	header := []string{
-		"job_date_time", "date", "claim_id", "claim", "community", "address", "creation_date", "participants",
+// claimMetricsDates returns the dates to report claim metrics for, either a single `date`
+// or every day from `from` to `to` inclusive
+func claimMetricsDates(date, from, to string) ([]time.Time, error) {
+	if from == "" && to == "" {
+		if date == "" {
+			return nil, errors.New("provide a valid date")
+		}
+		beforeDate, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, err
+		}
+		return []time.Time{beforeDate}, nil
 	}
-	err := csvw.Write(header)
+	if from == "" || to == "" {
+		return nil, errors.New("provide both from and to dates")
+	}
+	fromDate, err := time.Parse("2006-01-02", from)
 	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
-		return
+		return nil, err
+	}
+	toDate, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return nil, err
+	}
+	if fromDate.After(toDate) {
+		return nil, errors.New("from date must not be after to date")
 	}
+	dates := make([]time.Time, 0)
+	for d := fromDate; !d.After(toDate); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	return dates, nil
+}
+
+// UserClaimRow represents a claim in the user claims export
+type UserClaimRow struct {
+	JobDateTime  string `json:"job_date_time"`
+	Date         string `json:"date"`
+	ClaimID      uint64 `json:"claim_id"`
+	Claim        string `json:"claim"`
+	Community    string `json:"community"`
+	Address      string `json:"address"`
+	CreationDate string `json:"creation_date"`
+	Participants int    `json:"participants"`
+}
+
+// HandleUserClaims exports the claims created before a date as CSV, or as newline-delimited JSON with `format=json`.
+func (ta *TruAPI) HandleUserClaims(w http.ResponseWriter, r *http.Request) {
+	jobTime := time.Now().UTC().Format("200601021504")
 	ctx := ta.createContext(r.Context())
-	err = r.ParseForm()
+	err := r.ParseForm()
 	if err != nil {
 		render.Error(w, r, err.Error(), http.StatusInternalServerError)
 		return
@@ -642,7 +772,8 @@ func (ta *TruAPI) HandleUserClaims(w http.ResponseWriter, r *http.Request) {
 	}
 	// Get all claims
 	claims := make([]claim.Claim, 0)
-	result, err := ta.Query(
+	result, err := ta.QueryWithContext(
+		ctx,
 		path.Join(claim.QuerierRoute, claim.QueryClaimsBeforeTime),
 		claim.QueryClaimsTimeParams{CreatedTime: targetDate},
 		claim.ModuleCodec,
@@ -656,6 +787,26 @@ func (ta *TruAPI) HandleUserClaims(w http.ResponseWriter, r *http.Request) {
 		render.Error(w, r, err.Error(), http.StatusInternalServerError)
 		return
 	}
+
+	jsonFormat := r.FormValue("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/x-ndjson")
+	var csvw *csvWriter
+	var jsonw *json.Encoder
+	if jsonFormat {
+		w.Header().Add("Content-Type", "application/x-ndjson")
+		jsonw = json.NewEncoder(w)
+	} else {
+		csvw = newCSVResponseWriter(w, r)
+		header := []string{
+			"job_date_time", "date", "claim_id", "claim", "community", "address", "creation_date", "participants",
+		}
+		err = csvw.Write(header)
+		if err != nil {
+			csvw.Abort(w, r, err.Error())
+			return
+		}
+	}
+	flusher, _ := w.(http.Flusher)
+
 	previousDay := targetDate.Add(-24 * time.Hour)
 	for _, claim := range claims {
 		if !claim.CreatedTime.Before(targetDate) {
@@ -685,67 +836,179 @@ func (ta *TruAPI) HandleUserClaims(w http.ResponseWriter, r *http.Request) {
 				participantsPreviousDay[c.Creator] = true
 			}
 		}
-		// "job_date_time", "claim_id", "claim", "community", "address", "creation_date", "participants",
-		row := []string{jobTime, targetDate.Format(time.RFC3339Nano), fmt.Sprintf("%d", claim.ID),
-			claim.Body, claim.CommunityID, claim.Creator.String(), claim.CreatedTime.Format(time.RFC3339Nano),
-			fmt.Sprintf("%d", len(participantsTarget)-len(participantsPreviousDay)),
+		row := UserClaimRow{
+			JobDateTime:  jobTime,
+			Date:         targetDate.Format(time.RFC3339Nano),
+			ClaimID:      claim.ID,
+			Claim:        claim.Body,
+			Community:    claim.CommunityID,
+			Address:      claim.Creator.String(),
+			CreationDate: claim.CreatedTime.Format(time.RFC3339Nano),
+			Participants: len(participantsTarget) - len(participantsPreviousDay),
 		}
-		err := csvw.Write(row)
+		if jsonFormat {
+			err = jsonw.Encode(row)
+			if err != nil {
+				render.Error(w, r, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			continue
+		}
+		// "job_date_time", "claim_id", "claim", "community", "address", "creation_date", "participants",
+		err = csvw.Write([]string{row.JobDateTime, row.Date, fmt.Sprintf("%d", row.ClaimID),
+			row.Claim, row.Community, row.Address, row.CreationDate,
+			fmt.Sprintf("%d", row.Participants),
+		})
 		if err != nil {
-			render.Error(w, r, err.Error(), http.StatusInternalServerError)
+			csvw.Abort(w, r, err.Error())
 			return
 		}
 		csvw.Flush()
 	}
+	if csvw != nil {
+		err = csvw.Close()
+		if err != nil {
+			fmt.Println("HandleUserClaims err: ", err)
+		}
+	}
 }
 
-// HandleUserBase returns the user base.
-func (ta *TruAPI) HandleUserBase(w http.ResponseWriter, r *http.Request) {
+// HandleUserBase streams the user base. Soft-deleted users are only included with `include_deleted=true`.
+// Users are exported in id order and can be paged through with the `after_id` cursor and `limit`.
+// Users without an address are skipped, so the cursor of the next page is sent in the Next-After-Id header.
+func (ta *TruAPI) HandleUserBase(r *http.Request, sw *chttp.StreamWriter) chttp.Response {
 	token := ta.APIContext.Config.Metrics.Secret
 	if token == "" || token != r.Header.Get("Metrics-Secret") {
-		render.Error(w, r, "Invalid token", http.StatusUnauthorized)
-		return
+		return chttp.SimpleErrorResponse(http.StatusUnauthorized, errors.New("Invalid token"))
 	}
 
-	w.Header().Add("Content-Type", "text/csv")
-	csvw := csv.NewWriter(w)
+	includeDeleted := r.FormValue("include_deleted") == "true"
+	var afterID int64
+	var limit int
+	var err error
+	if r.FormValue("after_id") != "" {
+		afterID, err = strconv.ParseInt(r.FormValue("after_id"), 10, 64)
+		if err != nil {
+			return chttp.SimpleErrorResponse(http.StatusBadRequest, errors.New("invalid after_id"))
+		}
+	}
+	if r.FormValue("limit") != "" {
+		limit, err = strconv.Atoi(r.FormValue("limit"))
+		if err != nil || limit < 0 {
+			return chttp.SimpleErrorResponse(http.StatusBadRequest, errors.New("invalid limit"))
+		}
+	}
+
+	// a page is loaded before it's written, so that its cursor can be sent in a header
+	var page []db.User
+	if limit > 0 {
+		page, err = ta.userBasePage(includeDeleted, afterID, limit)
+		if err != nil {
+			return chttp.SimpleErrorResponse(http.StatusInternalServerError, err)
+		}
+		if len(page) > 0 {
+			sw.Header().Set(userBaseCursorHeader, strconv.FormatInt(page[len(page)-1].ID, 10))
+		}
+	}
+
+	csvw := newCSVResponseWriter(sw, r)
 	header := []string{
 		"address", "username", "email", "creation_date", "updated_date", "last_login", "user_group",
 	}
-	err := csvw.Write(header)
-	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
-		return
+	if includeDeleted {
+		header = append(header, "deleted_at")
 	}
-	// For each user, get the available stake calculated.
-	users := make([]db.User, 0)
-	err = ta.DBClient.FindAll(&users)
+	err = csvw.Write(header)
 	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
-		return
+		return chttp.SimpleErrorResponse(http.StatusInternalServerError, err)
 	}
-	for _, user := range users {
-		if user.Address == "" {
-			continue
+
+	writeUsers := func(users []db.User) error {
+		for _, user := range users {
+			if user.Address == "" {
+				continue
+			}
+			lastLogin := ""
+			if user.LastAuthenticatedAt != nil {
+				lastLogin = user.LastAuthenticatedAt.Format(time.RFC3339Nano)
+			}
+			row := []string{
+				user.Address,
+				user.Username,
+				user.Email,
+				user.CreatedAt.Format(time.RFC3339Nano),
+				user.UpdatedAt.Format(time.RFC3339Nano),
+				lastLogin,
+				user.UserGroup.String(),
+			}
+			if includeDeleted {
+				deletedAt := ""
+				if user.DeletedAt != nil {
+					deletedAt = user.DeletedAt.Format(time.RFC3339Nano)
+				}
+				row = append(row, deletedAt)
+			}
+			err := csvw.Write(row)
+			if err != nil {
+				return err
+			}
 		}
-		lastLogin := ""
-		if user.LastAuthenticatedAt != nil {
-			lastLogin = user.LastAuthenticatedAt.Format(time.RFC3339Nano)
+		csvw.Flush()
+		if err := csvw.Error(); err != nil {
+			return err
 		}
-		row := []string{
-			user.Address,
-			user.Username,
-			user.Email,
-			user.CreatedAt.Format(time.RFC3339Nano),
-			user.UpdatedAt.Format(time.RFC3339Nano),
-			lastLogin,
-			user.UserGroup.String(),
+		sw.Flush()
+		return nil
+	}
+
+	if limit > 0 {
+		err = writeUsers(page)
+		if err != nil {
+			return chttp.SimpleErrorResponse(http.StatusInternalServerError, err)
 		}
-		err := csvw.Write(row)
+	} else {
+		for {
+			users, err := ta.DBClient.UsersForExport(includeDeleted, afterID, userBaseBatchSize)
+			if err != nil {
+				return chttp.SimpleErrorResponse(http.StatusInternalServerError, err)
+			}
+			err = writeUsers(users)
+			if err != nil {
+				return chttp.SimpleErrorResponse(http.StatusInternalServerError, err)
+			}
+			if len(users) < userBaseBatchSize {
+				break
+			}
+			afterID = users[len(users)-1].ID
+		}
+	}
+	err = csvw.Close()
+	if err != nil {
+		return chttp.SimpleErrorResponse(http.StatusInternalServerError, err)
+	}
+	return nil
+}
+
+// userBasePage loads up to limit users of the user base after the given id, in batches
+func (ta *TruAPI) userBasePage(includeDeleted bool, afterID int64, limit int) ([]db.User, error) {
+	page := make([]db.User, 0)
+	for len(page) < limit {
+		batchSize := userBaseBatchSize
+		if limit-len(page) < batchSize {
+			batchSize = limit - len(page)
+		}
+		users, err := ta.DBClient.UsersForExport(includeDeleted, afterID, batchSize)
 		if err != nil {
-			render.Error(w, r, err.Error(), http.StatusInternalServerError)
-			return
+			return nil, err
 		}
-		csvw.Flush()
+		page = append(page, users...)
+		if len(users) < batchSize {
+			break
+		}
+		afterID = users[len(users)-1].ID
 	}
+	return page, nil
 }
@@ -0,0 +1,155 @@
+package truapi
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+func TestNotExpiredAt(t *testing.T) {
+	cutoff := time.Date(2019, 9, 12, 0, 0, 0, 0, time.UTC)
+	created := cutoff.Add(-7 * 24 * time.Hour)
+	end := cutoff.Add(-time.Minute)
+
+	// without a grace period a stake ending before the cutoff is expired
+	assert.False(t, notExpiredAt(cutoff, created, end, 0))
+	// within the grace window it still counts as pending
+	assert.True(t, notExpiredAt(cutoff, created, end, time.Hour))
+	// past the grace window it is expired
+	assert.False(t, notExpiredAt(cutoff.Add(2*time.Hour), created, end, time.Hour))
+	// a stake still running is pending
+	assert.True(t, notExpiredAt(cutoff, created, cutoff.Add(time.Hour), 0))
+	// stakes created before beta are always expired
+	assert.False(t, notExpiredAt(cutoff, time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC), cutoff.Add(time.Hour), time.Hour))
+}
+
+func TestClaimMetricsDates(t *testing.T) {
+	dates, err := claimMetricsDates("2019-09-12", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{time.Date(2019, 9, 12, 0, 0, 0, 0, time.UTC)}, dates)
+
+	dates, err = claimMetricsDates("", "2019-09-10", "2019-09-12")
+	assert.NoError(t, err)
+	assert.Len(t, dates, 3)
+	assert.Equal(t, time.Date(2019, 9, 10, 0, 0, 0, 0, time.UTC), dates[0])
+	assert.Equal(t, time.Date(2019, 9, 12, 0, 0, 0, 0, time.UTC), dates[2])
+
+	_, err = claimMetricsDates("", "2019-09-12", "2019-09-10")
+	assert.EqualError(t, err, "from date must not be after to date")
+
+	_, err = claimMetricsDates("", "", "")
+	assert.Error(t, err)
+}
+
+func TestClaimStatsBefore(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2019, 9, d, 0, 0, 0, 0, time.UTC) }
+	views := []db.ClaimDayViewsStats{
+		{Day: day(10), ClaimViewsStats: db.ClaimViewsStats{ClaimID: 1, UserViews: 2, UniqueUserViews: 1}},
+		{Day: day(11), ClaimViewsStats: db.ClaimViewsStats{ClaimID: 1, UserViews: 3, UniqueUserViews: 2, AnonViews: 1}},
+		{Day: day(11), ClaimViewsStats: db.ClaimViewsStats{ClaimID: 2, AnonArgumentsViews: 4}},
+		{Day: day(12), ClaimViewsStats: db.ClaimViewsStats{ClaimID: 1, UserViews: 7}},
+	}
+	replies := []db.ClaimDayRepliesStats{
+		{Day: day(10), ClaimRepliesStats: db.ClaimRepliesStats{ClaimID: 1, Replies: 1}},
+		{Day: day(11), ClaimRepliesStats: db.ClaimRepliesStats{ClaimID: 1, Replies: 2}},
+		{Day: day(12), ClaimRepliesStats: db.ClaimRepliesStats{ClaimID: 2, Replies: 5}},
+	}
+
+	stats := claimStatsBefore(views, replies, day(12))
+	assert.Equal(t, map[uint64]db.ClaimViewsStats{
+		1: {ClaimID: 1, UserViews: 5, UniqueUserViews: 3, AnonViews: 1},
+		2: {ClaimID: 2, AnonArgumentsViews: 4},
+	}, stats.views)
+	assert.Equal(t, map[uint64]db.ClaimRepliesStats{
+		1: {ClaimID: 1, Replies: 3},
+	}, stats.replies)
+
+	// nothing happened before the first day
+	stats = claimStatsBefore(views, replies, day(10))
+	assert.Empty(t, stats.views)
+	assert.Empty(t, stats.replies)
+}
+
+type fakeUserBaseStore struct {
+	db.Datastore
+	users []db.User
+}
+
+func (s *fakeUserBaseStore) UsersForExport(includeDeleted bool, afterID int64, limit int) ([]db.User, error) {
+	users := make([]db.User, 0)
+	for _, user := range s.users {
+		if user.ID > afterID && len(users) < limit {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func TestUserBaseCompression(t *testing.T) {
+	created := time.Date(2019, 9, 12, 0, 0, 0, 0, time.UTC)
+	store := &fakeUserBaseStore{users: []db.User{
+		{ID: 1, Address: "cosmos1alice", Username: "alice", Email: "alice@example.com", Timestamps: db.Timestamps{CreatedAt: created, UpdatedAt: created}},
+		{ID: 2, Address: "cosmos1bob", Username: "=bob", Timestamps: db.Timestamps{CreatedAt: created, UpdatedAt: created}},
+		// signed up but without an address yet
+		{ID: 3, Username: "carol", Timestamps: db.Timestamps{CreatedAt: created, UpdatedAt: created}},
+		{ID: 4, Address: "cosmos1dave", Username: "dave", Timestamps: db.Timestamps{CreatedAt: created, UpdatedAt: created}},
+	}}
+	ta := &TruAPI{DBClient: store, APIContext: truCtx.TruAPIContext{Config: truCtx.Config{Metrics: truCtx.MetricsConfig{Secret: "secret"}}}}
+	header := "address,username,email,creation_date,updated_date,last_login,user_group\n"
+	alice := "cosmos1alice,alice,alice@example.com,2019-09-12T00:00:00Z,2019-09-12T00:00:00Z,,User\n"
+	bob := "cosmos1bob,'=bob,,2019-09-12T00:00:00Z,2019-09-12T00:00:00Z,,User\n"
+	dave := "cosmos1dave,dave,,2019-09-12T00:00:00Z,2019-09-12T00:00:00Z,,User\n"
+	expected := header + alice + bob + dave
+	get := func(acceptEncoding string) *httptest.ResponseRecorder {
+		return getUserBase(ta, "/metrics/user_base", acceptEncoding)
+	}
+
+	res := get("deflate, gzip;q=0.8")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "gzip", res.Header().Get("Content-Encoding"))
+	gz, err := gzip.NewReader(res.Body)
+	assert.NoError(t, err)
+	body, err := ioutil.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(body))
+
+	res = get("gzip;q=0")
+	assert.Empty(t, res.Header().Get("Content-Encoding"))
+	assert.Equal(t, expected, res.Body.String())
+
+	// pages continue from the cursor of the previous one, which counts the skipped users
+	res = getUserBase(ta, "/metrics/user_base?limit=3", "")
+	assert.Equal(t, header+alice+bob, res.Body.String())
+	assert.Equal(t, "3", res.Header().Get("Next-After-Id"))
+	res = getUserBase(ta, "/metrics/user_base?limit=3&after_id=3", "")
+	assert.Equal(t, header+dave, res.Body.String())
+	assert.Equal(t, "4", res.Header().Get("Next-After-Id"))
+	res = getUserBase(ta, "/metrics/user_base?limit=3&after_id=4", "")
+	assert.Equal(t, header, res.Body.String())
+	assert.Empty(t, res.Header().Get("Next-After-Id"))
+}
+
+func getUserBase(ta *TruAPI, url, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Metrics-Secret", "secret")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	res := httptest.NewRecorder()
+	WrapStreamHandler(ta.HandleUserBase).ServeHTTP(res, req)
+	return res
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	assert.True(t, acceptsGzip("gzip"))
+	assert.True(t, acceptsGzip("deflate, GZIP ;q=0.5"))
+	assert.False(t, acceptsGzip(""))
+	assert.False(t, acceptsGzip("deflate, br"))
+	assert.False(t, acceptsGzip("gzip;q=0"))
+}
@@ -0,0 +1,69 @@
+package truapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/TruStory/octopus/services/truapi/truapi/cookies"
+	"github.com/TruStory/octopus/services/truapi/truapi/render"
+)
+
+// DeleteOwnAccountRequest represents the request to delete the authenticated user's account
+type DeleteOwnAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// HandleDeleteOwnAccount deletes the account of the authenticated user after re-confirming their password
+func (ta *TruAPI) HandleDeleteOwnAccount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		render.Error(w, r, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authenticatedUser, err := cookies.GetAuthenticatedUser(ta.APIContext, r)
+	if err != nil {
+		render.Error(w, r, Err401NotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var request DeleteOwnAccountRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := ta.DBClient.UserByID(authenticatedUser.ID)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if user == nil || user.DeletedAt != nil {
+		render.LoginError(w, r, ErrUserNotFound, http.StatusBadRequest)
+		return
+	}
+
+	_, err = ta.DBClient.GetAuthenticatedUser(user.Username, request.Password)
+	if err != nil {
+		render.LoginError(w, r, ErrInvalidPassword, http.StatusUnauthorized)
+		return
+	}
+
+	err = ta.DBClient.DeleteAccount(user.ID, ta.APIContext.Config.Defaults.AvatarURL)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, cookies.GetLogoutCookie(ta.APIContext))
+
+	if user.Email != "" {
+		err = ta.Dripper.ToWorkflow("account-deleted").Subscribe(user.Email)
+		if err != nil {
+			fmt.Println("could not send account deleted email: ", user.ID, err)
+		}
+	}
+
+	render.Response(w, r, true, http.StatusOK)
+}
@@ -0,0 +1,90 @@
+package truapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/TruStory/octopus/services/truapi/chttp"
+	"github.com/TruStory/octopus/services/truapi/db"
+	"github.com/TruStory/octopus/services/truapi/truapi/cookies"
+)
+
+const defaultMaxFeaturedClaims = 3
+
+// FeaturedClaimRequest represents the JSON request for featuring a claim in a community
+type FeaturedClaimRequest struct {
+	ClaimID     int64     `json:"claim_id"`
+	CommunityID string    `json:"community_id"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+}
+
+// HandleFeaturedClaim handles requests for featured claims
+func (ta *TruAPI) HandleFeaturedClaim(r *http.Request) chttp.Response {
+	switch r.Method {
+	case http.MethodPost:
+		return ta.handleSetFeaturedClaim(r, ta.claimCommunity, ta.isClaimAdmin)
+	default:
+		return chttp.SimpleErrorResponse(404, Err404ResourceNotFound)
+	}
+}
+
+func (ta *TruAPI) handleSetFeaturedClaim(r *http.Request, communityOf claimCommunityLookup, isAdmin adminCheck) chttp.Response {
+	request := &FeaturedClaimRequest{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		return chttp.SimpleErrorResponse(400, err)
+	}
+
+	user, ok := r.Context().Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil {
+		return chttp.SimpleErrorResponse(401, Err401NotAuthenticated)
+	}
+	if !isAdmin(r.Context(), user.Address) {
+		return chttp.SimpleErrorResponse(403, Err403NotAuthorized)
+	}
+
+	if request.ClaimID == 0 || request.CommunityID == "" {
+		return chttp.SimpleErrorResponse(400, errors.New("claim id and community id are required"))
+	}
+	communityID, ok := communityOf(r.Context(), request.ClaimID)
+	if !ok {
+		return chttp.SimpleErrorResponse(400, errors.New("invalid claim"))
+	}
+	if communityID != request.CommunityID {
+		return chttp.SimpleErrorResponse(400, errors.New("the claim doesn't belong to the community"))
+	}
+	if request.StartsAt.IsZero() {
+		request.StartsAt = time.Now()
+	}
+	if !request.EndsAt.After(request.StartsAt) {
+		return chttp.SimpleErrorResponse(400, errors.New("ends at must be after starts at"))
+	}
+
+	maxFeaturedClaims := ta.APIContext.Config.Community.MaxFeaturedClaims
+	if maxFeaturedClaims == 0 {
+		maxFeaturedClaims = defaultMaxFeaturedClaims
+	}
+	featuredClaim := &db.FeaturedClaim{
+		ClaimID:     request.ClaimID,
+		CommunityID: request.CommunityID,
+		StartsAt:    request.StartsAt,
+		EndsAt:      request.EndsAt,
+		SetBy:       user.Address,
+	}
+	err = ta.DBClient.SetFeaturedClaim(featuredClaim, maxFeaturedClaims)
+	if err == db.ErrTooManyFeaturedClaims {
+		return chttp.SimpleErrorResponse(400, err)
+	}
+	if err != nil {
+		return chttp.SimpleErrorResponse(500, err)
+	}
+	respBytes, err := json.Marshal(featuredClaim)
+	if err != nil {
+		return chttp.SimpleErrorResponse(500, err)
+	}
+
+	return chttp.SimpleResponse(200, respBytes)
+}
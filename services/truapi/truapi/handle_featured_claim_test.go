@@ -0,0 +1,42 @@
+package truapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+type fakeFeaturedClaimStore struct {
+	db.Datastore
+	featured []*db.FeaturedClaim
+}
+
+func (s *fakeFeaturedClaimStore) SetFeaturedClaim(featuredClaim *db.FeaturedClaim, maxPerCommunity int) error {
+	s.featured = append(s.featured, featuredClaim)
+	return nil
+}
+
+func TestSetFeaturedClaimChecksCommunity(t *testing.T) {
+	store := &fakeFeaturedClaimStore{}
+	ta := &TruAPI{DBClient: store}
+	communityOf := func(ctx context.Context, claimID int64) (string, bool) { return "crypto", claimID == 7 }
+	admins := func(ctx context.Context, address string) bool { return address == "admin" }
+	set := func(address, body string) int {
+		return ta.handleSetFeaturedClaim(commentRequest(http.MethodPost, address, body), communityOf, admins).HTTPCode()
+	}
+	period := `"starts_at": "2019-09-12T00:00:00Z", "ends_at": "2019-09-13T00:00:00Z"`
+
+	assert.Equal(t, http.StatusForbidden, set("author", `{"claim_id": 7, "community_id": "crypto", `+period+`}`))
+	assert.Equal(t, http.StatusBadRequest, set("admin", `{"claim_id": 8, "community_id": "crypto", `+period+`}`))
+	// the claim is in another community
+	assert.Equal(t, http.StatusBadRequest, set("admin", `{"claim_id": 7, "community_id": "sports", `+period+`}`))
+	assert.Empty(t, store.featured)
+
+	assert.Equal(t, http.StatusOK, set("admin", `{"claim_id": 7, "community_id": "crypto", `+period+`}`))
+	assert.Len(t, store.featured, 1)
+	assert.Equal(t, "admin", store.featured[0].SetBy)
+}
@@ -1,14 +1,15 @@
 package truapi
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 
+	"github.com/TruStory/octopus/services/truapi/chttp"
 	"github.com/TruStory/octopus/services/truapi/db"
-	app "github.com/TruStory/truchain/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
 
 	"github.com/TruStory/octopus/services/truapi/truapi/render"
 )
@@ -46,18 +47,13 @@ func (ta *TruAPI) HandleGift(w http.ResponseWriter, r *http.Request) {
 		render.Error(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if amount.Denom != app.StakeDenom {
-		err = fmt.Errorf("invalid denomination coin got %s wanted %s", amount.Denom, app.StakeDenom)
+	err = chttp.ValidateGift(amount)
+	if err != nil {
 		render.Error(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	broker, err := ta.accountQuery(r.Context(), ta.APIContext.Config.RewardBroker.Addr)
-	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	err = ta.SendGiftToAddress(user.Address, amount, broker.GetAccountNumber(), broker.GetSequence(), request.Memo)
+	err = sendGift(r.Context(), ta, ta.APIContext.Config.RewardBroker.Addr, user.Address, amount, request.Memo)
 	if err != nil {
 		render.Error(w, r, err.Error(), http.StatusBadRequest)
 		return
@@ -70,3 +66,24 @@ func (ta *TruAPI) HandleGift(w http.ResponseWriter, r *http.Request) {
 
 	render.Response(w, r, true, http.StatusOK)
 }
+
+// giftSender sends gifts from the reward broker account
+type giftSender interface {
+	accountQuery(ctx context.Context, addrStr string) (authexported.Account, error)
+	SendGiftToAddress(address string, amount sdk.Coin, brokerAccountNumber, brokerSequence uint64, memo string) error
+}
+
+// sendGift signs the gift with the broker account and sequence numbers queried from the chain,
+// backing off and querying them again when a concurrent transaction made the sequence stale
+func sendGift(ctx context.Context, sender giftSender, brokerAddr, address string, amount sdk.Coin, memo string) error {
+	broker := chttp.NewBrokerSequence(func(ctx context.Context) (uint64, uint64, error) {
+		account, err := sender.accountQuery(ctx, brokerAddr)
+		if err != nil {
+			return 0, 0, err
+		}
+		return account.GetAccountNumber(), account.GetSequence(), nil
+	})
+	return broker.Broadcast(ctx, func(accountNumber, sequence uint64) error {
+		return sender.SendGiftToAddress(address, amount, accountNumber, sequence, memo)
+	})
+}
@@ -0,0 +1,68 @@
+package truapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/chttp"
+)
+
+type fakeGiftSender struct {
+	sequences  []uint64 // broker sequence returned by each account query
+	queries    int
+	stale      int // number of sends rejected with a stale sequence
+	sentNumber uint64
+	sent       []uint64
+}
+
+func (f *fakeGiftSender) accountQuery(_ context.Context, _ string) (authexported.Account, error) {
+	sequence := f.sequences[f.queries]
+	f.queries++
+	return &auth.BaseAccount{AccountNumber: 7, Sequence: sequence}, nil
+}
+
+func (f *fakeGiftSender) SendGiftToAddress(_ string, _ sdk.Coin, accountNumber, sequence uint64, _ string) error {
+	f.sentNumber = accountNumber
+	f.sent = append(f.sent, sequence)
+	if len(f.sent) <= f.stale {
+		return chttp.ErrSequenceMismatch
+	}
+	return nil
+}
+
+func TestSendGiftUsesQueriedSequence(t *testing.T) {
+	amount := sdk.NewInt64Coin("utru", 1000)
+
+	sender := &fakeGiftSender{sequences: []uint64{42}}
+	assert.NoError(t, sendGift(context.Background(), sender, "broker", "user", amount, ""))
+	assert.Equal(t, uint64(7), sender.sentNumber)
+	assert.Equal(t, []uint64{42}, sender.sent)
+
+	// a stale sequence is queried again, after a backoff, before retrying
+	sender = &fakeGiftSender{sequences: []uint64{42, 43}, stale: 1}
+	start := time.Now()
+	assert.NoError(t, sendGift(context.Background(), sender, "broker", "user", amount, ""))
+	assert.Equal(t, []uint64{42, 43}, sender.sent)
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+
+	// gives up after a few attempts
+	sender = &fakeGiftSender{sequences: []uint64{42, 42, 42}, stale: 3}
+	err := sendGift(context.Background(), sender, "broker", "user", amount, "")
+	assert.True(t, errors.Is(err, chttp.ErrSequenceMismatch))
+	assert.Len(t, sender.sent, 3)
+
+	// stops waiting for a retry once the request is gone
+	sender = &fakeGiftSender{sequences: []uint64{42, 43}, stale: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = sendGift(ctx, sender, "broker", "user", amount, "")
+	assert.Equal(t, context.Canceled, err)
+	assert.Len(t, sender.sent, 1)
+}
@@ -0,0 +1,97 @@
+package truapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	healthCheckTimeout = 5 * time.Second
+
+	healthStatusOK          = "ok"
+	healthStatusUnavailable = "unavailable"
+)
+
+// HealthResponse reports whether the dependencies of the API can be reached, and which ones failed
+type HealthResponse struct {
+	Status string   `json:"status"`
+	Failed []string `json:"failed,omitempty"`
+}
+
+// healthCheck checks that a dependency of the API can be reached
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (ta *TruAPI) healthChecks() []healthCheck {
+	return []healthCheck{
+		{name: "database", check: ta.DBClient.Ping},
+		{name: "chain", check: ta.pingChain},
+	}
+}
+
+// pingChain asks the Tendermint node for its status
+func (ta *TruAPI) pingChain(ctx context.Context) error {
+	node, err := ta.APIContext.GetNode()
+	if err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := node.Status()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HandleHealth responds with a 200 when the database and the chain can be reached,
+// and with a 503 naming the failing dependencies otherwise. Their errors are only logged.
+func (ta *TruAPI) HandleHealth(w http.ResponseWriter, r *http.Request) {
+	serveHealth(w, r, ta.healthChecks())
+}
+
+func serveHealth(w http.ResponseWriter, r *http.Request, checks []healthCheck) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	response := HealthResponse{Status: healthStatusOK}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c healthCheck) {
+			defer wg.Done()
+			err := c.check(ctx)
+			if err == nil {
+				return
+			}
+			fmt.Printf("health check %s err: %s\n", c.name, err)
+			mu.Lock()
+			defer mu.Unlock()
+			response.Status = healthStatusUnavailable
+			response.Failed = append(response.Failed, c.name)
+		}(c)
+	}
+	wg.Wait()
+	sort.Strings(response.Failed)
+
+	statusCode := http.StatusOK
+	if response.Status != healthStatusOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,49 @@
+package truapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+type stubDBClient struct {
+	db.Datastore
+	pingErr error
+}
+
+func (c stubDBClient) Ping(ctx context.Context) error {
+	return c.pingErr
+}
+
+func TestHandleHealth(t *testing.T) {
+	chainUp := func(ctx context.Context) error { return nil }
+	chainDown := func(ctx context.Context) error { return errors.New("node is syncing") }
+	health := func(dbClient db.Datastore, chain func(ctx context.Context) error) (int, string) {
+		w := httptest.NewRecorder()
+		serveHealth(w, httptest.NewRequest(http.MethodGet, "/health", nil), []healthCheck{
+			{name: "database", check: dbClient.Ping},
+			{name: "chain", check: chain},
+		})
+		return w.Code, w.Body.String()
+	}
+
+	code, body := health(stubDBClient{}, chainUp)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"status": "ok"}`, body)
+
+	dbDown := stubDBClient{pingErr: errors.New("dial tcp: connection refused")}
+	code, body = health(dbDown, chainUp)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	// the failing dependency is named, its error is only logged
+	assert.JSONEq(t, `{"status": "unavailable", "failed": ["database"]}`, body)
+
+	code, body = health(dbDown, chainDown)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.JSONEq(t, `{"status": "unavailable", "failed": ["chain", "database"]}`, body)
+}
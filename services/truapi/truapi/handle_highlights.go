@@ -96,10 +96,7 @@ func renderAndCacheHighlight(ta *TruAPI, highlight *db.Highlight) {
 }
 
 func renderHighlight(ta *TruAPI, highlight *db.Highlight) (io.Reader, error) {
-	// firing up the http client
-	client := &http.Client{
-		Timeout: time.Second * 10,
-	}
+	client := ta.httpClient
 
 	spotlightURL := fmt.Sprintf("%s/highlight/%d/spotlight", ta.APIContext.Config.Spotlight.URL, highlight.ID)
 	request, err := http.NewRequest("GET", spotlightURL, nil)
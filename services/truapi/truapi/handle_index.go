@@ -302,15 +302,21 @@ func makeClaimMetaTags(ta *TruAPI, route string, claimID uint64) (*Tags, error)
 func makeClaimArgumentMetaTags(ta *TruAPI, route string, claimID uint64, argumentID uint64) (*Tags, error) {
 	ctx := ta.createContext(context.Background())
 	argumentObj := ta.claimArgumentResolver(ctx, queryByArgumentID{ID: argumentID})
+	if argumentObj == nil || argumentObj.ClaimID != claimID {
+		return nil, errors.New("argument not found")
+	}
 	creatorObj, err := ta.DBClient.UserByAddress(argumentObj.Creator.String())
-	if creatorObj == nil || err != nil {
+	if err != nil {
 		// if error, return default
 		return nil, err
 	}
+	if creatorObj == nil {
+		return nil, errors.New("argument creator not found")
+	}
 	return &Tags{
 		Title:       fmt.Sprintf("%s made an argument", "@"+creatorObj.Username),
 		Description: html.EscapeString(stripmd.Strip(argumentObj.Summary)),
-		Image:       fmt.Sprintf("%s/api/v1/spotlight?argument_id=%v", ta.APIContext.Config.App.URL, argumentID),
+		Image:       fmt.Sprintf("%s/api/v1/spotlight?claim_id=%v&argument_id=%v", ta.APIContext.Config.App.URL, claimID, argumentID),
 		URL:         joinPath(ta.APIContext.Config.App.URL, route),
 	}, nil
 }
@@ -12,13 +12,17 @@ type PingRequest struct{}
 
 // PingResponse is a JSON response body representing the result of Ping
 type PingResponse struct {
-	Pong bool `json:"pong"`
+	Pong               bool               `json:"pong"`
+	InFlightQueries    int64              `json:"in_flight_queries"`
+	NotificationsDepth NotificationsDepth `json:"notifications_depth"`
 }
 
 // HandlePing takes a `PingRequest` and returns a `PingResponse`
 func (ta *TruAPI) HandlePing(r *http.Request) chttp.Response {
 	responseBytes, _ := json.Marshal(PingResponse{
-		Pong: true,
+		Pong:               true,
+		InFlightQueries:    ta.InFlightQueries(),
+		NotificationsDepth: ta.notificationsDepth(),
 	})
 
 	return chttp.SimpleResponse(200, responseBytes)
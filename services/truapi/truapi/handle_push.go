@@ -5,7 +5,6 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/TruStory/octopus/services/truapi/truapi/render"
 )
@@ -13,10 +12,7 @@ import (
 // HandlePush proxies the request from the clients to the push service
 func (ta *TruAPI) HandlePush(res http.ResponseWriter, req *http.Request) {
 
-	// firing up the http client
-	client := &http.Client{
-		Timeout: time.Second * 10,
-	}
+	client := ta.httpClient
 
 	// preparing the request
 	request, err := http.NewRequest(http.MethodPost, ta.APIContext.Config.Push.EndpointURL+parsePath(req.URL.Path), req.Body)
@@ -49,6 +49,10 @@ func (ta *TruAPI) createReaction(r *http.Request) chttp.Response {
 		Type: request.ReactionableType,
 		ID:   request.ReactionableID,
 	}
+	existing, err := ta.DBClient.ReactionByAddressAndReactionable(user.Address, request.ReactionType, rxnable)
+	if err != nil {
+		return chttp.SimpleErrorResponse(500, err)
+	}
 	err = ta.DBClient.ReactOnReactionable(
 		user.Address,
 		request.ReactionType,
@@ -58,6 +62,20 @@ func (ta *TruAPI) createReaction(r *http.Request) chttp.Response {
 		return chttp.SimpleErrorResponse(500, err)
 	}
 
+	// only notify about new reactions on arguments
+	if existing == nil && rxnable.Type == db.Argument {
+		rxn, err := ta.DBClient.ReactionByAddressAndReactionable(user.Address, request.ReactionType, rxnable)
+		if err == nil && rxn != nil {
+			ta.sendReactionNotification(ReactionNotificationRequest{
+				ID:               rxn.ID,
+				ReactionType:     rxn.ReactionType,
+				ReactionableType: rxn.ReactionableType,
+				ReactionableID:   rxn.ReactionableID,
+				Creator:          rxn.Creator,
+			})
+		}
+	}
+
 	return chttp.SimpleResponse(200, nil)
 }
 
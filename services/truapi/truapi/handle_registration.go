@@ -124,7 +124,7 @@ func CalibrateUser(ta *TruAPI, twitterUser *twitter.User, referrerCode string) (
 	}
 
 	// we'll make a local copy of their avatar photo to remove the dependency on twitter
-	avatarURL, err := cacheAvatarLocally(ta.APIContext, twitterUser.ProfileImageURL)
+	avatarURL, err := cacheAvatarLocally(ta.APIContext, ta.httpClient, twitterUser.ProfileImageURL)
 	if err != nil {
 		return nil, false, err
 	}
@@ -262,13 +262,9 @@ func getTwitterUser(apiCtx truCtx.TruAPIContext, authToken string, authTokenSecr
 	return user, nil
 }
 
-func cacheAvatarLocally(apiCtx truCtx.TruAPIContext, avatarURL string) (string, error) {
+func cacheAvatarLocally(apiCtx truCtx.TruAPIContext, httpClient *http.Client, avatarURL string) (string, error) {
 	avatarURL = strings.Replace(avatarURL, "_normal", "_400x400", 1)
 
-	httpClient := &http.Client{
-		Timeout: time.Second * 10,
-	}
-
 	avatarResponse, err := httpClient.Get(avatarURL)
 	if err != nil {
 		return "", nil
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/TruStory/octopus/services/truapi/postman/messages"
 	"github.com/TruStory/octopus/services/truapi/truapi/render"
@@ -14,9 +15,18 @@ type ResendEmailVerificationRequest struct {
 	Identifier string `json:"identifier"`
 }
 
+const (
+	defaultVerificationCooldown    = 5 * time.Minute
+	defaultMaxVerificationAttempts = 10
+	// defaultVerificationAttemptWindow matches the verification campaign's default period (in days),
+	// as resends and campaign emails share the same attempt count
+	defaultVerificationAttemptWindow = 7
+)
+
 // TruErrors for resend email verification
 var (
-	ErrUserAlreadyVerified = render.TruError{Code: 200, Message: "User is already verified."}
+	ErrUserAlreadyVerified         = render.TruError{Code: 200, Message: "User is already verified."}
+	ErrTooManyVerificationAttempts = render.TruError{Code: 201, Message: "Verification email was sent recently, please try again later."}
 )
 
 // HandleResendEmailVerification resends the email verification email
@@ -46,6 +56,30 @@ func (ta *TruAPI) HandleResendEmailVerification(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	config := ta.APIContext.Config.VerificationRateLimit
+	cooldown := time.Duration(config.Cooldown) * time.Minute
+	if cooldown == 0 {
+		cooldown = defaultVerificationCooldown
+	}
+	maxAttempts := config.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultMaxVerificationAttempts
+	}
+	windowDays := config.Window
+	if windowDays == 0 {
+		windowDays = defaultVerificationAttemptWindow
+	}
+
+	canAttempt, err := ta.DBClient.CanAttemptVerification(user.ID, cooldown, time.Duration(windowDays)*24*time.Hour, maxAttempts)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !canAttempt {
+		render.LoginError(w, r, ErrTooManyVerificationAttempts, http.StatusTooManyRequests)
+		return
+	}
+
 	message, err := messages.MakeEmailConfirmationMessage(ta.Postman, ta.APIContext.Config, *user)
 	if err != nil {
 		fmt.Println("could not remake verification email: ", user, err)
@@ -59,4 +93,9 @@ func (ta *TruAPI) HandleResendEmailVerification(w http.ResponseWriter, r *http.R
 		render.Error(w, r, "cannot send email confirmation right now", http.StatusInternalServerError)
 		return
 	}
+
+	err = ta.DBClient.RecordVerificationAttempt(user.ID, time.Now().AddDate(0, 0, -windowDays))
+	if err != nil {
+		fmt.Println("could not record verification attempt: ", user.ID, err)
+	}
 }
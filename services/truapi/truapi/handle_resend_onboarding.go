@@ -0,0 +1,106 @@
+package truapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+	"github.com/TruStory/octopus/services/truapi/truapi/cookies"
+	"github.com/TruStory/octopus/services/truapi/truapi/render"
+)
+
+const defaultOnboardingResendCooldown = 24 // hours
+
+// onboardingWorkflows are the drip workflows emailing users about each step of their journey.
+// The sign up one is the workflow users are subscribed to on sign up.
+var onboardingWorkflows = map[db.UserJourneyStep]string{
+	db.JourneyStepSignedUp:          "onboarding",
+	db.JourneyStepOneArgument:       "onboarding-one-argument",
+	db.JourneyStepGivenOneAgree:     "onboarding-given-one-agree",
+	db.JourneyStepReceiveFiveAgrees: "onboarding-received-five-agrees",
+}
+
+// onboardingJourney is the ordered list of journey steps a user goes through while onboarding
+var onboardingJourney = []db.UserJourneyStep{
+	db.JourneyStepSignedUp,
+	db.JourneyStepOneArgument,
+	db.JourneyStepGivenOneAgree,
+	db.JourneyStepReceiveFiveAgrees,
+}
+
+// ResendOnboardingResponse represents the response after resending an onboarding email
+type ResendOnboardingResponse struct {
+	Step db.UserJourneyStep `json:"step"`
+}
+
+// HandleResendOnboarding resends the onboarding email of the next incomplete journey step
+func (ta *TruAPI) HandleResendOnboarding(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		render.Error(w, r, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authenticatedUser, err := cookies.GetAuthenticatedUser(ta.APIContext, r)
+	if err != nil {
+		render.Error(w, r, Err401NotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	user, err := ta.DBClient.UserByID(authenticatedUser.ID)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if user == nil {
+		render.LoginError(w, r, ErrUserNotFound, http.StatusBadRequest)
+		return
+	}
+
+	cooldown := ta.APIContext.Config.Dripper.ResendCooldown
+	if cooldown == 0 {
+		cooldown = defaultOnboardingResendCooldown
+	}
+	resentAt := user.Meta.OnboardingResentAt
+	if resentAt != nil && time.Since(*resentAt) < time.Duration(cooldown)*time.Hour {
+		render.Error(w, r, "onboarding email was resent recently, please try again later", http.StatusTooManyRequests)
+		return
+	}
+
+	step, ok := nextOnboardingStep(user.Meta.Journey)
+	if !ok {
+		render.Error(w, r, "journey is already complete", http.StatusBadRequest)
+		return
+	}
+
+	// a step whose workflow isn't configured fails here, so a step is only returned once it's emailed
+	err = ta.Dripper.ToWorkflow(onboardingWorkflows[step]).Subscribe(user.Email)
+	if err != nil {
+		fmt.Println("could not resend onboarding email: ", user.ID, step, err)
+		render.Error(w, r, "cannot resend onboarding email right now", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	err = ta.DBClient.SetUserMeta(user.ID, &db.UserMeta{OnboardingResentAt: &now})
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	render.Response(w, r, ResendOnboardingResponse{Step: step}, http.StatusOK)
+}
+
+// nextOnboardingStep returns the first journey step that has not been completed yet
+func nextOnboardingStep(journey []db.UserJourneyStep) (db.UserJourneyStep, bool) {
+	completed := make(map[db.UserJourneyStep]bool)
+	for _, step := range journey {
+		completed[step] = true
+	}
+	for _, step := range onboardingJourney {
+		if !completed[step] {
+			return step, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,154 @@
+package truapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+	"github.com/TruStory/octopus/services/truapi/db"
+	"github.com/TruStory/octopus/services/truapi/dripper"
+	"github.com/TruStory/octopus/services/truapi/truapi/cookies"
+)
+
+// fakeOnboardingStore keeps a single user in memory
+type fakeOnboardingStore struct {
+	db.Datastore
+	user *db.User
+}
+
+func (s *fakeOnboardingStore) UserByID(id int64) (*db.User, error) {
+	if s.user.ID != id {
+		return nil, nil
+	}
+	return s.user, nil
+}
+
+func (s *fakeOnboardingStore) SetUserMeta(id int64, meta *db.UserMeta) error {
+	if meta.OnboardingResentAt != nil {
+		s.user.Meta.OnboardingResentAt = meta.OnboardingResentAt
+	}
+	return nil
+}
+
+// fakeMailchimp answers the Mailchimp calls of a workflow subscription, recording the queued emails
+func fakeMailchimp(queued *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/automations/"):
+			_, _ = w.Write([]byte(`{"id":"workflow","recipients":{"list_id":"list"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/lists/list/members":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/queue"):
+			*queued = append(*queued, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status":404,"detail":"not found"}`))
+		}
+	}))
+}
+
+func newOnboardingTestAPI(user *db.User, endpoint string) *TruAPI {
+	config := truCtx.Config{
+		Cookie: truCtx.CookieConfig{
+			HashKey:    strings.Repeat("ab", 32),
+			EncryptKey: strings.Repeat("cd", 32),
+		},
+		Dripper: truCtx.DripperConfig{
+			Workflows: []truCtx.DripperWorkflowConfig{
+				{Name: "onboarding", WorkflowID: "workflow", EmailID: "email"},
+				{Name: "onboarding-one-argument", WorkflowID: "one-argument", EmailID: "email"},
+			},
+		},
+	}
+	drip := &dripper.Dripper{
+		Endpoint:         endpoint,
+		WorkflowRegistry: make(map[string]*dripper.Workflow),
+		HTTPClient:       http.DefaultClient,
+	}
+	for _, workflow := range config.Dripper.Workflows {
+		drip.AddWorkflowToRegistry(workflow.Name, workflow.WorkflowID, workflow.EmailID, workflow.Tags)
+	}
+	return &TruAPI{
+		APIContext: truCtx.TruAPIContext{Config: config},
+		DBClient:   &fakeOnboardingStore{user: user},
+		Dripper:    drip,
+	}
+}
+
+func resendOnboardingRequest(t *testing.T, ta *TruAPI, user *db.User) *http.Request {
+	value, err := cookies.MakeLoginCookieValue(ta.APIContext, user)
+	assert.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/onboarding/resend", nil)
+	r.AddCookie(&http.Cookie{Name: cookies.UserCookieName, Value: value})
+	return r
+}
+
+func TestHandleResendOnboarding(t *testing.T) {
+	queued := make([]string, 0)
+	mailchimp := fakeMailchimp(&queued)
+	defer mailchimp.Close()
+
+	user := &db.User{ID: 1, Email: "user@trustory.io"}
+	user.Meta.Journey = []db.UserJourneyStep{db.JourneyStepSignedUp}
+	ta := newOnboardingTestAPI(user, mailchimp.URL)
+
+	w := httptest.NewRecorder()
+	ta.HandleResendOnboarding(w, resendOnboardingRequest(t, ta, user))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), string(db.JourneyStepOneArgument))
+	assert.Equal(t, []string{"/automations/one-argument/emails/email/queue"}, queued)
+	assert.NotNil(t, user.Meta.OnboardingResentAt)
+
+	// a second resend within the cooldown is rejected without emailing
+	w = httptest.NewRecorder()
+	ta.HandleResendOnboarding(w, resendOnboardingRequest(t, ta, user))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Len(t, queued, 1)
+
+	// the cooldown is over
+	resentAt := time.Now().Add(-25 * time.Hour)
+	user.Meta.OnboardingResentAt = &resentAt
+	w = httptest.NewRecorder()
+	ta.HandleResendOnboarding(w, resendOnboardingRequest(t, ta, user))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, queued, 2)
+}
+
+func TestHandleResendOnboardingCompletedJourney(t *testing.T) {
+	queued := make([]string, 0)
+	mailchimp := fakeMailchimp(&queued)
+	defer mailchimp.Close()
+
+	user := &db.User{ID: 1, Email: "user@trustory.io"}
+	user.Meta.Journey = onboardingJourney
+	ta := newOnboardingTestAPI(user, mailchimp.URL)
+
+	w := httptest.NewRecorder()
+	ta.HandleResendOnboarding(w, resendOnboardingRequest(t, ta, user))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, queued)
+}
+
+func TestHandleResendOnboardingUnconfiguredStep(t *testing.T) {
+	queued := make([]string, 0)
+	mailchimp := fakeMailchimp(&queued)
+	defer mailchimp.Close()
+
+	user := &db.User{ID: 1, Email: "user@trustory.io"}
+	user.Meta.Journey = []db.UserJourneyStep{db.JourneyStepSignedUp, db.JourneyStepOneArgument}
+	ta := newOnboardingTestAPI(user, mailchimp.URL)
+
+	// no workflow emails about the given_one_agree step, so nothing is reported as sent
+	w := httptest.NewRecorder()
+	ta.HandleResendOnboarding(w, resendOnboardingRequest(t, ta, user))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), string(db.JourneyStepGivenOneAgree))
+	assert.Empty(t, queued)
+	assert.Nil(t, user.Meta.OnboardingResentAt)
+}
@@ -0,0 +1,24 @@
+package truapi
+
+import (
+	"net/http"
+
+	"github.com/TruStory/octopus/services/truapi/truapi/render"
+)
+
+// HandleRewardSchedule returns the configured schedule of journey rewards, failing when it's incomplete
+func (ta *TruAPI) HandleRewardSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		render.Error(w, r, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	schedule := NewRewardSchedule(ta.APIContext.Config.Rewards)
+	err := schedule.Validate()
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	render.Response(w, r, schedule, http.StatusOK)
+}
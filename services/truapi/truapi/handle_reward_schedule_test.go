@@ -0,0 +1,55 @@
+package truapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+func TestHandleRewardSchedule(t *testing.T) {
+	config := truCtx.RewardsConfig{
+		InviteBatchSize: 3,
+		StepSignUp:      "5000000000tru",
+		StepOneArgument: "1000000000tru",
+		StepFiveAgrees:  "2000000000tru",
+		AgreesRequired:  4,
+	}
+	ta := &TruAPI{APIContext: truCtx.TruAPIContext{Config: truCtx.Config{Rewards: config}}}
+
+	w := httptest.NewRecorder()
+	ta.HandleRewardSchedule(w, httptest.NewRequest(http.MethodGet, "/api/v1/rewards/schedule", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data RewardSchedule `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	// the pipeline gets exactly the schedule the API previews from
+	assert.Equal(t, NewRewardSchedule(config), response.Data)
+	reward, ok := response.Data.RewardForStep(db.JourneyStepSignedUp)
+	assert.True(t, ok)
+	assert.Equal(t, "5000000000tru", reward)
+
+	w = httptest.NewRecorder()
+	ta.HandleRewardSchedule(w, httptest.NewRequest(http.MethodPost, "/api/v1/rewards/schedule", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestHandleRewardScheduleRejectsMissingConfig(t *testing.T) {
+	for _, config := range []truCtx.RewardsConfig{
+		{},
+		{StepSignUp: "1tru", StepOneArgument: "1tru", StepFiveAgrees: "1tru"},
+		{InviteBatchSize: 3, StepSignUp: "1tru", StepFiveAgrees: "1tru"},
+	} {
+		ta := &TruAPI{APIContext: truCtx.TruAPIContext{Config: truCtx.Config{Rewards: config}}}
+		w := httptest.NewRecorder()
+		ta.HandleRewardSchedule(w, httptest.NewRequest(http.MethodGet, "/api/v1/rewards/schedule", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	}
+}
@@ -4,17 +4,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"time"
+	"net/url"
 
 	"github.com/TruStory/octopus/services/truapi/truapi/render"
 )
 
 // HandleSpotlight proxies the request from the clients to the spotlight service
 func (ta *TruAPI) HandleSpotlight(res http.ResponseWriter, req *http.Request) {
-	// firing up the http client
-	client := &http.Client{
-		Timeout: time.Second * 10,
-	}
+	client := ta.httpClient
 
 	err := req.ParseForm()
 	if err != nil {
@@ -34,6 +31,8 @@ func (ta *TruAPI) HandleSpotlight(res http.ResponseWriter, req *http.Request) {
 	spotlightURL := ""
 	if commentID != "" {
 		spotlightURL = fmt.Sprintf("%s/comment/%s/spotlight", ta.APIContext.Config.Spotlight.URL, commentID)
+	} else if claimID != "" && argumentID != "" {
+		spotlightURL = fmt.Sprintf("%s/argument/%s/%s/spotlight", ta.APIContext.Config.Spotlight.URL, claimID, argumentID)
 	} else if claimID != "" {
 		spotlightURL = fmt.Sprintf("%s/claim/%s/spotlight", ta.APIContext.Config.Spotlight.URL, claimID)
 	} else if argumentID != "" {
@@ -41,12 +40,30 @@ func (ta *TruAPI) HandleSpotlight(res http.ResponseWriter, req *http.Request) {
 	} else if highlightID != "" {
 		spotlightURL = fmt.Sprintf("%s/highlight/%s/spotlight", ta.APIContext.Config.Spotlight.URL, highlightID)
 	}
+	// the format and the size of the image are left for spotlight to validate
+	query := url.Values{}
+	for _, param := range []string{"format", "w", "h"} {
+		if value := req.FormValue(param); value != "" {
+			query.Set(param, value)
+		}
+	}
+	if len(query) > 0 {
+		spotlightURL = fmt.Sprintf("%s?%s", spotlightURL, query.Encode())
+	}
 	request, err := http.NewRequest("GET", spotlightURL, req.Body)
 	if err != nil {
 		fmt.Println("error creating request ", err.Error())
 		render.Error(res, req, err.Error(), http.StatusBadRequest)
 		return
 	}
+	// letting spotlight answer conditional requests with a 304
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		request.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	// spotlight serves WebP only to clients that accept it
+	if accept := req.Header.Get("Accept"); accept != "" {
+		request.Header.Set("Accept", accept)
+	}
 	// processing the request
 	response, err := client.Do(request)
 	if err != nil {
@@ -54,6 +71,7 @@ func (ta *TruAPI) HandleSpotlight(res http.ResponseWriter, req *http.Request) {
 		render.Error(res, req, err.Error(), http.StatusBadRequest)
 		return
 	}
+	defer response.Body.Close()
 
 	// reading the response
 	responseBody, err := ioutil.ReadAll(response.Body)
@@ -63,8 +81,27 @@ func (ta *TruAPI) HandleSpotlight(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if etag := response.Header.Get("ETag"); etag != "" {
+		res.Header().Set("ETag", etag)
+	}
+	for _, vary := range response.Header["Vary"] {
+		res.Header().Add("Vary", vary)
+	}
+	if response.StatusCode == http.StatusNotModified {
+		res.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		render.Error(res, req, string(responseBody), response.StatusCode)
+		return
+	}
+
 	// if all went well, sending back the response
-	res.Header().Set("Content-Type", "image/jpeg")
+	contentType := response.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "image/jpeg"
+	}
+	res.Header().Set("Content-Type", contentType)
 	res.WriteHeader(http.StatusOK)
 	_, err = res.Write(responseBody)
 	if err != nil {
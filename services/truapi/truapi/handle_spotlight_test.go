@@ -0,0 +1,60 @@
+package truapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+)
+
+func TestHandleSpotlightNegotiatesTheFormat(t *testing.T) {
+	var accept string
+	spotlight := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		w.Header().Set("Vary", "Accept")
+		w.Header().Set("Content-Type", "image/webp")
+		_, _ = w.Write([]byte("webp"))
+	}))
+	defer spotlight.Close()
+	ta := &TruAPI{
+		APIContext: truCtx.TruAPIContext{Config: truCtx.Config{Spotlight: truCtx.SpotlightConfig{URL: spotlight.URL}}},
+		httpClient: spotlight.Client(),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/spotlight?claim_id=1&format=webp", nil)
+	req.Header.Set("Accept", "image/webp,image/*")
+	w := httptest.NewRecorder()
+	ta.HandleSpotlight(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/webp,image/*", accept)
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	assert.Equal(t, "image/webp", w.Header().Get("Content-Type"))
+	assert.Equal(t, "webp", w.Body.String())
+}
+
+func TestHandleSpotlightForwardsTheSize(t *testing.T) {
+	var upstream string
+	spotlight := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstream = r.URL.String()
+		_, _ = w.Write([]byte("jpeg"))
+	}))
+	defer spotlight.Close()
+	ta := &TruAPI{
+		APIContext: truCtx.TruAPIContext{Config: truCtx.Config{Spotlight: truCtx.SpotlightConfig{URL: spotlight.URL}}},
+		httpClient: spotlight.Client(),
+	}
+	spotlightURL := func(target string) string {
+		w := httptest.NewRecorder()
+		ta.HandleSpotlight(w, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		return upstream
+	}
+
+	assert.Equal(t, "/claim/1/spotlight?h=630&w=1200", spotlightURL("/api/v1/spotlight?claim_id=1&w=1200&h=630"))
+	assert.Equal(t, "/argument/1/2/spotlight?format=webp&w=800", spotlightURL("/api/v1/spotlight?claim_id=1&argument_id=2&w=800&format=webp"))
+	assert.Equal(t, "/comment/3/spotlight", spotlightURL("/api/v1/spotlight?comment_id=3"))
+}
@@ -105,19 +105,11 @@ func (ta *TruAPI) HandleTrackEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if userOk && user != nil {
-		dbEvent.Address = user.Address
-	}
-
-	if user == nil {
-		sess, err := cookies.GetAnonymousSession(ta.APIContext, r)
-		if err != nil {
-			fmt.Println("unable to get session id cookie")
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-		dbEvent.IsAnonymous = true
-		dbEvent.SessionID = sess.SessionID
+	err = ta.identifyViewer(r, &dbEvent)
+	if err != nil {
+		fmt.Println("unable to get session id cookie")
+		w.WriteHeader(http.StatusOK)
+		return
 	}
 	err = ta.DBClient.Add(&dbEvent)
 	if err != nil {
@@ -126,3 +118,20 @@ func (ta *TruAPI) HandleTrackEvent(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// identifyViewer sets who a tracked event is from: the address of an authenticated user,
+// or the session of an anonymous one
+func (ta *TruAPI) identifyViewer(r *http.Request, event *db.TrackEvent) error {
+	user, ok := r.Context().Value(userContextKey).(*cookies.AuthenticatedUser)
+	if ok && user != nil {
+		event.Address = user.Address
+		return nil
+	}
+	sess, err := cookies.GetAnonymousSession(ta.APIContext, r)
+	if err != nil {
+		return err
+	}
+	event.IsAnonymous = true
+	event.SessionID = sess.SessionID
+	return nil
+}
@@ -0,0 +1,39 @@
+package truapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+	"github.com/TruStory/octopus/services/truapi/truapi/render"
+)
+
+// HandleTwitterProfilesBackfill repopulates the stored Twitter profiles from a provided list
+func (ta *TruAPI) HandleTwitterProfilesBackfill(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		render.Error(w, r, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var profiles []db.TwitterProfile
+	err := json.NewDecoder(r.Body).Decode(&profiles)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	for _, profile := range profiles {
+		if profile.FetchedAt.IsZero() {
+			render.Error(w, r, "every profile needs a fetched_at timestamp", http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = ta.DBClient.UpsertTwitterProfiles(profiles)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	render.Response(w, r, true, http.StatusOK)
+}
@@ -72,6 +72,14 @@ func (ta *TruAPI) HandleUnsigned(r *http.Request) chttp.Response {
 			err = claim.ModuleCodec.UnmarshalJSON(data, c)
 			if err == nil {
 				ta.sendClaimToSlack(*c)
+				// resolving the image right away caches the claim's media flag
+				go func(c claim.Claim) {
+					_, err := ta.resolveClaimImage(c)
+					if err != nil {
+						fmt.Println("could not cache the claim media flag err: ", err)
+					}
+				}(*c)
+				go ta.indexClaims([]claim.Claim{*c})
 			}
 		}
 	}
@@ -10,8 +10,8 @@ import (
 // HandleUpload proxies the request from the clients to the uploader service
 func (ta *TruAPI) HandleUpload(res http.ResponseWriter, req *http.Request) {
 
-	// firing up the http client
-	client := &http.Client{}
+	// uploads can take longer than the shared client's timeout, only its connections are reused
+	client := &http.Client{Transport: ta.httpClient.Transport}
 
 	// preparing the request
 	request, err := http.NewRequest("POST", ta.APIContext.Config.App.UploadURL, req.Body)
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/TruStory/octopus/services/truapi/db"
@@ -73,6 +74,8 @@ type UpdateUserViaCookieRequest struct {
 	Credentials *db.UserCredentials `json:"credentials,omitempty"`
 }
 
+const defaultUsernameChangeCooldown = 30 // days
+
 // TruErrors for handle user
 var (
 	ErrExistingAccountWithEmail = render.TruError{Code: 100, Message: "There's already an account with this email address."}
@@ -87,6 +90,15 @@ var (
 	ErrInvalidEmail             = render.TruError{Code: 109, Message: "Invalid email."}
 )
 
+// usernameChangeCooldown is the minimum time between two username changes of a user
+func (ta *TruAPI) usernameChangeCooldown() time.Duration {
+	days := ta.APIContext.Config.App.UsernameChangeCooldown
+	if days == 0 {
+		days = defaultUsernameChangeCooldown
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
 // HandleUserDetails takes a `UserRequest` and returns a `UserResponse`
 func (ta *TruAPI) HandleUserDetails(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -338,7 +350,12 @@ func (ta *TruAPI) updateUserDetailsViaCookie(w http.ResponseWriter, r *http.Requ
 			return
 		}
 
-		err = validatePassword(request.Password.New)
+		account, err := ta.DBClient.UserByID(user.ID)
+		if err != nil || account == nil {
+			render.Error(w, r, "no such user found", http.StatusBadRequest)
+			return
+		}
+		err = validatePassword(request.Password.New, account.Username, account.Email)
 		if err != nil {
 			render.LoginError(w, r, render.TruError{Code: ErrInvalidPassword.Code, Message: err.Error()}, http.StatusOK)
 			return
@@ -355,7 +372,7 @@ func (ta *TruAPI) updateUserDetailsViaCookie(w http.ResponseWriter, r *http.Requ
 
 	// if user wants to change their profile
 	if request.Profile != nil {
-		err = ta.DBClient.UpdateProfile(user.ID, request.Profile)
+		err = ta.DBClient.UpdateProfile(user.ID, request.Profile, ta.usernameChangeCooldown())
 		if err != nil {
 			render.Error(w, r, err.Error(), http.StatusBadRequest)
 			return
@@ -367,6 +384,16 @@ func (ta *TruAPI) updateUserDetailsViaCookie(w http.ResponseWriter, r *http.Requ
 
 	// if user (who was previously authorized via connected account) wants to add a password to their accounts
 	if request.Credentials != nil {
+		account, err := ta.DBClient.UserByID(user.ID)
+		if err != nil || account == nil {
+			render.Error(w, r, "no such user found", http.StatusBadRequest)
+			return
+		}
+		err = validatePassword(request.Credentials.Password, account.Username, request.Credentials.Email)
+		if err != nil {
+			render.LoginError(w, r, render.TruError{Code: ErrInvalidPassword.Code, Message: err.Error()}, http.StatusOK)
+			return
+		}
 		err = ta.DBClient.SetUserCredentials(user.ID, request.Credentials)
 		if err != nil {
 			render.Error(w, r, err.Error(), http.StatusBadRequest)
@@ -418,8 +445,7 @@ func (ta *TruAPI) getUserDetails(w http.ResponseWriter, r *http.Request) {
 }
 
 func (ta *TruAPI) createUserResponse(ctx context.Context, user *db.User, singedUp bool) UserResponse {
-	largeURI := strings.Replace(user.AvatarURL, "_bigger", "_200x200", 1)
-	largeURI = strings.Replace(largeURI, "http://", "https://", 1)
+	largeURI := ta.upgradeAvatarURL(user.AvatarURL)
 
 	aa := ta.appAccountResolver(ctx, queryByAddress{ID: user.Address})
 	var accountNumber, sequence uint64
@@ -474,7 +500,7 @@ func validateRegisterRequest(request RegisterUserRequest) error {
 		return errors.New("usernames cannot seem to be related to trustory")
 	}
 
-	err := validatePassword(request.Password)
+	err := validatePassword(request.Password, request.Username, request.Email)
 	if err != nil {
 		return err
 	}
@@ -482,7 +508,12 @@ func validateRegisterRequest(request RegisterUserRequest) error {
 	return nil
 }
 
-func validatePassword(password string) error {
+// identifiers shorter than this are too common to be rejected inside passwords
+const passwordIdentifierMinLength = 3
+
+// validatePassword checks the password strength and rejects passwords containing
+// any of the user's identifiers (username or email)
+func validatePassword(password string, identifiers ...string) error {
 	hasMinLength, hasUppercaseLetter, hasLowercaseLetter, hasNumber, hasSpecial := false, false, false, false, false
 
 	for _, char := range password {
@@ -522,6 +553,21 @@ func validatePassword(password string) error {
 		return errors.New("password must have a special character")
 	}
 
+	lowered := strings.ToLower(password)
+	for _, identifier := range identifiers {
+		identifier = strings.ToLower(strings.TrimSpace(identifier))
+		// the local part of an email is what people tend to reuse
+		if at := strings.Index(identifier, "@"); at >= 0 {
+			identifier = identifier[:at]
+		}
+		if len(identifier) < passwordIdentifierMinLength {
+			continue
+		}
+		if strings.Contains(lowered, identifier) {
+			return errors.New("password cannot contain your username or email")
+		}
+	}
+
 	return nil
 }
 
@@ -19,6 +19,7 @@ var (
 	ErrServerError        = render.TruError{Code: 300, Message: "Server Error. Please try again later."}
 	ErrUnverifiedEmail    = render.TruError{Code: 301, Message: "Please verify your email."}
 	ErrInvalidCredentials = render.TruError{Code: 302, Message: "Invalid login credentials."}
+	ErrTooManyLogins      = render.TruError{Code: 303, Message: "Too many login attempts. Please try again later."}
 )
 
 // HandleUserAuthentication handles the moderation of the users who have requested to signup
@@ -36,11 +37,18 @@ func (ta *TruAPI) HandleUserAuthentication(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if !ta.allowLoginAttempt(r, request.Identifier) {
+		render.LoginError(w, r, ErrTooManyLogins, http.StatusTooManyRequests)
+		return
+	}
+
 	user, err := ta.DBClient.GetAuthenticatedUser(request.Identifier, request.Password)
 	if err != nil {
 		render.LoginError(w, r, err, http.StatusBadRequest)
 		return
 	}
+	// a successful login clears the attempts against the account, but not against the IP
+	ta.loginRateLimiter.reset(loginIdentifierKey(request.Identifier))
 
 	if (*user).VerifiedAt.IsZero() {
 		render.LoginError(w, r, ErrUnverifiedEmail, http.StatusBadRequest)
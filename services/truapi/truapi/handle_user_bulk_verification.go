@@ -0,0 +1,50 @@
+package truapi
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/TruStory/octopus/services/truapi/truapi/render"
+)
+
+// UserBulkVerificationRequest represents the http request to verify a batch of users
+type UserBulkVerificationRequest struct {
+	UserIDs []int64 `json:"user_ids"`
+}
+
+// UserBulkVerificationResponse represents the result of a bulk verification
+type UserBulkVerificationResponse struct {
+	Verified int `json:"verified"`
+}
+
+// HandleUserBulkVerification verifies a batch of trusted users without sending them verification emails
+func (ta *TruAPI) HandleUserBulkVerification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		render.Error(w, r, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request UserBulkVerificationRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	if len(request.UserIDs) == 0 {
+		render.Error(w, r, "user ids are required", http.StatusBadRequest)
+		return
+	}
+
+	admin, _, _ := r.BasicAuth()
+	verified, err := ta.DBClient.BulkVerifyUsers(request.UserIDs)
+	if err != nil {
+		log.Printf("[audit] bulk verification by %s from %s failed for users %v: %s\n", admin, r.RemoteAddr, request.UserIDs, err)
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[audit] bulk verification by %s from %s verified %d of users %v\n", admin, r.RemoteAddr, verified, request.UserIDs)
+
+	render.Response(w, r, UserBulkVerificationResponse{Verified: verified}, http.StatusOK)
+}
@@ -95,28 +95,33 @@ func (ta *TruAPI) resetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = validatePassword(request.Password)
+	prt, err := ta.DBClient.UnusedResetTokenByUserAndToken(request.UserID, request.Token)
 	if err != nil {
 		render.Error(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if prt == nil {
+		render.LoginError(w, r, ErrNoSuchToken, http.StatusNotFound)
+		return
+	}
 
-	prt, err := ta.DBClient.UnusedResetTokenByUserAndToken(request.UserID, request.Token)
+	user, err := ta.DBClient.VerifiedUserByID(request.UserID)
 	if err != nil {
-		render.Error(w, r, err.Error(), http.StatusBadRequest)
+		render.Error(w, r, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if prt == nil {
-		render.LoginError(w, r, ErrNoSuchToken, http.StatusNotFound)
+	if user == nil {
+		render.LoginError(w, r, ErrEmailNotVerified, http.StatusBadRequest)
 		return
 	}
 
-	err = ta.DBClient.UseResetToken(prt)
+	// the token is only used once the new password is valid and saved
+	err = validatePassword(request.Password, user.Username, user.Email)
 	if err != nil {
 		render.Error(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
-	err = ta.DBClient.ResetPassword(request.UserID, request.Password)
+	err = ta.DBClient.ResetPasswordWithToken(prt, request.Password)
 	if err != nil {
 		render.Error(w, r, err.Error(), http.StatusBadRequest)
 		return
@@ -0,0 +1,51 @@
+package truapi
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+	"github.com/TruStory/octopus/services/truapi/truapi/render"
+)
+
+// UserGroupRequest represents the http request to assign a user to a group
+type UserGroupRequest struct {
+	UserID    int64        `json:"user_id"`
+	UserGroup db.UserGroup `json:"user_group"`
+}
+
+// UserGroupResponse represents the group transition of a user
+type UserGroupResponse struct {
+	UserID        int64        `json:"user_id"`
+	PreviousGroup db.UserGroup `json:"previous_group"`
+	UserGroup     db.UserGroup `json:"user_group"`
+}
+
+// HandleUserGroup assigns a user to a user group
+func (ta *TruAPI) HandleUserGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		render.Error(w, r, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request UserGroupRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	previous, err := ta.DBClient.SetUserGroup(request.UserID, request.UserGroup)
+	if err != nil {
+		render.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Printf("user %d moved from group %s to %s\n", request.UserID, previous, request.UserGroup)
+
+	render.Response(w, r, UserGroupResponse{
+		UserID:        request.UserID,
+		PreviousGroup: previous,
+		UserGroup:     request.UserGroup,
+	}, http.StatusOK)
+}
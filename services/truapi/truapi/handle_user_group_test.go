@@ -0,0 +1,62 @@
+package truapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+type fakeUserGroupStore struct {
+	db.Datastore
+	groups map[int64]db.UserGroup
+}
+
+func (s *fakeUserGroupStore) SetUserGroup(id int64, group db.UserGroup) (db.UserGroup, error) {
+	if !group.IsValid() {
+		return 0, errors.New("invalid user group")
+	}
+	previous, ok := s.groups[id]
+	if !ok {
+		return 0, errors.New("invalid user")
+	}
+	s.groups[id] = group
+	return previous, nil
+}
+
+func TestHandleUserGroup(t *testing.T) {
+	store := &fakeUserGroupStore{groups: map[int64]db.UserGroup{7: db.UserGroupUser}}
+	ta := &TruAPI{DBClient: store}
+	post := func(method, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ta.HandleUserGroup(w, httptest.NewRequest(method, "/api/v1/users/group", strings.NewReader(body)))
+		return w
+	}
+
+	w := post(http.MethodPost, `{"user_id": 7, "user_group": 3}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data UserGroupResponse `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, UserGroupResponse{UserID: 7, PreviousGroup: db.UserGroupUser, UserGroup: db.UserGroupResearchAnalyst}, response.Data)
+	assert.Equal(t, db.UserGroup(db.UserGroupResearchAnalyst), store.groups[7])
+
+	// the transition reports the group set by the previous call
+	w = post(http.MethodPost, `{"user_id": 7, "user_group": 1}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, db.UserGroup(db.UserGroupResearchAnalyst), response.Data.PreviousGroup)
+
+	assert.Equal(t, http.StatusBadRequest, post(http.MethodPost, `{"user_id": 7, "user_group": 42}`).Code)
+	assert.Equal(t, db.UserGroup(db.UserGroupEmployee), store.groups[7])
+	assert.Equal(t, http.StatusBadRequest, post(http.MethodPost, `{"user_id": 8, "user_group": 1}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, post(http.MethodPost, `not json`).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, post(http.MethodGet, "").Code)
+}
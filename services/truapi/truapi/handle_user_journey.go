@@ -6,10 +6,14 @@ import (
 	"net/http"
 	"strconv"
 
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
 	"github.com/TruStory/octopus/services/truapi/db"
 	"github.com/TruStory/octopus/services/truapi/truapi/render"
+	"github.com/TruStory/truchain/x/staking"
 )
 
+const defaultAgreesRequired = 5
+
 type UserJourneyResponse struct {
 	UserID int64                       `json:"user_id"`
 	Steps  map[db.UserJourneyStep]bool `json:"steps"`
@@ -111,13 +115,25 @@ func (ta *TruAPI) isFiveAgreesStepComplete(ctx context.Context, user *db.User) b
 	}
 
 	arguments := ta.appAccountArgumentsResolver(ctx, queryByAddress{ID: user.Address})
+	return hasReceivedAgrees(arguments, agreesRequired(ta.APIContext.Config.Rewards))
+}
+
+// agreesRequired returns the number of agrees needed to complete the "received five agrees" step
+func agreesRequired(config truCtx.RewardsConfig) int {
+	if config.AgreesRequired > 0 {
+		return config.AgreesRequired
+	}
+	return defaultAgreesRequired
+}
 
+// hasReceivedAgrees tells whether the arguments received at least threshold agrees in total
+func hasReceivedAgrees(arguments []staking.Argument, threshold int) bool {
 	agreesReceived := 0
 	for _, argument := range arguments {
 		agreesReceived += argument.UpvotedCount
 
-		// bailing out of the loop, as soon as we get the counter to 5
-		if agreesReceived >= 5 {
+		// bailing out of the loop, as soon as we get the counter to the threshold
+		if agreesReceived >= threshold {
 			return true
 		}
 	}
@@ -0,0 +1,26 @@
+package truapi
+
+import (
+	"testing"
+
+	"github.com/TruStory/truchain/x/staking"
+	"github.com/stretchr/testify/assert"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+)
+
+func TestHasReceivedAgrees(t *testing.T) {
+	threshold := agreesRequired(truCtx.RewardsConfig{AgreesRequired: 3})
+	assert.Equal(t, 3, threshold)
+
+	below := []staking.Argument{{UpvotedCount: 1}, {UpvotedCount: 1}}
+	assert.False(t, hasReceivedAgrees(below, threshold))
+
+	at := []staking.Argument{{UpvotedCount: 1}, {UpvotedCount: 2}}
+	assert.True(t, hasReceivedAgrees(at, threshold))
+
+	// defaults to five agrees
+	assert.Equal(t, 5, agreesRequired(truCtx.RewardsConfig{}))
+	assert.False(t, hasReceivedAgrees([]staking.Argument{{UpvotedCount: 4}}, agreesRequired(truCtx.RewardsConfig{})))
+	assert.True(t, hasReceivedAgrees([]staking.Argument{{UpvotedCount: 5}}, agreesRequired(truCtx.RewardsConfig{})))
+}
@@ -3,6 +3,7 @@ package truapi
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/TruStory/octopus/services/truapi/chttp"
 )
@@ -24,7 +25,14 @@ func (ta *TruAPI) handleUsernameSearch(r *http.Request) chttp.Response {
 	}
 
 	prefix := r.Form["username_prefix"][0]
-	usernames, err := ta.DBClient.UsernamesAndImagesByPrefix(prefix)
+	limit := 0
+	if r.FormValue("limit") != "" {
+		limit, err = strconv.Atoi(r.FormValue("limit"))
+		if err != nil {
+			return chttp.SimpleErrorResponse(400, err)
+		}
+	}
+	usernames, err := ta.DBClient.UsernamesAndImagesByPrefix(prefix, limit)
 	if err != nil {
 		return chttp.SimpleErrorResponse(500, err)
 	}
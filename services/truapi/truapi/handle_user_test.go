@@ -49,3 +49,28 @@ func TestGetEmailDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		message  string
+	}{
+		{"too short", "Ab1!", "password must be 8 characters long"},
+		{"no number", "Password!", "password must have a number"},
+		{"contains username", "MyShaneV!2019", "password cannot contain your username or email"},
+		{"contains email", "shane.v#Pass1", "password cannot contain your username or email"},
+		{"acceptable", "Tru$tory2019", ""},
+	}
+	for _, tt := range tests {
+		err := validatePassword(tt.password, "shanev", "shane.v@trustory.io")
+		if tt.message == "" {
+			assert.NoError(t, err, tt.name)
+			continue
+		}
+		assert.EqualError(t, err, tt.message, tt.name)
+	}
+
+	// short identifiers are ignored
+	assert.NoError(t, validatePassword("Hello-World9", "he"))
+}
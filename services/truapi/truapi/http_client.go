@@ -0,0 +1,39 @@
+package truapi
+
+import (
+	"net/http"
+	"time"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+)
+
+const (
+	defaultHTTPClientTimeout             = 10 * time.Second
+	defaultHTTPClientMaxIdleConnsPerHost = 10
+	defaultHTTPClientIdleConnTimeout     = 90 * time.Second
+)
+
+// newHTTPClient creates the client shared by the handlers calling other services,
+// pooling its connections to each of them
+func newHTTPClient(config truCtx.HTTPClientConfig) *http.Client {
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = defaultHTTPClientTimeout
+	}
+	maxIdleConnsPerHost := config.MaxIdleConnsPerHost
+	if maxIdleConnsPerHost == 0 {
+		maxIdleConnsPerHost = defaultHTTPClientMaxIdleConnsPerHost
+	}
+	idleConnTimeout := time.Duration(config.IdleConnTimeout) * time.Second
+	if idleConnTimeout == 0 {
+		idleConnTimeout = defaultHTTPClientIdleConnTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	transport.IdleConnTimeout = idleConnTimeout
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
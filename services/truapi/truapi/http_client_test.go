@@ -0,0 +1,27 @@
+package truapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	client := newHTTPClient(truCtx.HTTPClientConfig{Timeout: 3, MaxIdleConnsPerHost: 20, IdleConnTimeout: 30})
+	assert.Equal(t, 3*time.Second, client.Timeout)
+	transport := client.Transport.(*http.Transport)
+	assert.Equal(t, 20, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 30*time.Second, transport.IdleConnTimeout)
+
+	client = newHTTPClient(truCtx.HTTPClientConfig{})
+	assert.Equal(t, defaultHTTPClientTimeout, client.Timeout)
+	transport = client.Transport.(*http.Transport)
+	assert.Equal(t, defaultHTTPClientMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, defaultHTTPClientIdleConnTimeout, transport.IdleConnTimeout)
+	// the default transport isn't modified
+	assert.NotEqual(t, http.DefaultTransport, client.Transport)
+}
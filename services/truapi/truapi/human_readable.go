@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	app "github.com/TruStory/truchain/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -37,3 +38,52 @@ func HumanReadable(coin sdk.Coin) string {
 	}
 	return fmt.Sprintf("%s%s%s", number, ".", decimal)
 }
+
+// HumanReadableFormatted formats a coin amount in whole units for display, grouping the digits by
+// thousands (e.g. "1,234,567.89"). Like HumanReadable, decimals are truncated to decimalPlaces and
+// trailing zeros are dropped. A negative decimalPlaces shows no decimals.
+func HumanReadableFormatted(coin sdk.Coin, decimalPlaces int) string {
+	if (sdk.Coin{}) == coin {
+		return "0"
+	}
+	shanev := sdk.NewInt(app.Shanev)
+	number := groupThousands(coin.Amount.Quo(shanev).String())
+	// the remainder is zero padded to the six decimals of a shanev
+	decimal := fmt.Sprintf("%06d", coin.Amount.Mod(shanev).Int64())
+	if decimalPlaces < 0 {
+		decimalPlaces = 0
+	}
+	if decimalPlaces < len(decimal) {
+		decimal = decimal[0:decimalPlaces]
+	}
+	decimal = strings.TrimRight(decimal, "0")
+	if decimal == "" {
+		return number
+	}
+	return fmt.Sprintf("%s%s%s", number, ".", decimal)
+}
+
+// displayDecimalPlaces is the number of decimals HumanReadable shows for a coin:
+// two from one unit up, and four below
+func displayDecimalPlaces(coin sdk.Coin) int {
+	if (sdk.Coin{}) == coin || coin.Amount.LT(sdk.NewInt(app.Shanev)) {
+		return 4
+	}
+	return 2
+}
+
+// groupThousands separates the digits of an integer by thousands
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	head := len(digits) % 3
+	if head == 0 {
+		head = 3
+	}
+	groups := []string{digits[:head]}
+	for i := head; i < len(digits); i += 3 {
+		groups = append(groups, digits[i:i+3])
+	}
+	return strings.Join(groups, ",")
+}
@@ -38,3 +38,26 @@ func TestHumanReadable(t *testing.T) {
 	// return "0" for empty struct
 	assert.Equal(t, "0", HumanReadable(sdk.Coin{}))
 }
+
+func TestHumanReadableFormatted(t *testing.T) {
+	coin := func(amount int64) sdk.Coin { return sdk.NewInt64Coin("utru", amount) }
+
+	// below 1 unit
+	assert.Equal(t, "0.1234", HumanReadableFormatted(coin(123456), displayDecimalPlaces(coin(123456))))
+	assert.Equal(t, "0.12", HumanReadableFormatted(coin(123456), 2))
+	assert.Equal(t, "0", HumanReadableFormatted(coin(99), 4))
+	// exactly 1 unit
+	assert.Equal(t, "1", HumanReadableFormatted(coin(1000000), displayDecimalPlaces(coin(1000000))))
+	assert.Equal(t, 2, displayDecimalPlaces(coin(1000000)))
+	// millions
+	assert.Equal(t, "1,234,567.89", HumanReadableFormatted(coin(1234567890000), 2))
+	assert.Equal(t, "1,234,567.891", HumanReadableFormatted(coin(1234567891000), 3))
+	assert.Equal(t, "1,234,567", HumanReadableFormatted(coin(1234567891000), 0))
+	assert.Equal(t, "100,000,000", HumanReadableFormatted(coin(100000000000000), 2))
+	assert.Equal(t, "999", HumanReadableFormatted(coin(999000000), 2))
+	assert.Equal(t, "1,000", HumanReadableFormatted(coin(1000000000), 2))
+
+	assert.Equal(t, "0", HumanReadableFormatted(sdk.Coin{}, 2))
+	// negative decimals are treated as none instead of panicking
+	assert.Equal(t, "1,234", HumanReadableFormatted(coin(1234560000), -1))
+}
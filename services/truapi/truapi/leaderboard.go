@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"path"
+	"sort"
 	"time"
 
 	"github.com/TruStory/truchain/x/bank/exported"
@@ -26,6 +27,19 @@ const (
 	leaderboardDefaultTopDisplaying = 50
 )
 
+// leaderboard periods, rolling windows ending now
+const (
+	leaderboardPeriodAll   = "all"
+	leaderboardPeriodWeek  = "week"
+	leaderboardPeriodMonth = "month"
+)
+
+var leaderboardPeriodDateFilters = map[string]LeaderboardDateFilter{
+	leaderboardPeriodAll:   LeaderboardDateFilterAllTime,
+	leaderboardPeriodWeek:  LeaderboardDateFilterLastWeek,
+	leaderboardPeriodMonth: LeaderboardDateFilterLastMonth,
+}
+
 type UserStatsByCommunity struct {
 	EarnedCoin     sdk.Int
 	Claims         int64
@@ -92,7 +106,7 @@ func (ta *TruAPI) statsByDate(date time.Time) (*LeaderboardStats, error) {
 		argumentCreatorsMappings := make(map[uint64]string)
 		ucs := stats.getUserStatsByCommunity(claim.Creator.String(), claim.CommunityID)
 		ucs.Claims++
-		arguments, err := ta.getClaimArguments(claim.ID)
+		arguments, err := ta.getClaimArguments(context.Background(), claim.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -164,21 +178,11 @@ func (ta *TruAPI) leaderboardSeed() (time.Time, error) {
 		if err != nil {
 			return err
 		}
-		for user, userStats := range stats.UserStats {
-			for communityID, cStats := range userStats.CommunityStats {
-
-				m := db.LeaderboardUserMetric{
-					Date:           t,
-					Earned:         cStats.EarnedCoin.Int64(),
-					CommunityID:    communityID,
-					Address:        user,
-					AgreesGiven:    cStats.AgreesGiven,
-					AgreesReceived: cStats.AgreesReceived,
-				}
-				err := ta.DBClient.UpsertLeaderboardMetric(tx, &m)
-				if err != nil {
-					return err
-				}
+		for _, m := range leaderboardUserMetrics(t, stats, nil) {
+			m := m
+			err := ta.DBClient.UpsertLeaderboardMetric(tx, &m)
+			if err != nil {
+				return err
 			}
 		}
 		err = ta.DBClient.UpsertLeaderboardProcessedDate(tx, &db.LeaderboardProcessedDate{
@@ -208,22 +212,11 @@ func (ta *TruAPI) leaderboardStatsBetween(start, end time.Time) error {
 	}
 
 	err = ta.DBClient.FeedLeaderboardInTransaction(func(tx *pg.Tx) error {
-		for user, endUserStats := range endStats.UserStats {
-
-			for communityID, endUserCommunityStats := range endUserStats.CommunityStats {
-				startUserCommunityStats := startStats.getUserStatsByCommunity(user, communityID)
-				m := db.LeaderboardUserMetric{
-					Date:           start,
-					Earned:         endUserCommunityStats.EarnedCoin.Sub(startUserCommunityStats.EarnedCoin).Int64(),
-					CommunityID:    communityID,
-					Address:        user,
-					AgreesGiven:    endUserCommunityStats.AgreesGiven - startUserCommunityStats.AgreesGiven,
-					AgreesReceived: endUserCommunityStats.AgreesReceived - startUserCommunityStats.AgreesReceived,
-				}
-				err := ta.DBClient.UpsertLeaderboardMetric(tx, &m)
-				if err != nil {
-					return err
-				}
+		for _, m := range leaderboardUserMetrics(start, endStats, startStats) {
+			m := m
+			err := ta.DBClient.UpsertLeaderboardMetric(tx, &m)
+			if err != nil {
+				return err
 			}
 		}
 		err = ta.DBClient.UpsertLeaderboardProcessedDate(tx, &db.LeaderboardProcessedDate{
@@ -239,6 +232,43 @@ func (ta *TruAPI) leaderboardStatsBetween(start, end time.Time) error {
 	return nil
 }
 
+// leaderboardUserMetrics returns the metrics of every user in every community they took part in,
+// as the difference between the stats at the end and at the start of the period. Without start stats,
+// the metrics are the totals at the end. Users without activity in the period are left out.
+func leaderboardUserMetrics(date time.Time, endStats, startStats *LeaderboardStats) []db.LeaderboardUserMetric {
+	metrics := make([]db.LeaderboardUserMetric, 0)
+	for user, endUserStats := range endStats.UserStats {
+		for communityID, endUserCommunityStats := range endUserStats.CommunityStats {
+			m := db.LeaderboardUserMetric{
+				Date:           date,
+				Earned:         endUserCommunityStats.EarnedCoin.Int64(),
+				CommunityID:    communityID,
+				Address:        user,
+				AgreesGiven:    endUserCommunityStats.AgreesGiven,
+				AgreesReceived: endUserCommunityStats.AgreesReceived,
+			}
+			if startStats != nil {
+				startUserCommunityStats := startStats.getUserStatsByCommunity(user, communityID)
+				m.Earned = endUserCommunityStats.EarnedCoin.Sub(startUserCommunityStats.EarnedCoin).Int64()
+				m.AgreesGiven -= startUserCommunityStats.AgreesGiven
+				m.AgreesReceived -= startUserCommunityStats.AgreesReceived
+			}
+			// users without activity in the period have no metrics for it
+			if m.Earned == 0 && m.AgreesGiven == 0 && m.AgreesReceived == 0 {
+				continue
+			}
+			metrics = append(metrics, m)
+		}
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].Address != metrics[j].Address {
+			return metrics[i].Address < metrics[j].Address
+		}
+		return metrics[i].CommunityID < metrics[j].CommunityID
+	})
+	return metrics
+}
+
 func getZeroHour(t time.Time) time.Time {
 	year, month, day := t.Date()
 	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
@@ -322,6 +352,16 @@ func (ta *TruAPI) leaderboardScheduler() {
 type queryByDateAndMetricFilter struct {
 	DateFilter LeaderboardDateFilter   `graphql:"dateFilter,optional"`
 	Metric     LeaderboardMetricFilter `graphql:"metricFilter,optional"`
+	// Period is one of all, week or month, and takes precedence over the date filter
+	Period string `graphql:"period,optional"`
+}
+
+// leaderboardSince returns the first day of the metrics in the date filter, the zero time meaning all time
+func leaderboardSince(now time.Time, dateFilter LeaderboardDateFilter) time.Time {
+	if dateFilter.Value() == 0 {
+		return time.Time{}
+	}
+	return getZeroHour(now.Add(dateFilter.Value()))
 }
 
 func (ta *TruAPI) leaderboardResolver(ctx context.Context, q queryByDateAndMetricFilter) []db.LeaderboardTopUser {
@@ -329,11 +369,16 @@ func (ta *TruAPI) leaderboardResolver(ctx context.Context, q queryByDateAndMetri
 	if ta.APIContext.Config.Leaderboard.TopDisplaying > 0 {
 		limit = ta.APIContext.Config.Leaderboard.TopDisplaying
 	}
-	since := getZeroHour(time.Now().Add(q.DateFilter.Value()))
-	// all time
-	if q.DateFilter.Value() == 0 {
-		since = time.Time{}
+	dateFilter := q.DateFilter
+	if q.Period != "" {
+		periodDateFilter, ok := leaderboardPeriodDateFilters[q.Period]
+		if !ok {
+			log.Println("unknown leaderboard period", q.Period)
+			return []db.LeaderboardTopUser{}
+		}
+		dateFilter = periodDateFilter
 	}
+	since := leaderboardSince(time.Now(), dateFilter)
 	sortBy := q.Metric.Value()
 	topUsers, err := ta.DBClient.Leaderboard(since, sortBy, limit, ta.APIContext.Config.Community.InactiveCommunities, "")
 	if err != nil {
@@ -341,3 +386,24 @@ func (ta *TruAPI) leaderboardResolver(ctx context.Context, q queryByDateAndMetri
 	}
 	return topUsers
 }
+
+type queryCommunityLeaderboardParams struct {
+	CommunityID string                  `graphql:"communityId"`
+	DateFilter  LeaderboardDateFilter   `graphql:"dateFilter,optional"`
+	Metric      LeaderboardMetricFilter `graphql:"metricFilter,optional"`
+}
+
+// communityLeaderboardResolver ranks the users of a community by what they earned within it
+func (ta *TruAPI) communityLeaderboardResolver(ctx context.Context, q queryCommunityLeaderboardParams) []db.LeaderboardTopUser {
+	limit := leaderboardDefaultTopDisplaying
+	if ta.APIContext.Config.Leaderboard.TopDisplaying > 0 {
+		limit = ta.APIContext.Config.Leaderboard.TopDisplaying
+	}
+	since := leaderboardSince(time.Now(), q.DateFilter)
+	topUsers, err := ta.DBClient.CommunityLeaderboard(q.CommunityID, since, q.Metric.Value(), limit)
+	if err != nil {
+		log.Println("couldn't get community leaderboard results", err)
+		return []db.LeaderboardTopUser{}
+	}
+	return topUsers
+}
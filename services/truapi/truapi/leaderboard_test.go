@@ -0,0 +1,149 @@
+package truapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+func TestLeaderboardUserMetrics(t *testing.T) {
+	date := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	earn := func(stats *LeaderboardStats, address, communityID string, amount int64, agreesReceived int64) {
+		ucs := stats.getUserStatsByCommunity(address, communityID)
+		ucs.EarnedCoin = ucs.EarnedCoin.Add(sdk.NewInt(amount))
+		ucs.AgreesReceived += agreesReceived
+	}
+
+	startStats := &LeaderboardStats{UserStats: make(map[string]*UserStats)}
+	earn(startStats, "alice", "crypto", 100, 1)
+	earn(startStats, "alice", "sports", 40, 0)
+	endStats := &LeaderboardStats{UserStats: make(map[string]*UserStats)}
+	earn(endStats, "alice", "crypto", 250, 3)
+	earn(endStats, "alice", "sports", 70, 1)
+	earn(endStats, "bob", "crypto", 20, 0)
+	// carol was active before the period only
+	earn(startStats, "carol", "crypto", 60, 2)
+	earn(endStats, "carol", "crypto", 60, 2)
+
+	metric := func(address, communityID string, earned, agreesReceived int64) db.LeaderboardUserMetric {
+		return db.LeaderboardUserMetric{Date: date, Address: address, CommunityID: communityID, Earned: earned, AgreesReceived: agreesReceived}
+	}
+	// alice earned in two communities and has a metric in each, with what she earned in that community only
+	assert.Equal(t, []db.LeaderboardUserMetric{
+		metric("alice", "crypto", 150, 2),
+		metric("alice", "sports", 30, 1),
+		metric("bob", "crypto", 20, 0),
+	}, leaderboardUserMetrics(date, endStats, startStats))
+	// without start stats the totals are kept, as when seeding
+	assert.Equal(t, []db.LeaderboardUserMetric{
+		metric("alice", "crypto", 250, 3),
+		metric("alice", "sports", 70, 1),
+		metric("bob", "crypto", 20, 0),
+		metric("carol", "crypto", 60, 2),
+	}, leaderboardUserMetrics(date, endStats, nil))
+}
+
+// fakeLeaderboardStore aggregates leaderboard metrics in memory like the database
+type fakeLeaderboardStore struct {
+	db.Datastore
+	metrics []db.LeaderboardUserMetric
+}
+
+func (s *fakeLeaderboardStore) topUsers(since time.Time, keep func(m db.LeaderboardUserMetric) bool) []db.LeaderboardTopUser {
+	topUsers := make([]db.LeaderboardTopUser, 0)
+	positions := make(map[string]int)
+	for _, m := range s.metrics {
+		if !keep(m) || m.Date.Before(since) {
+			continue
+		}
+		position, ok := positions[m.Address]
+		if !ok {
+			position = len(topUsers)
+			positions[m.Address] = position
+			topUsers = append(topUsers, db.LeaderboardTopUser{Address: m.Address})
+		}
+		topUsers[position].Earned += m.Earned
+		topUsers[position].AgreesReceived += m.AgreesReceived
+		topUsers[position].AgreesGiven += m.AgreesGiven
+	}
+	return topUsers
+}
+
+func (s *fakeLeaderboardStore) Leaderboard(since time.Time, sortBy string, limit int, excludedCommunities []string, address string) ([]db.LeaderboardTopUser, error) {
+	return s.topUsers(since, func(db.LeaderboardUserMetric) bool { return true }), nil
+}
+
+func (s *fakeLeaderboardStore) CommunityLeaderboard(communityID string, since time.Time, sortBy string, limit int) ([]db.LeaderboardTopUser, error) {
+	topUsers := s.topUsers(since, func(m db.LeaderboardUserMetric) bool { return m.CommunityID == communityID })
+	for i := range topUsers {
+		topUsers[i].CommunityID = communityID
+	}
+	return topUsers, nil
+}
+
+func TestLeaderboardPeriods(t *testing.T) {
+	now := time.Now()
+	store := &fakeLeaderboardStore{}
+	stats := &LeaderboardStats{UserStats: make(map[string]*UserStats)}
+	// the scheduler stores the metrics of every day since the first activity
+	for d := 30; d >= 0; d-- {
+		date := getZeroHour(now.AddDate(0, 0, -d))
+		previous := &LeaderboardStats{UserStats: make(map[string]*UserStats)}
+		for address, userStats := range stats.UserStats {
+			for communityID, ucs := range userStats.CommunityStats {
+				*previous.getUserStatsByCommunity(address, communityID) = *ucs
+			}
+		}
+		switch d {
+		case 20:
+			// carol was active last month, but not this week
+			stats.getUserStatsByCommunity("carol", "crypto").EarnedCoin = sdk.NewInt(80)
+		case 2:
+			stats.getUserStatsByCommunity("alice", "crypto").EarnedCoin = sdk.NewInt(50)
+		}
+		store.metrics = append(store.metrics, leaderboardUserMetrics(date, stats, previous)...)
+	}
+	ta := &TruAPI{DBClient: store}
+
+	board := func(period string) []db.LeaderboardTopUser {
+		return ta.leaderboardResolver(context.Background(), queryByDateAndMetricFilter{Period: period})
+	}
+	assert.Equal(t, []db.LeaderboardTopUser{{Address: "alice", Earned: 50}}, board(leaderboardPeriodWeek))
+	assert.ElementsMatch(t, []db.LeaderboardTopUser{{Address: "carol", Earned: 80}, {Address: "alice", Earned: 50}}, board(leaderboardPeriodMonth))
+	assert.ElementsMatch(t, board(leaderboardPeriodMonth), board(leaderboardPeriodAll))
+	assert.Empty(t, board("fortnight"))
+
+	// without a period the date filter still applies
+	topUsers := ta.leaderboardResolver(context.Background(), queryByDateAndMetricFilter{DateFilter: LeaderboardDateFilterLastWeek})
+	assert.Equal(t, []db.LeaderboardTopUser{{Address: "alice", Earned: 50}}, topUsers)
+}
+
+func TestCommunityLeaderboardResolver(t *testing.T) {
+	day := func(d int) time.Time { return getZeroHour(time.Now().AddDate(0, 0, -d)) }
+	store := &fakeLeaderboardStore{metrics: []db.LeaderboardUserMetric{
+		{Date: day(1), Address: "alice", CommunityID: "crypto", Earned: 150},
+		{Date: day(2), Address: "alice", CommunityID: "crypto", Earned: 50},
+		{Date: day(1), Address: "alice", CommunityID: "sports", Earned: 30},
+		{Date: day(3), Address: "bob", CommunityID: "sports", Earned: 10},
+	}}
+	ta := &TruAPI{DBClient: store}
+
+	board := func(communityID string) []db.LeaderboardTopUser {
+		return ta.communityLeaderboardResolver(context.Background(), queryCommunityLeaderboardParams{
+			CommunityID: communityID,
+			DateFilter:  LeaderboardDateFilterLastWeek,
+		})
+	}
+	assert.Equal(t, []db.LeaderboardTopUser{
+		{Address: "alice", CommunityID: "crypto", Earned: 200},
+	}, board("crypto"))
+	assert.Equal(t, []db.LeaderboardTopUser{
+		{Address: "alice", CommunityID: "sports", Earned: 30},
+		{Address: "bob", CommunityID: "sports", Earned: 10},
+	}, board("sports"))
+}
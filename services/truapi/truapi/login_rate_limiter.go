@@ -0,0 +1,108 @@
+package truapi
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLoginIPMaxAttempts         = 30
+	defaultLoginIdentifierMaxAttempts = 5
+	defaultLoginRateLimitWindow       = 15 * time.Minute
+
+	// expired windows are swept at most once per interval, so the sweep cost doesn't add to every attempt
+	loginRateLimiterSweepInterval = time.Minute
+)
+
+type loginAttemptWindow struct {
+	attempts int
+	resetsAt time.Time
+}
+
+// loginRateLimiter counts login attempts per key over fixed time windows
+type loginRateLimiter struct {
+	mu      sync.Mutex
+	windows map[string]loginAttemptWindow
+	sweptAt time.Time
+}
+
+func newLoginRateLimiter() *loginRateLimiter {
+	return &loginRateLimiter{windows: make(map[string]loginAttemptWindow)}
+}
+
+// allow records an attempt for the key and tells whether it is within maxAttempts for the current window
+func (l *loginRateLimiter) allow(key string, maxAttempts int, window time.Duration, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.sweptAt) >= loginRateLimiterSweepInterval {
+		for k, w := range l.windows {
+			if !now.Before(w.resetsAt) {
+				delete(l.windows, k)
+			}
+		}
+		l.sweptAt = now
+	}
+	w, ok := l.windows[key]
+	if !ok || !now.Before(w.resetsAt) {
+		w = loginAttemptWindow{resetsAt: now.Add(window)}
+	}
+	w.attempts++
+	l.windows[key] = w
+	return w.attempts <= maxAttempts
+}
+
+// reset forgets the attempts recorded for the key
+func (l *loginRateLimiter) reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.windows, key)
+}
+
+func loginIPKey(r *http.Request) string {
+	return "ip:" + clientIP(r)
+}
+
+func loginIdentifierKey(identifier string) string {
+	return "identifier:" + strings.ToLower(strings.TrimSpace(identifier))
+}
+
+// clientIP returns the address of the client, as seen by the load balancer when there is one
+func clientIP(r *http.Request) string {
+	// the last entry is appended by our load balancer, earlier ones can be spoofed by the client
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		ips := strings.Split(forwarded, ",")
+		return strings.TrimSpace(ips[len(ips)-1])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// allowLoginAttempt records a login attempt for the client IP and the submitted identifier,
+// and tells whether both are within their configured rates
+func (ta *TruAPI) allowLoginAttempt(r *http.Request, identifier string) bool {
+	config := ta.APIContext.Config.LoginRateLimit
+	ipMaxAttempts := config.IPMaxAttempts
+	if ipMaxAttempts == 0 {
+		ipMaxAttempts = defaultLoginIPMaxAttempts
+	}
+	identifierMaxAttempts := config.IdentifierMaxAttempts
+	if identifierMaxAttempts == 0 {
+		identifierMaxAttempts = defaultLoginIdentifierMaxAttempts
+	}
+	window := time.Duration(config.Window) * time.Minute
+	if window == 0 {
+		window = defaultLoginRateLimitWindow
+	}
+
+	now := time.Now()
+	if !ta.loginRateLimiter.allow(loginIPKey(r), ipMaxAttempts, window, now) {
+		return false
+	}
+	return ta.loginRateLimiter.allow(loginIdentifierKey(identifier), identifierMaxAttempts, window, now)
+}
@@ -0,0 +1,54 @@
+package truapi
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginRateLimiter(t *testing.T) {
+	limiter := newLoginRateLimiter()
+	now := time.Now()
+	window := time.Minute
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, limiter.allow("ip:1.2.3.4", 3, window, now))
+	}
+	assert.False(t, limiter.allow("ip:1.2.3.4", 3, window, now))
+	// other keys are counted separately
+	assert.True(t, limiter.allow("ip:5.6.7.8", 3, window, now))
+	// a new window starts once the current one is over
+	assert.True(t, limiter.allow("ip:1.2.3.4", 3, window, now.Add(window)))
+
+	assert.True(t, limiter.allow("identifier:shane", 1, window, now))
+	assert.False(t, limiter.allow("identifier:shane", 1, window, now))
+	limiter.reset("identifier:shane")
+	assert.True(t, limiter.allow("identifier:shane", 1, window, now))
+}
+
+func TestLoginRateLimiterSweep(t *testing.T) {
+	limiter := newLoginRateLimiter()
+	now := time.Now()
+	window := time.Second
+
+	limiter.allow("ip:1.2.3.4", 3, window, now)
+	limiter.allow("ip:5.6.7.8", 3, window, now)
+	// the windows are over, but the last sweep is too recent to sweep them yet
+	limiter.allow("ip:9.9.9.9", 3, window, now.Add(2*window))
+	assert.Len(t, limiter.windows, 3)
+
+	limiter.allow("ip:9.9.9.9", 3, time.Hour, now.Add(loginRateLimiterSweepInterval))
+	assert.Len(t, limiter.windows, 1)
+}
+
+func TestLoginRateLimitKeys(t *testing.T) {
+	assert.Equal(t, loginIdentifierKey("shane@trustory.io"), loginIdentifierKey(" Shane@TruStory.io "))
+
+	r := httptest.NewRequest("POST", "/api/v1/users/authentication", nil)
+	r.RemoteAddr = "10.0.0.1:5555"
+	assert.Equal(t, "10.0.0.1", clientIP(r))
+	r.Header.Set("X-Forwarded-For", "1.1.1.1, 2.2.2.2")
+	assert.Equal(t, "2.2.2.2", clientIP(r))
+}
@@ -0,0 +1,89 @@
+package truapi
+
+import (
+	"sync"
+)
+
+const defaultNotificationsBufferSize = 100
+
+const defaultNotificationsOutboxSize = 1000
+
+// notificationOutbox holds notifications that could not be queued because the
+// sender channel was full, and delivers them once the sender catches up.
+// It holds at most capacity notifications, dropping the oldest ones past that.
+type notificationOutbox struct {
+	mu       sync.Mutex
+	pending  []interface{}
+	capacity int
+	dropped  int64
+	signal   chan struct{}
+}
+
+func newNotificationOutbox(capacity int) *notificationOutbox {
+	return &notificationOutbox{capacity: capacity, signal: make(chan struct{}, 1)}
+}
+
+// push adds a notification to the outbox without blocking, dropping the oldest one when full
+func (o *notificationOutbox) push(n interface{}) {
+	o.mu.Lock()
+	if len(o.pending) >= o.capacity {
+		o.pending = o.pending[1:]
+		o.dropped++
+	}
+	o.pending = append(o.pending, n)
+	o.mu.Unlock()
+	select {
+	case o.signal <- struct{}{}:
+	default:
+	}
+}
+
+// droppedCount returns the number of notifications dropped because the outbox was full
+func (o *notificationOutbox) droppedCount() int64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.dropped
+}
+
+// len returns the number of notifications waiting in the outbox
+func (o *notificationOutbox) len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.pending)
+}
+
+// run delivers pending notifications in order, blocking on deliver as needed
+func (o *notificationOutbox) run(deliver func(n interface{})) {
+	for range o.signal {
+		for {
+			o.mu.Lock()
+			if len(o.pending) == 0 {
+				o.mu.Unlock()
+				break
+			}
+			n := o.pending[0]
+			o.pending = o.pending[1:]
+			o.mu.Unlock()
+			deliver(n)
+		}
+	}
+}
+
+// NotificationsDepth represents the number of notifications waiting to be sent
+type NotificationsDepth struct {
+	Comments   int   `json:"comments"`
+	Broadcasts int   `json:"broadcasts"`
+	Reactions  int   `json:"reactions"`
+	Dropped    int64 `json:"dropped"`
+}
+
+// notificationsDepth returns the number of queued notifications, including the ones in the outboxes,
+// and how many were dropped from full outboxes
+func (ta *TruAPI) notificationsDepth() NotificationsDepth {
+	return NotificationsDepth{
+		Comments:   len(ta.commentsNotificationsCh) + ta.commentsOutbox.len(),
+		Broadcasts: len(ta.broadcastNotificationsCh) + ta.broadcastOutbox.len(),
+		Reactions:  len(ta.reactionsNotificationsCh) + ta.reactionsOutbox.len(),
+		Dropped:    ta.commentsOutbox.droppedCount() + ta.broadcastOutbox.droppedCount() + ta.reactionsOutbox.droppedCount(),
+	}
+}
@@ -0,0 +1,53 @@
+package truapi
+
+import (
+	"testing"
+)
+
+func TestNotificationOutbox(t *testing.T) {
+	outbox := newNotificationOutbox(10)
+	outbox.push(1)
+	outbox.push(2)
+	outbox.push(3)
+	if outbox.len() != 3 {
+		t.Fatalf("expected 3 pending notifications, got %d", outbox.len())
+	}
+
+	delivered := make(chan interface{})
+	go outbox.run(func(n interface{}) {
+		delivered <- n
+	})
+	for _, expected := range []int{1, 2, 3} {
+		n := <-delivered
+		if n.(int) != expected {
+			t.Errorf("expected notification %d, got %v", expected, n)
+		}
+	}
+	if outbox.len() != 0 {
+		t.Errorf("expected an empty outbox, got %d", outbox.len())
+	}
+}
+
+func TestNotificationOutboxDropsOldestWhenFull(t *testing.T) {
+	outbox := newNotificationOutbox(2)
+	outbox.push(1)
+	outbox.push(2)
+	outbox.push(3)
+	if outbox.len() != 2 {
+		t.Fatalf("expected 2 pending notifications, got %d", outbox.len())
+	}
+	if outbox.droppedCount() != 1 {
+		t.Errorf("expected 1 dropped notification, got %d", outbox.droppedCount())
+	}
+
+	delivered := make(chan interface{})
+	go outbox.run(func(n interface{}) {
+		delivered <- n
+	})
+	for _, expected := range []int{2, 3} {
+		n := <-delivered
+		if n.(int) != expected {
+			t.Errorf("expected notification %d, got %v", expected, n)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package truapi
+
+import (
+	"log"
+	"time"
+)
+
+const (
+	notificationsDefaultRetentionDays = 90
+	notificationsDefaultPruneInterval = 24 // hours
+)
+
+func (ta *TruAPI) notificationPruner() {
+	config := ta.APIContext.Config.Notifications
+	if !config.PruneEnabled {
+		log.Println("notification pruning is disabled")
+		return
+	}
+	retentionDays := notificationsDefaultRetentionDays
+	if config.RetentionDays > 0 {
+		retentionDays = config.RetentionDays
+	}
+	interval := notificationsDefaultPruneInterval
+	if config.PruneInterval > 0 {
+		interval = config.PruneInterval
+	}
+	log.Printf("notifications: pruning read notifications older than %d days every %d hours\n", retentionDays, interval)
+	ta.pruneNotifications(retentionDays, config.PruneDryRun)
+	ticker := time.NewTicker(time.Duration(interval) * time.Hour)
+	for range ticker.C {
+		ta.pruneNotifications(retentionDays, config.PruneDryRun)
+	}
+}
+
+func (ta *TruAPI) pruneNotifications(retentionDays int, dryRun bool) {
+	olderThan := time.Now().AddDate(0, 0, -retentionDays)
+	if dryRun {
+		count, err := ta.DBClient.CountPrunableNotificationEvents(olderThan)
+		if err != nil {
+			log.Println("an error occurred counting prunable notifications", err)
+			return
+		}
+		log.Printf("notifications: dry run, %d read notifications would be pruned\n", count)
+		return
+	}
+	count, err := ta.DBClient.PruneNotificationEvents(olderThan)
+	if err != nil {
+		log.Println("an error occurred pruning notifications", err)
+		return
+	}
+	log.Printf("notifications: pruned %d read notifications\n", count)
+}
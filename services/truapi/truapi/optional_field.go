@@ -0,0 +1,58 @@
+package truapi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// optionalField wraps a field resolver so that a failure while resolving it, such as a
+// DB error, is logged and resolves the field to null instead of failing the whole request.
+// Both panics and a non-nil trailing error return value are handled. The resolver must return
+// a nullable value, so that a failure can't be mistaken for a valid zero value.
+func optionalField(name string, resolver interface{}) interface{} {
+	fn := reflect.ValueOf(resolver)
+	fnType := fn.Type()
+	if fnType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("optionalField %s: resolver must be a function", name))
+	}
+	for i := 0; i < fnType.NumOut(); i++ {
+		if fnType.Out(i) != errorType && !isNullable(fnType.Out(i)) {
+			panic(fmt.Sprintf("optionalField %s: resolver must return a nullable value, not %s", name, fnType.Out(i)))
+		}
+	}
+	zeroResults := func() []reflect.Value {
+		results := make([]reflect.Value, fnType.NumOut())
+		for i := range results {
+			results[i] = reflect.Zero(fnType.Out(i))
+		}
+		return results
+	}
+
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) (results []reflect.Value) {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Printf("%s field resolver err: %v\n", name, r)
+				results = zeroResults()
+			}
+		}()
+		results = fn.Call(args)
+		last := fnType.NumOut() - 1
+		if last >= 0 && fnType.Out(last) == errorType && !results[last].IsNil() {
+			fmt.Printf("%s field resolver err: %v\n", name, results[last].Interface())
+			return zeroResults()
+		}
+		return results
+	}).Interface()
+}
+
+// isNullable tells whether the zero value of a type resolves to null
+func isNullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,108 @@
+package truapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TruStory/truchain/x/claim"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+func TestOptionalField(t *testing.T) {
+	panicking := optionalField("panicking", func(_ context.Context, q string) []string {
+		panic(errors.New("db is down"))
+	}).(func(context.Context, string) []string)
+	assert.Nil(t, panicking(context.Background(), "q"))
+
+	failing := optionalField("failing", func(_ context.Context, q string) (*string, error) {
+		return &q, errors.New("db is down")
+	}).(func(context.Context, string) (*string, error))
+	value, err := failing(context.Background(), "q")
+	assert.Nil(t, value)
+	assert.NoError(t, err)
+
+	working := optionalField("working", func(_ context.Context, q string) (*string, error) {
+		return &q, nil
+	}).(func(context.Context, string) (*string, error))
+	value, err = working(context.Background(), "q")
+	assert.NoError(t, err)
+	assert.Equal(t, "q", *value)
+
+	// a failure must not be mistaken for a count of 0
+	assert.Panics(t, func() {
+		optionalField("count", func(_ context.Context, q string) int { return len(q) })
+	})
+	count := optionalField("count", func(_ context.Context, q string) *int {
+		panic(errors.New("db is down"))
+	}).(func(context.Context, string) *int)
+	assert.Nil(t, count(context.Background(), "q"))
+}
+
+type fakeOptionalFieldStore struct {
+	db.Datastore
+	err      error
+	comments []db.Comment
+}
+
+func (s *fakeOptionalFieldStore) ClaimLevelComments(claimID uint64) ([]db.Comment, error) {
+	return s.comments, s.err
+}
+
+func (s *fakeOptionalFieldStore) UserByAddress(address string) (*db.User, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	user := &db.User{Address: address}
+	user.Meta.Journey = []db.UserJourneyStep{db.JourneyStepSignedUp}
+	return user, nil
+}
+
+func (s *fakeOptionalFieldStore) UsersByAddress(addresses []string) ([]db.User, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	users := make([]db.User, 0, len(addresses))
+	for _, address := range addresses {
+		users = append(users, db.User{Address: address, Username: "alice"})
+	}
+	return users, nil
+}
+
+func TestOptionalFieldsResolveToNullWhenTheDatabaseFails(t *testing.T) {
+	store := &fakeOptionalFieldStore{err: errors.New("db is down"), comments: []db.Comment{{ID: 1}}}
+	ta := &TruAPI{DBClient: store}
+	ctx := ta.createContext(context.Background())
+	comments := optionalField("Claim.comments", ta.claimCommentsResolver).(func(context.Context, claim.Claim) ([]db.Comment, error))
+	commentCount := optionalField("Claim.commentCount", ta.claimCommentCountResolver).(func(context.Context, claim.Claim) (*int, error))
+	userProfile := optionalField("AppAccount.userProfile", ta.appAccountUserProfileResolver).(func(context.Context, AppAccount) (*db.UserProfile, error))
+	userJourney := optionalField("AppAccount.userJourney", ta.userJourneyResolver).(func(context.Context, AppAccount) ([]db.UserJourneyStep, error))
+	account := AppAccount{Address: "cosmos1alice"}
+
+	claimComments, err := comments(ctx, claim.Claim{ID: 1})
+	assert.NoError(t, err)
+	assert.Nil(t, claimComments)
+	count, err := commentCount(ctx, claim.Claim{ID: 1})
+	assert.NoError(t, err)
+	assert.Nil(t, count)
+	profile, err := userProfile(ctx, account)
+	assert.NoError(t, err)
+	assert.Nil(t, profile)
+	journey, err := userJourney(ctx, account)
+	assert.NoError(t, err)
+	assert.Nil(t, journey)
+
+	// the same fields resolve normally once the database is back
+	store.err = nil
+	ctx = ta.createContext(context.Background())
+	claimComments, _ = comments(ctx, claim.Claim{ID: 1})
+	assert.Len(t, claimComments, 1)
+	count, _ = commentCount(ctx, claim.Claim{ID: 1})
+	assert.Equal(t, 1, *count)
+	profile, _ = userProfile(ctx, account)
+	assert.Equal(t, "alice", profile.Username)
+	journey, _ = userJourney(ctx, account)
+	assert.Equal(t, []db.UserJourneyStep{db.JourneyStepSignedUp}, journey)
+}
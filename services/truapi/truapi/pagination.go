@@ -0,0 +1,20 @@
+package truapi
+
+// pageBounds returns the bounds of a page in a list of the given length. Negative offsets
+// and limits are treated as 0, and the page is empty when the offset is past the end.
+func pageBounds(length, offset, limit int) (start, end int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	if offset >= length {
+		return length, length
+	}
+	end = offset + limit
+	if end > length {
+		end = length
+	}
+	return offset, end
+}
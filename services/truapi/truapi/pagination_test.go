@@ -0,0 +1,22 @@
+package truapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageBounds(t *testing.T) {
+	start, end := pageBounds(5, 1, 2)
+	assert.Equal(t, []int{1, 3}, []int{start, end})
+	start, end = pageBounds(5, 4, 2)
+	assert.Equal(t, []int{4, 5}, []int{start, end})
+	start, end = pageBounds(5, 7, 2)
+	assert.Equal(t, []int{5, 5}, []int{start, end})
+
+	// negative values are clamped to 0
+	start, end = pageBounds(5, -3, 2)
+	assert.Equal(t, []int{0, 2}, []int{start, end})
+	start, end = pageBounds(5, 1, -2)
+	assert.Equal(t, []int{1, 1}, []int{start, end})
+}
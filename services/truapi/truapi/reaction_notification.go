@@ -0,0 +1,52 @@
+package truapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func (ta *TruAPI) sendReactionNotification(n ReactionNotificationRequest) {
+	if !ta.notificationsInitialized || ta.reactionsNotificationsCh == nil {
+		return
+	}
+	// never block the caller on notification delivery, overflow goes to the outbox
+	select {
+	case ta.reactionsNotificationsCh <- n:
+	default:
+		ta.reactionsOutbox.push(n)
+	}
+}
+
+func (ta *TruAPI) runReactionNotificationSender(notifications <-chan ReactionNotificationRequest, endpoint string) {
+	url := fmt.Sprintf("%s/%s", strings.TrimRight(strings.TrimSpace(endpoint), "/"), "sendReactionNotification")
+
+	for n := range notifications {
+		b, err := json.Marshal(&n)
+		if err != nil {
+			fmt.Println("error encoding reaction notification request", err)
+			continue
+		}
+		request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(b))
+		if err != nil {
+			fmt.Println("error creating http request", err)
+			continue
+		}
+		request.Header.Add("Accept", "application/json")
+		request.Header.Add("Content-Type", "application/json")
+		resp, err := ta.httpClient.Do(request)
+		if err != nil {
+			fmt.Println("error sending reaction notification request", err)
+			continue
+		}
+		// only read the status
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			fmt.Printf("error sending reaction notification request status [%s] \n", resp.Status)
+			continue
+		}
+		fmt.Printf("reaction notification sent id[%d]\n", n.ID)
+	}
+}
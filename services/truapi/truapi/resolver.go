@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/TruStory/octopus/services/truapi/db"
@@ -25,6 +26,7 @@ import (
 	"github.com/julianshen/og"
 	tcmn "github.com/tendermint/tendermint/libs/common"
 	stripmd "github.com/writeas/go-strip-markdown"
+	"golang.org/x/sync/singleflight"
 )
 
 type queryByCommunityID struct {
@@ -70,12 +72,40 @@ type queryByCommunityIDAndFeedFilter struct {
 	CommunityID string     `graphql:"communityId,optional"`
 	FeedFilter  FeedFilter `graphql:"feedFilter,optional"`
 	IsSearch    bool       `graphql:"isSearch,optional"`
+	HasMedia    bool       `graphql:"hasMedia,optional"`
+	// CreatedAfter keeps the claims created at or after this time
+	CreatedAfter *time.Time `graphql:"createdAfter,optional"`
+	// CreatedBefore keeps the claims created strictly before this time
+	CreatedBefore *time.Time `graphql:"createdBefore,optional"`
+}
+
+type queryUnreadCommentsCountParams struct {
+	ClaimID int64 `graphql:"claimId"`
+}
+
+type queryAppAccountEarningsBetweenParams struct {
+	Address string `graphql:"address"`
+	// From is inclusive
+	From time.Time `graphql:"from"`
+	// To is exclusive, and defaults to now
+	To *time.Time `graphql:"to,optional"`
 }
 
 type queryReferredAppAccountsParams struct {
 	Admin bool `graphql:"admin,optional"`
 }
 
+const (
+	defaultReferralTreeDepth = 3
+	maxReferralTreeDepth     = 10
+)
+
+type queryReferralTreeParams struct {
+	// UserID lets admins look at the referral tree of any user
+	UserID   int64 `graphql:"userId,optional"`
+	MaxDepth int64 `graphql:"maxDepth,optional"`
+}
+
 // claimMetricsBest represents all-time claim metrics
 type claimMetricsBest struct {
 	Claim                 claim.Claim
@@ -116,7 +146,7 @@ func (ta *TruAPI) accountQuery(ctx context.Context, addrStr string) (authexporte
 	if err != nil {
 		return nil, err
 	}
-	res, err := ta.Query(queryRoute, auth.QueryAccountParams{Address: addr}, auth.ModuleCdc)
+	res, err := ta.QueryWithContext(ctx, queryRoute, auth.QueryAccountParams{Address: addr}, auth.ModuleCdc)
 	if err != nil {
 		fmt.Println("accountResolver err: ", err)
 		return nil, err
@@ -132,7 +162,7 @@ func (ta *TruAPI) accountQuery(ctx context.Context, addrStr string) (authexporte
 
 func (ta *TruAPI) appAccountsResolver(ctx context.Context, addresses []sdk.AccAddress) ([]*AppAccount, error) {
 	queryRoute := path.Join(account.QuerierRoute, account.QueryPrimaryAccounts)
-	res, err := ta.Query(queryRoute, account.QueryPrimaryAccountsParams{Addresses: addresses}, account.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, account.QueryPrimaryAccountsParams{Addresses: addresses}, account.ModuleCodec)
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +237,27 @@ func (ta *TruAPI) userProfileResolver(ctx context.Context, addr string) *db.User
 	return profile
 }
 
+// appAccountUserProfileResolver returns the profile of an account, failing when it can't be loaded
+func (ta *TruAPI) appAccountUserProfileResolver(ctx context.Context, q AppAccount) (*db.UserProfile, error) {
+	loaders, ok := getDataLoaders(ctx)
+	if !ok {
+		return ta.DBClient.UserProfileByAddress(q.Address)
+	}
+	return loaders.userProfileLoader.Load(q.Address)
+}
+
+// userJourneyResolver returns the journey of an account, failing when the user can't be loaded
+func (ta *TruAPI) userJourneyResolver(ctx context.Context, q AppAccount) ([]db.UserJourneyStep, error) {
+	user, err := ta.DBClient.UserByAddress(q.Address)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return []db.UserJourneyStep{}, nil
+	}
+	return user.Meta.Journey, nil
+}
+
 func (ta *TruAPI) userResolver(ctx context.Context, addr string) *db.User {
 	user, err := ta.DBClient.UserByAddress(addr)
 	if err != nil {
@@ -234,7 +285,7 @@ func (ta *TruAPI) earnedStakeResolver(ctx context.Context, q queryByAddress) []E
 	}
 
 	queryRoute := path.Join(staking.QuerierRoute, staking.QueryEarnedCoins)
-	res, err := ta.Query(queryRoute, staking.QueryEarnedCoinsParams{Address: address}, staking.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryEarnedCoinsParams{Address: address}, staking.ModuleCodec)
 	if err != nil {
 		fmt.Println("earnedStakeResolver err: ", err)
 		return []EarnedCoin{}
@@ -268,7 +319,7 @@ func (ta *TruAPI) pendingBalanceResolver(ctx context.Context, q queryByAddress)
 	}
 
 	queryRoute := path.Join(staking.QuerierRoute, staking.QueryUserStakes)
-	res, err := ta.Query(queryRoute, staking.QueryUserStakesParams{Address: address}, staking.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryUserStakesParams{Address: address}, staking.ModuleCodec)
 	if err != nil {
 		fmt.Println("pendingBalanceResolver err: ", err)
 		return sdk.Coin{}
@@ -303,7 +354,7 @@ func (ta *TruAPI) pendingStakeResolver(ctx context.Context, q queryByAddress) []
 
 	for _, community := range communities {
 		queryRoute := path.Join(staking.QuerierRoute, staking.QueryUserCommunityStakes)
-		res, err := ta.Query(queryRoute, staking.QueryUserCommunityStakesParams{Address: address, CommunityID: community.ID}, staking.ModuleCodec)
+		res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryUserCommunityStakesParams{Address: address, CommunityID: community.ID}, staking.ModuleCodec)
 		if err != nil {
 			fmt.Println("pendingStakeResolver err: ", err)
 			return []EarnedCoin{}
@@ -337,7 +388,7 @@ func (ta *TruAPI) pendingStakeResolver(ctx context.Context, q queryByAddress) []
 
 func (ta *TruAPI) communitiesResolver(ctx context.Context) []community.Community {
 	queryRoute := path.Join(community.QuerierRoute, community.QueryCommunities)
-	res, err := ta.Query(queryRoute, struct{}{}, community.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, struct{}{}, community.ModuleCodec)
 	if err != nil {
 		fmt.Println("communitiesResolver err: ", err)
 		return []community.Community{}
@@ -368,7 +419,7 @@ func (ta *TruAPI) communitiesResolver(ctx context.Context) []community.Community
 
 func (ta *TruAPI) communityResolver(ctx context.Context, q queryByCommunityID) *community.Community {
 	queryRoute := path.Join(community.QuerierRoute, community.QueryCommunity)
-	res, err := ta.Query(queryRoute, community.QueryCommunityParams{ID: q.CommunityID}, community.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, community.QueryCommunityParams{ID: q.CommunityID}, community.ModuleCodec)
 	if err != nil {
 		fmt.Println("getCommunityByIDResolver err: ", err)
 		return nil
@@ -382,6 +433,24 @@ func (ta *TruAPI) communityResolver(ctx context.Context, q queryByCommunityID) *
 	return c
 }
 
+// defaultBetaCommunityLabel is appended to the name of beta communities unless configured otherwise
+const defaultBetaCommunityLabel = " (Beta)"
+
+func (ta *TruAPI) communityNameResolver(ctx context.Context, q community.Community) string {
+	if !ta.isBetaCommunityResolver(ctx, q) {
+		return q.Name
+	}
+	betaLabel := ta.APIContext.Config.Community.BetaLabel
+	if betaLabel == "" {
+		betaLabel = defaultBetaCommunityLabel
+	}
+	return q.Name + betaLabel
+}
+
+func (ta *TruAPI) isBetaCommunityResolver(_ context.Context, q community.Community) bool {
+	return contains(ta.APIContext.Config.Community.BetaCommunities, q.ID)
+}
+
 func (ta *TruAPI) communityIconImageResolver(ctx context.Context, q community.Community) CommunityIconImage {
 	return CommunityIconImage{
 		Regular: joinPath(ta.APIContext.Config.App.S3AssetsURL, fmt.Sprintf("communities/%s_icon_normal.png", q.ID)),
@@ -411,18 +480,18 @@ func (ta *TruAPI) claimsResolver(ctx context.Context, q queryByCommunityIDAndFee
 
 	switch q.CommunityID {
 	case "all":
-		queryRoute := path.Join(claim.QuerierRoute, claim.QueryClaims)
-		res, err = ta.Query(queryRoute, struct{}{}, claim.ModuleCodec)
+		queryRoute, params := allClaimsQuery(q.CreatedAfter, q.CreatedBefore)
+		res, err = ta.QueryWithContext(ctx, queryRoute, params, claim.ModuleCodec)
 	case "home":
 		communityIDs, cErr := ta.followedCommunityIDs(ctx)
 		if cErr != nil {
 			return []claim.Claim{}
 		}
 		queryRoute := path.Join(claim.QuerierRoute, claim.QueryCommunitiesClaims)
-		res, err = ta.Query(queryRoute, claim.QueryCommunitiesClaimsParams{CommunityIDs: communityIDs}, claim.ModuleCodec)
+		res, err = ta.QueryWithContext(ctx, queryRoute, claim.QueryCommunitiesClaimsParams{CommunityIDs: communityIDs}, claim.ModuleCodec)
 	default:
 		queryRoute := path.Join(claim.QuerierRoute, claim.QueryCommunityClaims)
-		res, err = ta.Query(queryRoute, claim.QueryCommunityClaimsParams{CommunityID: q.CommunityID}, claim.ModuleCodec)
+		res, err = ta.QueryWithContext(ctx, queryRoute, claim.QueryCommunityClaimsParams{CommunityID: q.CommunityID}, claim.ModuleCodec)
 	}
 
 	if err != nil {
@@ -435,6 +504,7 @@ func (ta *TruAPI) claimsResolver(ctx context.Context, q queryByCommunityIDAndFee
 	if err != nil {
 		panic(err)
 	}
+	claims = claimsCreatedBetween(claims, q.CreatedAfter, q.CreatedBefore)
 
 	if !q.IsSearch {
 		claims = ta.removeClaimOfTheDay(claims, q.CommunityID)
@@ -446,14 +516,54 @@ func (ta *TruAPI) claimsResolver(ctx context.Context, q queryByCommunityIDAndFee
 		panic(err)
 	}
 
+	if q.HasMedia {
+		unflaggedClaims, err = ta.filterClaimsWithMedia(unflaggedClaims)
+		if err != nil {
+			fmt.Println("filterClaimsWithMedia err: ", err)
+			return []claim.Claim{}
+		}
+	}
+
 	filteredClaims := ta.filterFeedClaims(ctx, unflaggedClaims, q.FeedFilter)
 
 	return filteredClaims
 }
 
+// allClaimsQuery returns the chain query for the claims of all communities, narrowed down by
+// creation time when a bound is given. The chain can only narrow down by one bound, the other
+// one and the community queries being left to claimsCreatedBetween.
+func allClaimsQuery(createdAfter, createdBefore *time.Time) (string, interface{}) {
+	switch {
+	case createdAfter != nil:
+		return path.Join(claim.QuerierRoute, claim.QueryClaimsAfterTime), claim.QueryClaimsTimeParams{CreatedTime: *createdAfter}
+	case createdBefore != nil:
+		return path.Join(claim.QuerierRoute, claim.QueryClaimsBeforeTime), claim.QueryClaimsTimeParams{CreatedTime: *createdBefore}
+	default:
+		return path.Join(claim.QuerierRoute, claim.QueryClaims), struct{}{}
+	}
+}
+
+// claimsCreatedBetween keeps the claims created at or after createdAfter and before createdBefore, when given
+func claimsCreatedBetween(claims []claim.Claim, createdAfter, createdBefore *time.Time) []claim.Claim {
+	if createdAfter == nil && createdBefore == nil {
+		return claims
+	}
+	filtered := make([]claim.Claim, 0, len(claims))
+	for _, c := range claims {
+		if createdAfter != nil && c.CreatedTime.Before(*createdAfter) {
+			continue
+		}
+		if createdBefore != nil && !c.CreatedTime.Before(*createdBefore) {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered
+}
+
 func (ta *TruAPI) claimResolver(ctx context.Context, q queryByClaimID) claim.Claim {
 	queryRoute := path.Join(claim.QuerierRoute, claim.QueryClaim)
-	res, err := ta.Query(queryRoute, claim.QueryClaimParams{ID: q.ID}, claim.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, claim.QueryClaimParams{ID: q.ID}, claim.ModuleCodec)
 	if err != nil {
 		fmt.Println("claimResolver err: ", err)
 		return claim.Claim{}
@@ -518,9 +628,30 @@ func (ta *TruAPI) filterFlaggedClaims(claims []claim.Claim) ([]claim.Claim, erro
 	return unflaggedClaims, nil
 }
 
+func (ta *TruAPI) filterClaimsWithMedia(claims []claim.Claim) ([]claim.Claim, error) {
+	claimsWithMedia := make([]claim.Claim, 0)
+
+	claimIDsWithMedia, err := ta.DBClient.ClaimIDsWithMedia()
+	if err != nil {
+		return claims, err
+	}
+	hasMedia := make(map[int64]bool, len(claimIDsWithMedia))
+	for _, claimID := range claimIDsWithMedia {
+		hasMedia[claimID] = true
+	}
+
+	for _, claim := range claims {
+		if hasMedia[int64(claim.ID)] {
+			claimsWithMedia = append(claimsWithMedia, claim)
+		}
+	}
+
+	return claimsWithMedia, nil
+}
+
 func (ta *TruAPI) claimArgumentsResolver(ctx context.Context, q queryClaimArgumentParams) []staking.Argument {
 	queryRoute := path.Join(staking.ModuleName, staking.QueryClaimArguments)
-	res, err := ta.Query(queryRoute, staking.QueryClaimArgumentsParams{ClaimID: q.ClaimID}, staking.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryClaimArgumentsParams{ClaimID: q.ClaimID}, staking.ModuleCodec)
 	if err != nil {
 		fmt.Println("claimArgumentsResolver err: ", err)
 		return []staking.Argument{}
@@ -580,7 +711,7 @@ func (ta *TruAPI) claimArgumentsResolver(ctx context.Context, q queryClaimArgume
 
 func (ta *TruAPI) claimArgumentResolver(ctx context.Context, q queryByArgumentID) *staking.Argument {
 	queryRoute := path.Join(staking.ModuleName, staking.QueryClaimArgument)
-	res, err := ta.Query(queryRoute, staking.QueryClaimArgumentParams{ArgumentID: q.ID}, staking.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryClaimArgumentParams{ArgumentID: q.ID}, staking.ModuleCodec)
 	if err != nil {
 		fmt.Println("claimArgumentResolver err: ", err)
 		return nil
@@ -597,7 +728,7 @@ func (ta *TruAPI) claimArgumentResolver(ctx context.Context, q queryByArgumentID
 
 func (ta *TruAPI) topArgumentResolver(ctx context.Context, q claim.Claim) *staking.Argument {
 	queryRoute := path.Join(staking.ModuleName, staking.QueryClaimTopArgument)
-	res, err := ta.Query(queryRoute, staking.QueryClaimTopArgumentParams{ClaimID: q.ID}, staking.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryClaimTopArgumentParams{ClaimID: q.ID}, staking.ModuleCodec)
 	if err != nil {
 		fmt.Println("topArgumentResolver err: ", err)
 		return nil
@@ -671,7 +802,7 @@ func (ta *TruAPI) claimParticipantsResolver(ctx context.Context, q claim.Claim)
 
 func (ta *TruAPI) stakeResolver(ctx context.Context, q queryByStakeID) *staking.Stake {
 	queryRoute := path.Join(staking.ModuleName, staking.QueryStake)
-	res, err := ta.Query(queryRoute, staking.QueryStakeParams{StakeID: q.ID}, staking.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryStakeParams{StakeID: q.ID}, staking.ModuleCodec)
 	if err != nil {
 		fmt.Println("stakeResolver err: ", err)
 		return nil
@@ -688,7 +819,7 @@ func (ta *TruAPI) stakeResolver(ctx context.Context, q queryByStakeID) *staking.
 
 func (ta *TruAPI) claimArgumentStakesResolver(ctx context.Context, q staking.Argument) []staking.Stake {
 	queryRoute := path.Join(staking.ModuleName, staking.QueryArgumentStakes)
-	res, err := ta.Query(queryRoute, staking.QueryArgumentStakesParams{ArgumentID: q.ID}, staking.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryArgumentStakesParams{ArgumentID: q.ID}, staking.ModuleCodec)
 	if err != nil {
 		fmt.Println("claimArgumentStakesResolver err: ", err)
 		return []staking.Stake{}
@@ -706,7 +837,7 @@ func (ta *TruAPI) claimArgumentStakesResolver(ctx context.Context, q staking.Arg
 
 func (ta *TruAPI) slashResolver(ctx context.Context, q queryBySlashID) *slashing.Slash {
 	queryRoute := path.Join(slashing.ModuleName, slashing.QuerySlash)
-	res, err := ta.Query(queryRoute, slashing.QuerySlashParams{ID: q.ID}, slashing.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, slashing.QuerySlashParams{ID: q.ID}, slashing.ModuleCodec)
 	if err != nil {
 		fmt.Println("slashResolver err: ", err)
 		return nil
@@ -721,6 +852,39 @@ func (ta *TruAPI) slashResolver(ctx context.Context, q queryBySlashID) *slashing
 	return slash
 }
 
+type queryFlaggedCommentsParams struct {
+	Days  int64 `graphql:"days,optional"`
+	Limit int64 `graphql:"limit,optional"`
+}
+
+func (ta *TruAPI) keywordFlaggedCommentsResolver(ctx context.Context, q queryFlaggedCommentsParams) []db.Comment {
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok {
+		return make([]db.Comment, 0)
+	}
+
+	settings := ta.settingsResolver(ctx)
+	if !contains(settings.ClaimAdmins, user.Address) {
+		return make([]db.Comment, 0)
+	}
+
+	days := q.Days
+	if days == 0 {
+		days = 7
+	}
+	limit := int(q.Limit)
+	if limit == 0 {
+		limit = 100
+	}
+	from := time.Now().AddDate(0, 0, -int(days))
+	comments, err := ta.DBClient.CommentsMatchingKeywords(ta.APIContext.Config.Moderation.Keywords, from, limit)
+	if err != nil {
+		fmt.Println("keywordFlaggedCommentsResolver err: ", err)
+		return make([]db.Comment, 0)
+	}
+	return comments
+}
+
 func (ta *TruAPI) slashesResolver(ctx context.Context) []slashing.Slash {
 	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
 	if !ok {
@@ -733,7 +897,7 @@ func (ta *TruAPI) slashesResolver(ctx context.Context) []slashing.Slash {
 	}
 
 	queryRoute := path.Join(slashing.ModuleName, slashing.QuerySlashes)
-	res, err := ta.Query(queryRoute, struct{}{}, slashing.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, struct{}{}, slashing.ModuleCodec)
 	if err != nil {
 		fmt.Println("slashesResolver err: ", err)
 		return nil
@@ -751,7 +915,7 @@ func (ta *TruAPI) slashesResolver(ctx context.Context) []slashing.Slash {
 
 func (ta *TruAPI) claimArgumentSlashesResolver(ctx context.Context, q staking.Argument) []slashing.Slash {
 	queryRoute := path.Join(slashing.ModuleName, slashing.QueryArgumentSlashes)
-	res, err := ta.Query(queryRoute, slashing.QueryArgumentSlashesParams{ArgumentID: q.ID}, slashing.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, slashing.QueryArgumentSlashesParams{ArgumentID: q.ID}, slashing.ModuleCodec)
 	if err != nil {
 		fmt.Println("claimArgumentSlashesResolver err: ", err)
 		return []slashing.Slash{}
@@ -791,6 +955,104 @@ func (ta *TruAPI) appAccountStakeResolver(ctx context.Context, q staking.Argumen
 	return nil
 }
 
+// claimStakesCache memoizes the stakes of claims for the duration of a request.
+// Stakes are queried without holding the lock, and concurrent lookups of the same
+// claim share a single query.
+type claimStakesCache struct {
+	mu      sync.Mutex
+	stakes  map[uint64][]staking.Stake
+	queries singleflight.Group
+}
+
+// get returns the memoized stakes of a claim, fetching them on the first lookup
+func (c *claimStakesCache) get(claimID uint64, fetch func() []staking.Stake) []staking.Stake {
+	c.mu.Lock()
+	stakes, ok := c.stakes[claimID]
+	c.mu.Unlock()
+	if ok {
+		return stakes
+	}
+	fetched, _, _ := c.queries.Do(strconv.FormatUint(claimID, 10), func() (interface{}, error) {
+		stakes := fetch()
+		c.mu.Lock()
+		c.stakes[claimID] = stakes
+		c.mu.Unlock()
+		return stakes, nil
+	})
+	return fetched.([]staking.Stake)
+}
+
+// memoizedClaimStakes returns the stakes of a claim, querying them at most once per request
+func (ta *TruAPI) memoizedClaimStakes(ctx context.Context, claimID uint64) []staking.Stake {
+	l, ok := getDataLoaders(ctx)
+	if !ok || l.claimStakes == nil {
+		return ta.claimStakesResolver(ctx, claim.Claim{ID: claimID})
+	}
+	return l.claimStakes.get(claimID, func() []staking.Stake {
+		return ta.claimStakesResolver(ctx, claim.Claim{ID: claimID})
+	})
+}
+
+func (ta *TruAPI) viewerHasStakedResolver(ctx context.Context, q claim.Claim) bool {
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil {
+		return false
+	}
+	for _, stake := range ta.memoizedClaimStakes(ctx, q.ID) {
+		if stake.Creator.String() == user.Address {
+			return true
+		}
+	}
+	return false
+}
+
+func (ta *TruAPI) viewerArgumentResolver(ctx context.Context, q claim.Claim) *staking.Argument {
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil {
+		return nil
+	}
+	arguments := ta.claimArgumentsResolver(ctx, queryClaimArgumentParams{ClaimID: q.ID, Address: &user.Address, Filter: ArgumentCreated})
+	if len(arguments) == 0 {
+		return nil
+	}
+	return &arguments[0]
+}
+
+func (ta *TruAPI) viewerStakeResolver(ctx context.Context, q staking.Argument) *staking.Stake {
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil {
+		return nil
+	}
+	for _, stake := range ta.memoizedClaimStakes(ctx, q.ClaimID) {
+		if stake.ArgumentID == q.ID && stake.Creator.String() == user.Address {
+			return &stake
+		}
+	}
+	return nil
+}
+
+func (ta *TruAPI) argumentReactionCountsResolver(_ context.Context, q staking.Argument) []db.ReactionsCount {
+	counts, err := ta.DBClient.ReactionCountsByArgumentID(int64(q.ID))
+	if err != nil {
+		fmt.Println("argumentReactionCountsResolver err: ", err)
+		return make([]db.ReactionsCount, 0)
+	}
+	return counts
+}
+
+func (ta *TruAPI) argumentMyReactionsResolver(ctx context.Context, q staking.Argument) []db.Reaction {
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil {
+		return make([]db.Reaction, 0)
+	}
+	reactions, err := ta.DBClient.ReactionsByAddressAndReactionable(user.Address, db.Reactionable{Type: db.Argument, ID: int64(q.ID)})
+	if err != nil {
+		fmt.Println("argumentMyReactionsResolver err: ", err)
+		return make([]db.Reaction, 0)
+	}
+	return reactions
+}
+
 func (ta *TruAPI) appAccountSlashResolver(ctx context.Context, q staking.Argument) *slashing.Slash {
 	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
 	if ok {
@@ -822,7 +1084,85 @@ func (ta *TruAPI) commentsResolver(ctx context.Context, q queryCommentsParams) [
 			fmt.Println("commentsResolver err: ", err)
 		}
 	}
-	return comments
+	return visibleComments(comments)
+}
+
+// claimCommentsResolver returns the visible comments of a claim, failing when they can't be loaded
+func (ta *TruAPI) claimCommentsResolver(ctx context.Context, q claim.Claim) ([]db.Comment, error) {
+	comments, err := ta.DBClient.ClaimLevelComments(q.ID)
+	if err != nil {
+		return nil, err
+	}
+	return visibleComments(comments), nil
+}
+
+// claimCommentCountResolver counts the comments of a claim that aren't deleted, failing when they can't be loaded
+func (ta *TruAPI) claimCommentCountResolver(ctx context.Context, q claim.Claim) (*int, error) {
+	comments, err := ta.DBClient.ClaimLevelComments(q.ID)
+	if err != nil {
+		return nil, err
+	}
+	commentCount := 0
+	for _, comment := range comments {
+		if comment.DeletedAt == nil {
+			commentCount++
+		}
+	}
+	return &commentCount, nil
+}
+
+// unreadCommentsCountResolver counts the comments of a claim the authenticated user hasn't seen yet
+func (ta *TruAPI) unreadCommentsCountResolver(ctx context.Context, q queryUnreadCommentsCountParams) int {
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil {
+		return 0
+	}
+	count, err := ta.DBClient.UnreadCommentsCount(q.ClaimID, user.Address)
+	if err != nil {
+		fmt.Println("unreadCommentsCountResolver err: ", err)
+		return 0
+	}
+	return count
+}
+
+// markClaimCommentsSeen records that the authenticated user has seen the comments of a claim
+func (ta *TruAPI) markClaimCommentsSeen(ctx context.Context, claimID int64) error {
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil {
+		return Err401NotAuthenticated
+	}
+	return ta.DBClient.MarkClaimCommentsSeen(claimID, user.Address)
+}
+
+// visibleComments leaves out deleted comments, except the ones with live replies below them.
+// These are kept as redacted placeholders so replies still point to a parent the client receives.
+func visibleComments(comments []db.Comment) []db.Comment {
+	parents := make(map[int64]int64, len(comments))
+	for _, comment := range comments {
+		parents[comment.ID] = comment.ParentID
+	}
+	kept := make(map[int64]bool, len(comments))
+	for _, comment := range comments {
+		if comment.DeletedAt != nil {
+			continue
+		}
+		for id := comment.ID; id != 0 && !kept[id]; id = parents[id] {
+			kept[id] = true
+		}
+	}
+
+	visible := make([]db.Comment, 0, len(comments))
+	for _, comment := range comments {
+		if !kept[comment.ID] {
+			continue
+		}
+		if comment.DeletedAt != nil {
+			comment.Body = DeletedCommentBody
+			comment.Creator = ""
+		}
+		visible = append(visible, comment)
+	}
+	return visible
 }
 
 func (ta *TruAPI) claimQuestionsResolver(ctx context.Context, q queryByClaimID) []db.Question {
@@ -833,6 +1173,63 @@ func (ta *TruAPI) claimQuestionsResolver(ctx context.Context, q queryByClaimID)
 	return questions
 }
 
+func (ta *TruAPI) claimTagsResolver(ctx context.Context, q claim.Claim) []string {
+	claimTags, err := ta.DBClient.TagsByClaimID(int64(q.ID))
+	if err != nil {
+		fmt.Println("claimTagsResolver err: ", err)
+		return []string{}
+	}
+	tags := make([]string, 0, len(claimTags))
+	for _, claimTag := range claimTags {
+		tags = append(tags, claimTag.Tag)
+	}
+	return tags
+}
+
+type queryByTagAndCommunityID struct {
+	Tag         string `graphql:"tag"`
+	CommunityID string `graphql:"communityId,optional"`
+}
+
+func (ta *TruAPI) claimsByTagResolver(ctx context.Context, q queryByTagAndCommunityID) []claim.Claim {
+	claimIDs, err := ta.DBClient.ClaimIDsByTag(q.Tag)
+	if err != nil {
+		fmt.Println("claimsByTagResolver err: ", err)
+		return []claim.Claim{}
+	}
+	sort.Slice(claimIDs, func(i, j int) bool { return claimIDs[i] > claimIDs[j] })
+
+	claims := make([]claim.Claim, 0, len(claimIDs))
+	for _, claimID := range claimIDs {
+		c := ta.claimResolver(ctx, queryByClaimID{ID: uint64(claimID)})
+		if c.ID == 0 {
+			continue
+		}
+		if q.CommunityID != "" && q.CommunityID != "all" && c.CommunityID != q.CommunityID {
+			continue
+		}
+		claims = append(claims, c)
+	}
+	return claims
+}
+
+func (ta *TruAPI) featuredClaimsResolver(ctx context.Context, q queryByCommunityID) []claim.Claim {
+	featuredClaims, err := ta.DBClient.ActiveFeaturedClaims(q.CommunityID)
+	if err != nil {
+		fmt.Println("featuredClaimsResolver err: ", err)
+		return []claim.Claim{}
+	}
+	claims := make([]claim.Claim, 0, len(featuredClaims))
+	for _, featuredClaim := range featuredClaims {
+		c := ta.claimResolver(ctx, queryByClaimID{ID: uint64(featuredClaim.ClaimID)})
+		if c.ID == 0 {
+			continue
+		}
+		claims = append(claims, c)
+	}
+	return claims
+}
+
 func (ta *TruAPI) appAccountClaimsCreatedResolver(ctx context.Context, q queryByAddress) []claim.Claim {
 	creator, err := sdk.AccAddressFromBech32(q.ID)
 	if err != nil {
@@ -841,7 +1238,7 @@ func (ta *TruAPI) appAccountClaimsCreatedResolver(ctx context.Context, q queryBy
 	}
 
 	queryRoute := path.Join(claim.QuerierRoute, claim.QueryCreatorClaims)
-	res, err := ta.Query(queryRoute, claim.QueryCreatorClaimsParams{Creator: creator}, claim.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, claim.QueryCreatorClaimsParams{Creator: creator}, claim.ModuleCodec)
 	if err != nil {
 		return []claim.Claim{}
 	}
@@ -869,7 +1266,7 @@ func (ta *TruAPI) appAccountArgumentsResolver(ctx context.Context, q queryByAddr
 	}
 
 	queryRoute := path.Join(staking.QuerierRoute, staking.QueryUserArguments)
-	res, err := ta.Query(queryRoute, staking.QueryUserArgumentsParams{Address: creator}, staking.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryUserArgumentsParams{Address: creator}, staking.ModuleCodec)
 	if err != nil {
 		fmt.Println("appAccountArguments err: ", err)
 		return []staking.Argument{}
@@ -904,7 +1301,7 @@ func (ta *TruAPI) appAccountClaimsWithArgumentsResolver(ctx context.Context, q q
 	})
 
 	queryRoute := path.Join(claim.QuerierRoute, claim.QueryClaimsByIDs)
-	res, err := ta.Query(queryRoute, claim.QueryClaimsParams{IDs: claimIDsWithArgument}, claim.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, claim.QueryClaimsParams{IDs: claimIDsWithArgument}, claim.ModuleCodec)
 	if err != nil {
 		fmt.Println("appAccountClaimsWithArguments err: ", err)
 		return []claim.Claim{}
@@ -948,7 +1345,7 @@ func (ta *TruAPI) appAccountClaimsWithAgreesResolver(ctx context.Context, q quer
 	})
 
 	queryRoute := path.Join(claim.QuerierRoute, claim.QueryClaimsByIDs)
-	res, err := ta.Query(queryRoute, claim.QueryClaimsParams{IDs: claimIDsWithAgrees}, claim.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, claim.QueryClaimsParams{IDs: claimIDsWithAgrees}, claim.ModuleCodec)
 	if err != nil {
 		fmt.Println("appAccountClaimsWithAgrees err: ", err)
 		return []claim.Claim{}
@@ -977,7 +1374,7 @@ func (ta *TruAPI) agreesResolver(ctx context.Context, q queryByAddress) []stakin
 	}
 
 	queryRoute := path.Join(staking.QuerierRoute, staking.QueryUserStakes)
-	res, err := ta.Query(queryRoute, staking.QueryUserStakesParams{Address: creator}, staking.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryUserStakesParams{Address: creator}, staking.ModuleCodec)
 	if err != nil {
 		fmt.Println("agreesResolver err: ", err)
 		return []staking.Stake{}
@@ -1021,7 +1418,7 @@ func (ta *TruAPI) appAccountTransactionsResolver(ctx context.Context, q queryByA
 	}
 
 	queryRoute := path.Join(bank.QuerierRoute, bank.QueryTransactionsByAddress)
-	res, err := ta.Query(queryRoute, bank.QueryTransactionsByAddressParams{Address: creator}, bank.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, bank.QueryTransactionsByAddressParams{Address: creator}, bank.ModuleCodec)
 	if err != nil {
 		fmt.Println("appAccountTransactionsResolver err: ", err)
 		return []bank.Transaction{}
@@ -1135,10 +1532,20 @@ func (ta *TruAPI) transactionReferenceResolver(ctx context.Context, t bank.Trans
 }
 
 func (ta *TruAPI) claimImageResolver(ctx context.Context, q claim.Claim) string {
+	claimImageURL, err := ta.resolveClaimImage(q)
+	if err != nil {
+		fmt.Println("resolveClaimImage err: ", err)
+	}
+	return claimImageURL
+}
+
+// resolveClaimImage returns the image of a claim, caching it along with the claim's media flag the first time.
+// The returned error is from caching the image, the image url is always usable.
+func (ta *TruAPI) resolveClaimImage(q claim.Claim) (string, error) {
 	claimImageURL, err := ta.DBClient.ClaimImageURL(q.ID)
 	if err == nil && claimImageURL != "" {
 		// found claimImageURL in the database, exit early
-		return claimImageURL
+		return claimImageURL, nil
 	}
 
 	n := (q.ID % 5) // random but deterministic placeholder image 0-4
@@ -1159,12 +1566,13 @@ func (ta *TruAPI) claimImageResolver(ctx context.Context, q claim.Claim) string
 		}
 	}
 
-	_ = ta.DBClient.AddClaimImage(&db.ClaimImage{
+	err = ta.DBClient.AddClaimImage(&db.ClaimImage{
 		ClaimID:       q.ID,
 		ClaimImageURL: claimImageURL,
+		HasMedia:      claimImageURL != defaultImageURL,
 	})
 
-	return claimImageURL
+	return claimImageURL, err
 }
 
 func (ta *TruAPI) claimVideoResolver(ctx context.Context, q claim.Claim) *string {
@@ -1175,9 +1583,9 @@ func (ta *TruAPI) claimVideoResolver(ctx context.Context, q claim.Claim) *string
 	return nil
 }
 
-func (ta *TruAPI) settingsResolver(_ context.Context) Settings {
+func (ta *TruAPI) settingsResolver(ctx context.Context) Settings {
 	queryRoute := path.Join(account.QuerierRoute, account.QueryParams)
-	res, err := ta.Query(queryRoute, struct{}{}, account.ModuleCodec)
+	res, err := ta.QueryWithContext(ctx, queryRoute, struct{}{}, account.ModuleCodec)
 	if err != nil {
 		fmt.Println("settingsResolver err: ", err)
 		return Settings{}
@@ -1191,7 +1599,7 @@ func (ta *TruAPI) settingsResolver(_ context.Context) Settings {
 	}
 
 	queryRoute = path.Join(claim.QuerierRoute, claim.QueryParams)
-	res, err = ta.Query(queryRoute, struct{}{}, claim.ModuleCodec)
+	res, err = ta.QueryWithContext(ctx, queryRoute, struct{}{}, claim.ModuleCodec)
 	if err != nil {
 		fmt.Println("settingsResolver err: ", err)
 		return Settings{}
@@ -1205,7 +1613,7 @@ func (ta *TruAPI) settingsResolver(_ context.Context) Settings {
 	}
 
 	queryRoute = path.Join(staking.QuerierRoute, staking.QueryParams)
-	res, err = ta.Query(queryRoute, struct{}{}, staking.ModuleCodec)
+	res, err = ta.QueryWithContext(ctx, queryRoute, struct{}{}, staking.ModuleCodec)
 	if err != nil {
 		fmt.Println("settingsResolver err: ", err)
 		return Settings{}
@@ -1219,7 +1627,7 @@ func (ta *TruAPI) settingsResolver(_ context.Context) Settings {
 	}
 
 	queryRoute = path.Join(slashing.QuerierRoute, slashing.QueryParams)
-	res, err = ta.Query(queryRoute, struct{}{}, slashing.ModuleCodec)
+	res, err = ta.QueryWithContext(ctx, queryRoute, struct{}{}, slashing.ModuleCodec)
 	if err != nil {
 		fmt.Println("settingsResolver err: ", err)
 		return Settings{}
@@ -1288,7 +1696,7 @@ func (ta *TruAPI) settingsResolver(_ context.Context) Settings {
 
 		// off-chain params
 		MinCommentLength:  int32(tomlParams.CommentMinLength),
-		MaxCommentLength:  int32(tomlParams.CommentMaxLength),
+		MaxCommentLength:  int32(ta.commentMaxLength()),
 		BlockIntervalTime: int32(tomlParams.BlockInterval),
 		StakeDisplayDenom: db.CoinDisplayName,
 
@@ -1301,6 +1709,22 @@ func (ta *TruAPI) settingsResolver(_ context.Context) Settings {
 	}
 }
 
+var (
+	// earningTransactions are the interest and rewards earned with stakes
+	earningTransactions = []bank.TransactionType{
+		bank.TransactionInterestArgumentCreation,
+		bank.TransactionInterestUpvoteReceived,
+		bank.TransactionInterestUpvoteGiven,
+		bank.TransactionRewardPayout,
+	}
+	// earningSlashedTransactions take back the interest of slashed stakes
+	earningSlashedTransactions = []bank.TransactionType{
+		bank.TransactionInterestArgumentCreationSlashed,
+		bank.TransactionInterestUpvoteGivenSlashed,
+		bank.TransactionInterestUpvoteReceivedSlashed,
+	}
+)
+
 func (ta *TruAPI) appAccountCommunityEarningsResolver(ctx context.Context, q queryByAddress) []appAccountCommunityEarning {
 	now := time.Now()
 
@@ -1326,12 +1750,7 @@ func (ta *TruAPI) appAccountCommunityEarningsResolver(ctx context.Context, q que
 	for _, transaction := range transactions {
 
 		// Stake Earned
-		if transaction.Type.OneOf([]bank.TransactionType{
-			bank.TransactionInterestArgumentCreation,
-			bank.TransactionInterestUpvoteReceived,
-			bank.TransactionInterestUpvoteGiven,
-			bank.TransactionRewardPayout,
-		}) {
+		if transaction.Type.OneOf(earningTransactions) {
 			// some transactions are in blacklisted communities so make sure to check the community exists in the map
 			if _, ok := communityAllTimeEarnings[transaction.CommunityID]; ok {
 				communityAllTimeEarnings[transaction.CommunityID] = communityAllTimeEarnings[transaction.CommunityID].Add(transaction.Amount)
@@ -1345,11 +1764,7 @@ func (ta *TruAPI) appAccountCommunityEarningsResolver(ctx context.Context, q que
 		}
 
 		// Stake  Lost
-		if transaction.Type.OneOf([]bank.TransactionType{
-			bank.TransactionInterestArgumentCreationSlashed,
-			bank.TransactionInterestUpvoteGivenSlashed,
-			bank.TransactionInterestUpvoteReceivedSlashed,
-		}) {
+		if transaction.Type.OneOf(earningSlashedTransactions) {
 			// some transactions are in blacklisted communities so make sure to check the community exists in the map
 			if _, ok := communityAllTimeEarnings[transaction.CommunityID]; ok {
 				communityAllTimeEarnings[transaction.CommunityID] = communityAllTimeEarnings[transaction.CommunityID].Sub(transaction.Amount)
@@ -1428,23 +1843,14 @@ func (ta *TruAPI) appAccountEarningsResolver(ctx context.Context, q appAccountEa
 		dailyRunningBalances[key] = runningBalance
 
 		// Stake Earned
-		if transaction.Type.OneOf([]bank.TransactionType{
-			bank.TransactionInterestArgumentCreation,
-			bank.TransactionInterestUpvoteReceived,
-			bank.TransactionInterestUpvoteGiven,
-			bank.TransactionRewardPayout,
-		}) {
+		if transaction.Type.OneOf(earningTransactions) {
 			if transaction.CreatedTime.After(from) {
 				netEarnings = netEarnings.Add(transaction.Amount)
 			}
 		}
 
 		// Stake  Lost
-		if transaction.Type.OneOf([]bank.TransactionType{
-			bank.TransactionInterestArgumentCreationSlashed,
-			bank.TransactionInterestUpvoteGivenSlashed,
-			bank.TransactionInterestUpvoteReceivedSlashed,
-		}) {
+		if transaction.Type.OneOf(earningSlashedTransactions) {
 			if transaction.CreatedTime.After(from) {
 				netEarnings = netEarnings.Sub(transaction.Amount)
 			}
@@ -1488,6 +1894,34 @@ func (ta *TruAPI) appAccountEarningsResolver(ctx context.Context, q appAccountEa
 	}
 }
 
+// appAccountEarningsBetweenResolver returns the net earnings of a user within a time window:
+// the interest and rewards they earned minus the interest that was slashed
+func (ta *TruAPI) appAccountEarningsBetweenResolver(ctx context.Context, q queryAppAccountEarningsBetweenParams) sdk.Coin {
+	to := time.Now()
+	if q.To != nil {
+		to = *q.To
+	}
+	transactions := ta.appAccountTransactionsResolver(ctx, queryByAddress{ID: q.Address})
+	return netEarningsBetween(transactions, q.From, to)
+}
+
+// netEarningsBetween sums the earnings of the transactions made from the start of the window until before its end
+func netEarningsBetween(transactions []bank.Transaction, from, to time.Time) sdk.Coin {
+	netEarnings := sdk.NewCoin(app.StakeDenom, sdk.ZeroInt())
+	for _, transaction := range transactions {
+		if transaction.CreatedTime.Before(from) || !transaction.CreatedTime.Before(to) {
+			continue
+		}
+		if transaction.Type.OneOf(earningTransactions) {
+			netEarnings.Amount = netEarnings.Amount.Add(transaction.Amount.Amount)
+		}
+		if transaction.Type.OneOf(earningSlashedTransactions) {
+			netEarnings.Amount = netEarnings.Amount.Sub(transaction.Amount.Amount)
+		}
+	}
+	return netEarnings
+}
+
 func (ta *TruAPI) unreadNotificationsCountResolver(ctx context.Context, q struct{}) *db.NotificationsCountResponse {
 	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
 	if !ok {
@@ -1528,6 +1962,34 @@ func (ta *TruAPI) notificationsResolver(ctx context.Context, q struct{}) []db.No
 	return evts
 }
 
+// mentionNotificationsResolver returns the notifications of the user being mentioned
+func (ta *TruAPI) mentionNotificationsResolver(ctx context.Context, q struct{}) []db.NotificationEvent {
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok {
+		return make([]db.NotificationEvent, 0)
+	}
+	evts, err := ta.DBClient.NotificationEventsByAddressAndType(user.Address, db.NotificationMentionAction)
+	if err != nil {
+		fmt.Println("mentionNotificationsResolver err: ", err)
+		return make([]db.NotificationEvent, 0)
+	}
+	return evts
+}
+
+// rewardLedgerResolver returns the invites credited to and debited from the user, newest first
+func (ta *TruAPI) rewardLedgerResolver(ctx context.Context, q struct{}) []db.RewardLedgerEntry {
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok {
+		return make([]db.RewardLedgerEntry, 0)
+	}
+	entries, err := ta.DBClient.RewardLedgerByUser(user.ID)
+	if err != nil {
+		fmt.Println("rewardLedgerResolver err: ", err)
+		return make([]db.RewardLedgerEntry, 0)
+	}
+	return entries
+}
+
 func (ta *TruAPI) invitesResolver(ctx context.Context) []db.Invite {
 	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
 	if !ok {
@@ -1592,6 +2054,35 @@ func (ta *TruAPI) referredAppAccountsResolver(ctx context.Context, q queryReferr
 	return appAccounts
 }
 
+func (ta *TruAPI) referralTreeResolver(ctx context.Context, q queryReferralTreeParams) []db.ReferredUser {
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil {
+		return make([]db.ReferredUser, 0)
+	}
+
+	rootID := user.ID
+	if q.UserID != 0 && q.UserID != user.ID {
+		if !ta.isClaimAdmin(ctx, user.Address) {
+			return make([]db.ReferredUser, 0)
+		}
+		rootID = q.UserID
+	}
+	maxDepth := int(q.MaxDepth)
+	if maxDepth <= 0 {
+		maxDepth = defaultReferralTreeDepth
+	}
+	if maxDepth > maxReferralTreeDepth {
+		maxDepth = maxReferralTreeDepth
+	}
+
+	tree, err := ta.DBClient.ReferralTree(rootID, maxDepth)
+	if err != nil {
+		fmt.Println("referralTreeResolver err: ", err)
+		return make([]db.ReferredUser, 0)
+	}
+	return tree
+}
+
 func (ta *TruAPI) followsCommunity(ctx context.Context, q queryByCommunityID) bool {
 	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
 	if !ok || user == nil {
@@ -1604,6 +2095,102 @@ func (ta *TruAPI) followsCommunity(ctx context.Context, q queryByCommunityID) bo
 	return follows
 }
 
+func (ta *TruAPI) communityFollowersCountResolver(_ context.Context, q community.Community) int {
+	count, err := ta.DBClient.CommunityFollowersCount(q.ID)
+	if err != nil {
+		fmt.Println("communityFollowersCountResolver err: ", err)
+		return 0
+	}
+	return count
+}
+
+func (ta *TruAPI) communityFollowersResolver(ctx context.Context, q queryByCommunityID) []AppAccount {
+	users, err := ta.DBClient.CommunityFollowers(q.CommunityID)
+	if err != nil {
+		fmt.Println("communityFollowersResolver err: ", err)
+		return make([]AppAccount, 0)
+	}
+
+	appAccounts := make([]AppAccount, 0)
+	for _, user := range users {
+		appAccount := ta.appAccountResolver(ctx, queryByAddress{ID: user.Address})
+		if appAccount != nil {
+			appAccounts = append(appAccounts, *appAccount)
+		}
+	}
+	return appAccounts
+}
+
+type queryRecentCommunityMembersParams struct {
+	CommunityID string `graphql:"communityId"`
+	Days        int64  `graphql:"days,optional"`
+	Limit       int64  `graphql:"limit,optional"`
+}
+
+func (ta *TruAPI) recentCommunityMembersResolver(ctx context.Context, q queryRecentCommunityMembersParams) []AppAccount {
+	days := q.Days
+	if days == 0 {
+		days = 30
+	}
+	limit := int(q.Limit)
+	if limit == 0 {
+		limit = 10
+	}
+	since := time.Now().AddDate(0, 0, -int(days))
+	users, err := ta.DBClient.RecentCommunityMembers(q.CommunityID, since, limit)
+	if err != nil {
+		fmt.Println("recentCommunityMembersResolver err: ", err)
+		return make([]AppAccount, 0)
+	}
+
+	appAccounts := make([]AppAccount, 0)
+	for _, user := range users {
+		appAccount := ta.appAccountResolver(ctx, queryByAddress{ID: user.Address})
+		if appAccount != nil {
+			appAccounts = append(appAccounts, *appAccount)
+		}
+	}
+	return appAccounts
+}
+
+type queryByUserGroup struct {
+	Group int64 `graphql:"group"`
+}
+
+func (ta *TruAPI) usersByGroupResolver(ctx context.Context, q queryByUserGroup) []AppAccount {
+	user, ok := ctx.Value(userContextKey).(*cookies.AuthenticatedUser)
+	if !ok || user == nil {
+		return make([]AppAccount, 0)
+	}
+	if !ta.isClaimAdmin(ctx, user.Address) {
+		return make([]AppAccount, 0)
+	}
+
+	users, err := ta.DBClient.UsersByGroup(db.UserGroup(q.Group))
+	if err != nil {
+		fmt.Println("usersByGroupResolver err: ", err)
+		return make([]AppAccount, 0)
+	}
+
+	appAccounts := make([]AppAccount, 0)
+	for _, u := range users {
+		if u.Address == "" {
+			continue
+		}
+		appAccount := ta.appAccountResolver(ctx, queryByAddress{ID: u.Address})
+		if appAccount != nil {
+			appAccounts = append(appAccounts, *appAccount)
+		}
+	}
+	return appAccounts
+}
+
+// isClaimAdmin tells whether an address can administer claims in communities
+func (ta *TruAPI) isClaimAdmin(ctx context.Context, address string) bool {
+	settings := ta.settingsResolver(ctx)
+	return contains(settings.ClaimAdmins, address)
+}
+
 func contains(s []string, e string) bool {
 	for _, a := range s {
 		if a == e {
@@ -0,0 +1,276 @@
+package truapi
+
+import (
+	"context"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TruStory/truchain/x/bank"
+	"github.com/TruStory/truchain/x/claim"
+	"github.com/TruStory/truchain/x/community"
+	"github.com/TruStory/truchain/x/staking"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+	"github.com/TruStory/octopus/services/truapi/db"
+	"github.com/TruStory/octopus/services/truapi/truapi/cookies"
+)
+
+func TestClaimsCreatedBetween(t *testing.T) {
+	now := time.Now()
+	lastWeek := now.Add(-7 * 24 * time.Hour)
+	yesterday := now.Add(-24 * time.Hour)
+	claims := []claim.Claim{
+		{ID: 1, CommunityID: "crypto", CreatedTime: now.Add(-30 * 24 * time.Hour)},
+		{ID: 2, CommunityID: "crypto", CreatedTime: lastWeek},
+		{ID: 3, CommunityID: "sports", CreatedTime: now.Add(-3 * 24 * time.Hour)},
+		{ID: 4, CommunityID: "crypto", CreatedTime: yesterday},
+		{ID: 5, CommunityID: "crypto", CreatedTime: now},
+	}
+	ids := func(claims []claim.Claim) []uint64 {
+		ids := make([]uint64, 0, len(claims))
+		for _, c := range claims {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5}, ids(claimsCreatedBetween(claims, nil, nil)))
+	assert.Equal(t, []uint64{2, 3, 4, 5}, ids(claimsCreatedBetween(claims, &lastWeek, nil)))
+	assert.Equal(t, []uint64{1, 2, 3}, ids(claimsCreatedBetween(claims, nil, &yesterday)))
+	assert.Equal(t, []uint64{2, 3}, ids(claimsCreatedBetween(claims, &lastWeek, &yesterday)))
+	assert.Empty(t, claimsCreatedBetween(claims, &yesterday, &lastWeek))
+
+	// claims of a community, as returned by the chain, created in the last week
+	crypto := []claim.Claim{claims[0], claims[1], claims[3], claims[4]}
+	assert.Equal(t, []uint64{2, 4, 5}, ids(claimsCreatedBetween(crypto, &lastWeek, nil)))
+}
+
+func TestAllClaimsQuery(t *testing.T) {
+	after := time.Now().Add(-time.Hour)
+	before := time.Now()
+
+	route, params := allClaimsQuery(nil, nil)
+	assert.Equal(t, path.Join(claim.QuerierRoute, claim.QueryClaims), route)
+	assert.Equal(t, struct{}{}, params)
+
+	route, params = allClaimsQuery(&after, nil)
+	assert.Equal(t, path.Join(claim.QuerierRoute, claim.QueryClaimsAfterTime), route)
+	assert.Equal(t, claim.QueryClaimsTimeParams{CreatedTime: after}, params)
+
+	route, params = allClaimsQuery(nil, &before)
+	assert.Equal(t, path.Join(claim.QuerierRoute, claim.QueryClaimsBeforeTime), route)
+	assert.Equal(t, claim.QueryClaimsTimeParams{CreatedTime: before}, params)
+
+	// the chain narrows down by the lower bound, the upper one is applied afterwards
+	route, _ = allClaimsQuery(&after, &before)
+	assert.Equal(t, path.Join(claim.QuerierRoute, claim.QueryClaimsAfterTime), route)
+}
+
+// fakeNotificationStore filters notification events in memory like the database
+type fakeNotificationStore struct {
+	db.Datastore
+	events []db.NotificationEvent
+}
+
+func (s fakeNotificationStore) NotificationEventsByAddressAndType(addr string, notificationType db.NotificationType) ([]db.NotificationEvent, error) {
+	evts := make([]db.NotificationEvent, 0)
+	for _, evt := range s.events {
+		if evt.Address == addr && evt.Type == notificationType {
+			evts = append(evts, evt)
+		}
+	}
+	return evts, nil
+}
+
+func TestMentionNotificationsResolver(t *testing.T) {
+	ta := &TruAPI{DBClient: fakeNotificationStore{events: []db.NotificationEvent{
+		{ID: 1, Address: "alice", Type: db.NotificationCommentAction},
+		{ID: 2, Address: "alice", Type: db.NotificationMentionAction},
+		{ID: 3, Address: "bob", Type: db.NotificationMentionAction},
+		{ID: 4, Address: "alice", Type: db.NotificationReactionReceived},
+		{ID: 5, Address: "alice", Type: db.NotificationMentionAction},
+	}}}
+
+	assert.Empty(t, ta.mentionNotificationsResolver(context.Background(), struct{}{}))
+
+	ctx := context.WithValue(context.Background(), userContextKey, &cookies.AuthenticatedUser{Address: "alice"})
+	evts := ta.mentionNotificationsResolver(ctx, struct{}{})
+	ids := make([]int64, 0, len(evts))
+	for _, evt := range evts {
+		assert.Equal(t, db.NotificationMentionAction, evt.Type)
+		ids = append(ids, evt.ID)
+	}
+	assert.Equal(t, []int64{2, 5}, ids)
+}
+
+func TestCommunityNameResolver(t *testing.T) {
+	ta := &TruAPI{APIContext: truCtx.TruAPIContext{Config: truCtx.Config{
+		Community: truCtx.CommunityConfig{BetaCommunities: []string{"sports"}},
+	}}}
+	ctx := context.Background()
+	beta := community.Community{ID: "sports", Name: "Sports"}
+	regular := community.Community{ID: "crypto", Name: "Crypto"}
+
+	assert.Equal(t, "Sports (Beta)", ta.communityNameResolver(ctx, beta))
+	assert.True(t, ta.isBetaCommunityResolver(ctx, beta))
+	assert.Equal(t, "Crypto", ta.communityNameResolver(ctx, regular))
+	assert.False(t, ta.isBetaCommunityResolver(ctx, regular))
+
+	ta.APIContext.Config.Community.BetaLabel = " · early access"
+	assert.Equal(t, "Sports · early access", ta.communityNameResolver(ctx, beta))
+	assert.Equal(t, "Crypto", ta.communityNameResolver(ctx, regular))
+}
+
+func TestNetEarningsBetween(t *testing.T) {
+	from := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	transaction := func(txType bank.TransactionType, amount int64, createdTime time.Time) bank.Transaction {
+		return bank.Transaction{Type: txType, Amount: sdk.NewInt64Coin("tru", amount), CreatedTime: createdTime}
+	}
+	transactions := []bank.Transaction{
+		// before the window
+		transaction(bank.TransactionInterestArgumentCreation, 1000, from.Add(-time.Second)),
+		// within the window
+		transaction(bank.TransactionInterestArgumentCreation, 100, from),
+		transaction(bank.TransactionInterestUpvoteReceived, 20, from.Add(time.Hour)),
+		transaction(bank.TransactionRewardPayout, 5, from.AddDate(0, 0, 3)),
+		transaction(bank.TransactionInterestUpvoteGivenSlashed, 30, from.AddDate(0, 0, 4)),
+		// stakes aren't earnings
+		transaction(bank.TransactionBacking, 500, from.AddDate(0, 0, 5)),
+		// at the end of the window
+		transaction(bank.TransactionInterestUpvoteGiven, 2000, to),
+	}
+
+	assert.Equal(t, sdk.NewInt(95), netEarningsBetween(transactions, from, to).Amount)
+	assert.Equal(t, sdk.NewInt(1095), netEarningsBetween(transactions, from.Add(-time.Hour), to).Amount)
+	// slashes can make the earnings negative
+	assert.Equal(t, sdk.NewInt(-30), netEarningsBetween(transactions, from.AddDate(0, 0, 4), to).Amount)
+	assert.True(t, netEarningsBetween(nil, from, to).IsZero())
+}
+
+// fakeLedgerStore returns the ledger entries of any user
+type fakeLedgerStore struct {
+	db.Datastore
+	entries map[int64][]db.RewardLedgerEntry
+}
+
+func (s *fakeLedgerStore) RewardLedgerByUser(id int64) ([]db.RewardLedgerEntry, error) {
+	return s.entries[id], nil
+}
+
+func TestRewardLedgerResolver(t *testing.T) {
+	alice := []db.RewardLedgerEntry{
+		{ID: 2, UserID: 1, Direction: db.RewardLedgerEntryDirectionDebit, Amount: 1, Currency: db.RewardLedgerEntryCurrencyInvite},
+		{ID: 1, UserID: 1, Direction: db.RewardLedgerEntryDirectionCredit, Amount: 2, Currency: db.RewardLedgerEntryCurrencyInvite},
+	}
+	store := &fakeLedgerStore{entries: map[int64][]db.RewardLedgerEntry{1: alice}}
+	ta := &TruAPI{DBClient: store}
+
+	assert.Empty(t, ta.rewardLedgerResolver(context.Background(), struct{}{}))
+
+	// the ledger of the authenticated user, whatever address is asked for
+	ctx := context.WithValue(context.Background(), userContextKey, &cookies.AuthenticatedUser{ID: 1, Address: "alice"})
+	assert.Equal(t, alice, ta.rewardLedgerResolver(ctx, struct{}{}))
+}
+
+// fakeCommentsViewStore counts the comments of a claim by others as unread until they are marked seen
+type fakeCommentsViewStore struct {
+	db.Datastore
+	creators []string
+	seen     map[string]int
+}
+
+func (s *fakeCommentsViewStore) MarkClaimCommentsSeen(_ int64, address string) error {
+	s.seen[address] = len(s.creators)
+	return nil
+}
+
+func (s *fakeCommentsViewStore) UnreadCommentsCount(_ int64, address string) (int, error) {
+	unread := 0
+	for _, creator := range s.creators[s.seen[address]:] {
+		if creator != address {
+			unread++
+		}
+	}
+	return unread, nil
+}
+
+func TestUnreadCommentsCountResolver(t *testing.T) {
+	store := &fakeCommentsViewStore{creators: []string{"alice", "bob", "alice"}, seen: make(map[string]int)}
+	ta := &TruAPI{DBClient: store}
+	q := queryUnreadCommentsCountParams{ClaimID: 1}
+
+	// only the authenticated user has unread comments
+	assert.Equal(t, 0, ta.unreadCommentsCountResolver(context.Background(), q))
+	assert.Equal(t, Err401NotAuthenticated, ta.markClaimCommentsSeen(context.Background(), 1))
+
+	bob := context.WithValue(context.Background(), userContextKey, &cookies.AuthenticatedUser{ID: 2, Address: "bob"})
+	assert.Equal(t, 2, ta.unreadCommentsCountResolver(bob, q))
+	assert.NoError(t, ta.markClaimCommentsSeen(bob, 1))
+	assert.Equal(t, 0, ta.unreadCommentsCountResolver(bob, q))
+
+	store.creators = append(store.creators, "alice")
+	assert.Equal(t, 1, ta.unreadCommentsCountResolver(bob, q))
+}
+
+func TestClaimStakesCache(t *testing.T) {
+	cache := &claimStakesCache{stakes: make(map[uint64][]staking.Stake)}
+	var queries int64
+	release := make(chan struct{})
+	slow := func() []staking.Stake {
+		atomic.AddInt64(&queries, 1)
+		<-release
+		return []staking.Stake{{ID: 1}}
+	}
+
+	// concurrent lookups of the same claim share one query
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, uint64(1), cache.get(1, slow)[0].ID)
+		}()
+	}
+
+	// while it's pending, other claims are not blocked
+	done := make(chan []staking.Stake)
+	go func() { done <- cache.get(2, func() []staking.Stake { return []staking.Stake{{ID: 2}} }) }()
+	select {
+	case stakes := <-done:
+		assert.Equal(t, uint64(2), stakes[0].ID)
+	case <-time.After(time.Second):
+		t.Fatal("a pending query blocked the lookup of another claim")
+	}
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int64(1), atomic.LoadInt64(&queries))
+
+	// memoized from now on
+	assert.Equal(t, uint64(1), cache.get(1, slow)[0].ID)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&queries))
+}
+
+type fakeMediaStore struct {
+	db.Datastore
+	claimIDsWithMedia []int64
+}
+
+func (s *fakeMediaStore) ClaimIDsWithMedia() ([]int64, error) {
+	return s.claimIDsWithMedia, nil
+}
+
+func TestFilterClaimsWithMedia(t *testing.T) {
+	ta := &TruAPI{DBClient: &fakeMediaStore{claimIDsWithMedia: []int64{3, 1, 9}}}
+	claims := []claim.Claim{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	claimsWithMedia, err := ta.filterClaimsWithMedia(claims)
+	assert.NoError(t, err)
+	assert.Equal(t, []claim.Claim{{ID: 1}, {ID: 3}}, claimsWithMedia)
+}
@@ -0,0 +1,142 @@
+package truapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+// RequiredJourneySteps are the steps a user must complete to unlock their invites
+var RequiredJourneySteps = []db.UserJourneyStep{
+	db.JourneyStepSignedUp,
+	db.JourneyStepOneArgument,
+	db.JourneyStepReceiveFiveAgrees,
+}
+
+// RewardSchedule is the schedule of rewards given for completing the user journey.
+// It is served to the reward pipeline, so that payouts and previews come from the same config.
+type RewardSchedule struct {
+	// InviteBatchSize is the number of invites granted to a user (and their referrer) once all required steps are completed
+	InviteBatchSize int `json:"invite_batch_size"`
+	// StepRewards is the amount paid to the referrer when the referred user completes a step
+	StepRewards map[db.UserJourneyStep]string `json:"step_rewards"`
+	// AgreesRequired is the number of agrees to receive to complete the "received five agrees" step
+	AgreesRequired int `json:"agrees_required"`
+}
+
+// NewRewardSchedule builds the reward schedule from the config
+func NewRewardSchedule(config truCtx.RewardsConfig) RewardSchedule {
+	return RewardSchedule{
+		InviteBatchSize: config.InviteBatchSize,
+		StepRewards: map[db.UserJourneyStep]string{
+			db.JourneyStepSignedUp:          config.StepSignUp,
+			db.JourneyStepOneArgument:       config.StepOneArgument,
+			db.JourneyStepReceiveFiveAgrees: config.StepFiveAgrees,
+		},
+		AgreesRequired: agreesRequired(config),
+	}
+}
+
+// Validate checks that the schedule grants invites and rewards every required step,
+// so that a missing config isn't paid out as nothing
+func (s RewardSchedule) Validate() error {
+	if s.InviteBatchSize <= 0 {
+		return errors.New("reward schedule has no invite batch size")
+	}
+	for _, step := range RequiredJourneySteps {
+		if _, ok := s.RewardForStep(step); !ok {
+			return fmt.Errorf("reward schedule has no reward for step %s", step)
+		}
+	}
+	return nil
+}
+
+// RewardForStep returns the referrer reward for a completed step, if any
+func (s RewardSchedule) RewardForStep(step db.UserJourneyStep) (string, bool) {
+	reward, ok := s.StepRewards[step]
+	if !ok || reward == "" {
+		return "", false
+	}
+	return reward, true
+}
+
+// PendingReward represents a reward a user unlocks by completing the remaining steps
+type PendingReward struct {
+	Type        string               `json:"type"`
+	Amount      string               `json:"amount"`
+	Steps       []db.UserJourneyStep `json:"steps"`
+	Description string               `json:"description"`
+}
+
+// PendingRewards returns the rewards a user would earn by completing the remaining required steps
+func (s RewardSchedule) PendingRewards(completed []db.UserJourneyStep) []PendingReward {
+	remaining := make([]db.UserJourneyStep, 0)
+	for _, step := range RequiredJourneySteps {
+		if !containsJourneyStep(completed, step) {
+			remaining = append(remaining, step)
+		}
+	}
+	if len(remaining) == 0 || s.InviteBatchSize == 0 {
+		return make([]PendingReward, 0)
+	}
+
+	return []PendingReward{
+		{
+			Type:        "invite",
+			Amount:      strconv.Itoa(s.InviteBatchSize),
+			Steps:       remaining,
+			Description: fmt.Sprintf("%s to unlock %d invites", s.stepDescription(remaining[0]), s.InviteBatchSize),
+		},
+	}
+}
+
+var journeyStepDescriptions = map[db.UserJourneyStep]string{
+	db.JourneyStepSignedUp:      "Sign up",
+	db.JourneyStepOneArgument:   "Write an argument",
+	db.JourneyStepGivenOneAgree: "Agree with an argument",
+}
+
+func (s RewardSchedule) stepDescription(step db.UserJourneyStep) string {
+	if step == db.JourneyStepReceiveFiveAgrees {
+		return fmt.Sprintf("Receive %d agrees", s.AgreesRequired)
+	}
+	return journeyStepDescriptions[step]
+}
+
+func containsJourneyStep(steps []db.UserJourneyStep, step db.UserJourneyStep) bool {
+	for _, s := range steps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+func (ta *TruAPI) appAccountPendingRewardsResolver(ctx context.Context, q queryByAddress) []PendingReward {
+	user, err := ta.DBClient.UserByAddress(q.ID)
+	if err != nil {
+		fmt.Println("appAccountPendingRewardsResolver err: ", err)
+		return make([]PendingReward, 0)
+	}
+	if user == nil {
+		return make([]PendingReward, 0)
+	}
+
+	completed := make([]db.UserJourneyStep, 0)
+	for _, step := range RequiredJourneySteps {
+		done, err := ta.isStepCompleted(ctx, step, user)
+		if err != nil {
+			fmt.Println("appAccountPendingRewardsResolver err: ", err)
+			return make([]PendingReward, 0)
+		}
+		if done {
+			completed = append(completed, step)
+		}
+	}
+
+	return NewRewardSchedule(ta.APIContext.Config.Rewards).PendingRewards(completed)
+}
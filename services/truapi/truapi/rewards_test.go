@@ -0,0 +1,31 @@
+package truapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	truCtx "github.com/TruStory/octopus/services/truapi/context"
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+func TestPendingRewards(t *testing.T) {
+	schedule := NewRewardSchedule(truCtx.RewardsConfig{InviteBatchSize: 5})
+
+	rewards := schedule.PendingRewards([]db.UserJourneyStep{db.JourneyStepSignedUp})
+	assert.Len(t, rewards, 1)
+	assert.Equal(t, "5", rewards[0].Amount)
+	assert.Equal(t, []db.UserJourneyStep{db.JourneyStepOneArgument, db.JourneyStepReceiveFiveAgrees}, rewards[0].Steps)
+	assert.Equal(t, "Write an argument to unlock 5 invites", rewards[0].Description)
+
+	// nothing left to unlock once every required step is completed
+	assert.Len(t, schedule.PendingRewards(RequiredJourneySteps), 0)
+}
+
+func TestPendingRewardsAgreesRequired(t *testing.T) {
+	schedule := NewRewardSchedule(truCtx.RewardsConfig{InviteBatchSize: 5, AgreesRequired: 3})
+
+	rewards := schedule.PendingRewards([]db.UserJourneyStep{db.JourneyStepSignedUp, db.JourneyStepOneArgument})
+	assert.Len(t, rewards, 1)
+	assert.Equal(t, "Receive 3 agrees to unlock 5 invites", rewards[0].Description)
+}
@@ -24,6 +24,7 @@ func (ta *TruAPI) RegisterRoutes(apiCtx truCtx.TruAPIContext) {
 
 	liveRedirectHandler := RedirectHandler(apiCtx.Config.App.LiveDebateURL, http.StatusFound)
 	ta.Handle("/live", liveRedirectHandler)
+	ta.Handle("/health", http.HandlerFunc(ta.HandleHealth))
 
 	// Mixpanel support
 	ta.PathPrefix("/mixpanel", http.StripPrefix("/mixpanel", HandleMixpanel()))
@@ -32,7 +33,7 @@ func (ta *TruAPI) RegisterRoutes(apiCtx truCtx.TruAPIContext) {
 	// Enable gzip compression
 	api.Use(handlers.CompressHandler)
 	api.Use(chttp.JSONResponseMiddleware)
-	api.Use(WithUser(ta.APIContext))
+	api.Use(ta.WithUser())
 	api.Use(ta.WithDataLoaders())
 	api.Handle("/ping", WrapHandler(ta.HandlePing))
 
@@ -48,12 +49,15 @@ func (ta *TruAPI) RegisterRoutes(apiCtx truCtx.TruAPIContext) {
 	api.Handle("/flagStory", WrapHandler(ta.HandleFlagStory))
 	api.HandleFunc("/comments", ta.HandleComment)
 	api.Handle("/questions", WrapHandler(ta.HandleQuestion))
+	api.Handle("/claim_tags", WrapHandler(ta.HandleClaimTag))
 	api.HandleFunc("/comments/open/{claimID:[0-9]+}", ta.handleThreadOpened)
 	api.HandleFunc("/comments/open/{claimID:[0-9]+}/{argumentID:[0-9]+}/{elementID:[0-9]+}", ta.handleThreadOpened)
 	api.Handle("/reactions", WrapHandler(ta.HandleReaction))
 	api.HandleFunc("/mentions/translateToCosmos", ta.HandleTranslateCosmosMentions)
 	api.Handle("/track/", http.HandlerFunc(ta.HandleTrackEvent))
+	api.HandleFunc("/claims/{claimID:[0-9]+}/views", ta.HandleClaimView).Methods(http.MethodPost)
 	api.Handle("/claim_of_the_day", WrapHandler(ta.HandleClaimOfTheDayID))
+	api.Handle("/featured_claims", WrapHandler(ta.HandleFeaturedClaim))
 	api.Handle("/claim/image", WrapHandler(ta.HandleClaimImage))
 	api.HandleFunc("/spotlight", ta.HandleSpotlight)
 	api.HandleFunc("/request_tru", ta.HandleRequestTru)
@@ -62,13 +66,19 @@ func (ta *TruAPI) RegisterRoutes(apiCtx truCtx.TruAPIContext) {
 	api.HandleFunc("/user", ta.HandleUserDetails)
 	api.HandleFunc("/user/verify", ta.verifyUserViaToken).Methods(http.MethodPut)
 	api.HandleFunc("/users/blacklist", BasicAuth(apiCtx, http.HandlerFunc(ta.HandleUserBlacklisting)))
+	api.HandleFunc("/users/group", BasicAuth(apiCtx, http.HandlerFunc(ta.HandleUserGroup)))
+	api.HandleFunc("/twitter_profiles/backfill", BasicAuth(apiCtx, http.HandlerFunc(ta.HandleTwitterProfilesBackfill)))
+	api.HandleFunc("/users/verify/bulk", BasicAuth(apiCtx, http.HandlerFunc(ta.HandleUserBulkVerification)))
 	api.HandleFunc("/users/password-reset", ta.HandleUserForgotPassword)
 	api.HandleFunc("/users/resend-email-verification", ta.HandleResendEmailVerification)
 	api.HandleFunc("/users/validate/username", ta.HandleUniqueUsernameUtility)
 	api.HandleFunc("/users/validate/email", ta.HandleUniqueEmailUtility)
 	api.HandleFunc("/users/authentication", ta.HandleUserAuthentication)
 	api.HandleFunc("/users/onboard", ta.HandleUserOnboard)
+	api.HandleFunc("/users/onboard/resend", ta.HandleResendOnboarding)
+	api.HandleFunc("/users/delete", ta.HandleDeleteOwnAccount)
 	api.HandleFunc("/users/journey", BasicAuth(apiCtx, http.HandlerFunc(ta.HandleUserJourney)))
+	api.HandleFunc("/rewards/schedule", BasicAuth(apiCtx, http.HandlerFunc(ta.HandleRewardSchedule)))
 
 	api.HandleFunc("/gift", BasicAuth(apiCtx, http.HandlerFunc(ta.HandleGift)))
 	api.Handle("/communities/follow", http.HandlerFunc(ta.handleFollowCommunities)).Methods(http.MethodPost)
@@ -82,8 +92,9 @@ func (ta *TruAPI) RegisterRoutes(apiCtx truCtx.TruAPIContext) {
 	api.HandleFunc("/metrics/claims", ta.HandleClaimMetrics)
 	api.HandleFunc("/metrics/user_claims", ta.HandleUserClaims)
 	api.HandleFunc("/metrics/auth", BasicAuth(apiCtx, http.HandlerFunc(ta.HandleAuthMetrics)))
+	api.HandleFunc("/metrics/active_users", BasicAuth(apiCtx, http.HandlerFunc(ta.HandleActiveUsersMetrics)))
 	api.HandleFunc("/metrics/invites", BasicAuth(apiCtx, http.HandlerFunc(ta.HandleInvitesMetrics)))
-	api.HandleFunc("/metrics/user_base", ta.HandleUserBase)
+	api.Handle("/metrics/user_base", WrapStreamHandler(ta.HandleUserBase))
 
 	if apiCtx.Config.App.MockRegistration {
 		api.HandleFunc("/mock_register", ta.HandleMockRegistration)
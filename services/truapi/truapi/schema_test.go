@@ -0,0 +1,16 @@
+package truapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/graphql"
+)
+
+func TestGraphQLSchemaBuilds(t *testing.T) {
+	ta := &TruAPI{GraphQLClient: graphql.NewGraphQLClient()}
+	ta.RegisterMutations()
+	assert.NotPanics(t, ta.RegisterResolvers)
+	assert.True(t, ta.GraphQLClient.Built)
+}
@@ -0,0 +1,161 @@
+package truapi
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	app "github.com/TruStory/truchain/types"
+	"github.com/TruStory/truchain/x/bank"
+	"github.com/TruStory/truchain/x/claim"
+	"github.com/TruStory/truchain/x/staking"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const defaultStakePositionsLimit = 20
+
+// ClaimStatus is the staking status of a claim
+type ClaimStatus string
+
+// Claim statuses
+const (
+	ClaimStatusOpen    ClaimStatus = "open"
+	ClaimStatusExpired ClaimStatus = "expired"
+)
+
+// StakePosition represents how much a user has staked on a claim
+type StakePosition struct {
+	Claim      claim.Claim `json:"claim"`
+	Status     ClaimStatus `json:"status"`
+	Agreed     sdk.Coin    `json:"agreed"`
+	Backed     sdk.Coin    `json:"backed"`
+	Challenged sdk.Coin    `json:"challenged"`
+	Total      sdk.Coin    `json:"total"`
+	Pending    sdk.Coin    `json:"pending"`
+	Returned   sdk.Coin    `json:"returned"`
+}
+
+type queryStakePositionsParams struct {
+	ID     string `graphql:"id"`
+	Limit  int64  `graphql:"limit,optional"`
+	Offset int64  `graphql:"offset,optional"`
+}
+
+func newStakePosition(c claim.Claim) *StakePosition {
+	zero := sdk.NewCoin(app.StakeDenom, sdk.ZeroInt())
+	return &StakePosition{
+		Claim:      c,
+		Status:     ClaimStatusExpired,
+		Agreed:     zero,
+		Backed:     zero,
+		Challenged: zero,
+		Total:      zero,
+		Pending:    zero,
+		Returned:   zero,
+	}
+}
+
+// addStake adds one of the user's stakes to the position
+func (p *StakePosition) addStake(stake staking.Stake) {
+	switch stake.Type {
+	case staking.StakeUpvote:
+		p.Agreed = p.Agreed.Add(stake.Amount)
+	case staking.StakeBacking:
+		p.Backed = p.Backed.Add(stake.Amount)
+	case staking.StakeChallenge:
+		p.Challenged = p.Challenged.Add(stake.Amount)
+	}
+	p.Total = p.Total.Add(stake.Amount)
+	if !stake.Expired {
+		p.Pending = p.Pending.Add(stake.Amount)
+	}
+}
+
+// paginateStakePositions orders positions by amount staked descending, then by claim id, and returns a page
+func paginateStakePositions(positions []StakePosition, offset, limit int) []StakePosition {
+	sort.SliceStable(positions, func(i, j int) bool {
+		if !positions[i].Total.Amount.Equal(positions[j].Total.Amount) {
+			return positions[i].Total.Amount.GT(positions[j].Total.Amount)
+		}
+		return positions[i].Claim.ID > positions[j].Claim.ID
+	})
+	start, end := pageBounds(len(positions), offset, limit)
+	return positions[start:end]
+}
+
+func (ta *TruAPI) appAccountStakePositionsResolver(ctx context.Context, q queryStakePositionsParams) []StakePosition {
+	address, err := sdk.AccAddressFromBech32(q.ID)
+	if err != nil {
+		fmt.Println("appAccountStakePositionsResolver err: ", err)
+		return make([]StakePosition, 0)
+	}
+
+	queryRoute := path.Join(staking.QuerierRoute, staking.QueryUserStakes)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryUserStakesParams{Address: address}, staking.ModuleCodec)
+	if err != nil {
+		fmt.Println("appAccountStakePositionsResolver err: ", err)
+		return make([]StakePosition, 0)
+	}
+	stakes := make([]staking.Stake, 0)
+	err = staking.ModuleCodec.UnmarshalJSON(res, &stakes)
+	if err != nil {
+		fmt.Println("stakes UnmarshalJSON err: ", err)
+		return make([]StakePosition, 0)
+	}
+
+	// map every argument the user staked on to its claim
+	argumentClaims := make(map[uint64]uint64)
+	for _, stake := range stakes {
+		if _, ok := argumentClaims[stake.ArgumentID]; ok {
+			continue
+		}
+		argument := ta.claimArgumentResolver(ctx, queryByArgumentID{ID: stake.ArgumentID})
+		if argument == nil {
+			continue
+		}
+		argumentClaims[stake.ArgumentID] = argument.ClaimID
+	}
+
+	positions := make(map[uint64]*StakePosition)
+	for _, claimID := range argumentClaims {
+		if _, ok := positions[claimID]; ok {
+			continue
+		}
+		c := ta.claimResolver(ctx, queryByClaimID{ID: claimID})
+		if c.ID == 0 {
+			continue
+		}
+		position := newStakePosition(c)
+		for _, stake := range ta.memoizedClaimStakes(ctx, claimID) {
+			if !stake.Expired {
+				position.Status = ClaimStatusOpen
+			}
+			if stake.Creator.Equals(address) {
+				position.addStake(stake)
+			}
+		}
+		positions[claimID] = position
+	}
+
+	// returned stakes reference the argument they were staked on
+	for _, transaction := range ta.appAccountTransactionsResolver(ctx, queryByAddress{ID: q.ID}) {
+		switch transaction.Type {
+		case bank.TransactionBackingReturned, bank.TransactionChallengeReturned, bank.TransactionUpvoteReturned:
+			position, ok := positions[argumentClaims[transaction.ReferenceID]]
+			if ok {
+				position.Returned = position.Returned.Add(transaction.Amount)
+			}
+		}
+	}
+
+	list := make([]StakePosition, 0, len(positions))
+	for _, position := range positions {
+		list = append(list, *position)
+	}
+	limit := int(q.Limit)
+	if limit == 0 {
+		limit = defaultStakePositionsLimit
+	}
+	return paginateStakePositions(list, int(q.Offset), limit)
+}
@@ -0,0 +1,31 @@
+package truapi
+
+import (
+	"testing"
+
+	app "github.com/TruStory/truchain/types"
+	"github.com/TruStory/truchain/x/claim"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginateStakePositions(t *testing.T) {
+	position := func(claimID uint64, total int64) StakePosition {
+		return StakePosition{Claim: claim.Claim{ID: claimID}, Total: sdk.NewInt64Coin(app.StakeDenom, total)}
+	}
+	positions := []StakePosition{position(1, 10), position(2, 30), position(3, 20), position(4, 30)}
+
+	page := paginateStakePositions(positions, 0, 3)
+	assert.Len(t, page, 3)
+	assert.Equal(t, uint64(4), page[0].Claim.ID)
+	assert.Equal(t, uint64(2), page[1].Claim.ID)
+	assert.Equal(t, uint64(3), page[2].Claim.ID)
+
+	page = paginateStakePositions(positions, 3, 3)
+	assert.Len(t, page, 1)
+	assert.Equal(t, uint64(1), page[0].Claim.ID)
+
+	assert.Len(t, paginateStakePositions(positions, 10, 3), 0)
+	assert.Len(t, paginateStakePositions(positions, -1, 2), 2)
+	assert.Len(t, paginateStakePositions(positions, 0, -1), 0)
+}
@@ -0,0 +1,83 @@
+package truapi
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/TruStory/truchain/x/staking"
+)
+
+const defaultTopArgumentsLimit = 10
+
+type queryTopArgumentsParams struct {
+	CommunityID string                `graphql:"communityId"`
+	DateFilter  LeaderboardDateFilter `graphql:"dateFilter,optional"`
+	Limit       int64                 `graphql:"limit,optional"`
+	Offset      int64                 `graphql:"offset,optional"`
+}
+
+// agreesSince counts the agrees each argument received since the given time
+func agreesSince(stakes []staking.Stake, since time.Time) map[uint64]int {
+	agrees := make(map[uint64]int)
+	for _, stake := range stakes {
+		if stake.Type == staking.StakeUpvote && !stake.CreatedTime.Before(since) {
+			agrees[stake.ArgumentID]++
+		}
+	}
+	return agrees
+}
+
+// rankArguments returns a page of the helpful arguments created since the given time,
+// ranked by the agrees they received in that time and then by amount staked
+func rankArguments(arguments []staking.Argument, agrees map[uint64]int, since time.Time, offset, limit int) []staking.Argument {
+	ranked := make([]staking.Argument, 0)
+	for _, argument := range arguments {
+		if argument.IsUnhelpful || argument.CreatedTime.Before(since) {
+			continue
+		}
+		ranked = append(ranked, argument)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if agrees[ranked[i].ID] != agrees[ranked[j].ID] {
+			return agrees[ranked[i].ID] > agrees[ranked[j].ID]
+		}
+		if !ranked[i].TotalStake.Amount.Equal(ranked[j].TotalStake.Amount) {
+			return ranked[i].TotalStake.Amount.GT(ranked[j].TotalStake.Amount)
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	start, end := pageBounds(len(ranked), offset, limit)
+	return ranked[start:end]
+}
+
+func (ta *TruAPI) topArgumentsResolver(ctx context.Context, q queryTopArgumentsParams) []staking.Argument {
+	since := getZeroHour(time.Now().Add(q.DateFilter.Value()))
+	// all time
+	if q.DateFilter.Value() == 0 {
+		since = time.Time{}
+	}
+	limit := int(q.Limit)
+	if limit == 0 {
+		limit = defaultTopArgumentsLimit
+	}
+
+	claims := ta.claimsResolver(ctx, queryByCommunityIDAndFeedFilter{CommunityID: q.CommunityID, IsSearch: true})
+	arguments := make([]staking.Argument, 0)
+	stakes := make([]staking.Stake, 0)
+	for _, c := range claims {
+		arguments = append(arguments, ta.claimArgumentsResolver(ctx, queryClaimArgumentParams{ClaimID: c.ID})...)
+		stakes = append(stakes, ta.memoizedClaimStakes(ctx, c.ID)...)
+	}
+	topArguments := rankArguments(arguments, agreesSince(stakes, since), since, int(q.Offset), limit)
+
+	// load the creators in a single batch
+	if loaders, ok := getDataLoaders(ctx); ok {
+		creators := make([]string, 0, len(topArguments))
+		for _, argument := range topArguments {
+			creators = append(creators, argument.Creator.String())
+		}
+		loaders.appAccountLoader.LoadAll(creators)
+	}
+	return topArguments
+}
@@ -0,0 +1,65 @@
+package truapi
+
+import (
+	"testing"
+	"time"
+
+	app "github.com/TruStory/truchain/types"
+	"github.com/TruStory/truchain/x/staking"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankArguments(t *testing.T) {
+	now := time.Now()
+	argument := func(id uint64, agrees int, stake int64, created time.Time) staking.Argument {
+		return staking.Argument{ID: id, UpvotedCount: agrees, TotalStake: sdk.NewInt64Coin(app.StakeDenom, stake), CreatedTime: created}
+	}
+	unhelpful := argument(5, 100, 100, now)
+	unhelpful.IsUnhelpful = true
+	arguments := []staking.Argument{
+		argument(1, 2, 50, now),
+		argument(2, 5, 10, now),
+		argument(3, 2, 80, now),
+		argument(4, 9, 90, now.AddDate(0, 0, -10)),
+		unhelpful,
+	}
+
+	agrees := map[uint64]int{1: 2, 2: 5, 3: 2, 4: 9, 5: 100}
+
+	ranked := rankArguments(arguments, agrees, now.AddDate(0, 0, -7), 0, 10)
+	ids := make([]uint64, 0)
+	for _, a := range ranked {
+		ids = append(ids, a.ID)
+	}
+	assert.Equal(t, []uint64{2, 3, 1}, ids)
+
+	ranked = rankArguments(arguments, agrees, time.Time{}, 1, 2)
+	assert.Len(t, ranked, 2)
+	assert.Equal(t, uint64(2), ranked[0].ID)
+	assert.Equal(t, uint64(3), ranked[1].ID)
+
+	assert.Len(t, rankArguments(arguments, agrees, time.Time{}, 10, 2), 0)
+	assert.Len(t, rankArguments(arguments, agrees, time.Time{}, -1, 2), 2)
+	assert.Len(t, rankArguments(arguments, agrees, time.Time{}, 0, -2), 0)
+
+	// the agrees received in the window rank the arguments, not their lifetime count
+	ranked = rankArguments(arguments, map[uint64]int{1: 4, 2: 1}, now.AddDate(0, 0, -7), 0, 10)
+	assert.Equal(t, uint64(1), ranked[0].ID)
+	assert.Equal(t, uint64(2), ranked[1].ID)
+}
+
+func TestAgreesSince(t *testing.T) {
+	now := time.Now()
+	stake := func(argumentID uint64, stakeType staking.StakeType, created time.Time) staking.Stake {
+		return staking.Stake{ArgumentID: argumentID, Type: stakeType, CreatedTime: created}
+	}
+	stakes := []staking.Stake{
+		stake(1, staking.StakeUpvote, now),
+		stake(1, staking.StakeUpvote, now.AddDate(0, 0, -10)),
+		stake(1, staking.StakeBacking, now),
+		stake(2, staking.StakeUpvote, now.AddDate(0, 0, -1)),
+	}
+	assert.Equal(t, map[uint64]int{1: 1, 2: 1}, agreesSince(stakes, now.AddDate(0, 0, -7)))
+	assert.Equal(t, map[uint64]int{1: 2, 2: 1}, agreesSince(stakes, time.Time{}))
+}
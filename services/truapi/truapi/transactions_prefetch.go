@@ -0,0 +1,41 @@
+package truapi
+
+import (
+	"context"
+
+	"github.com/TruStory/truchain/x/bank"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// transactionsByAddress loads every transaction on the chain with a single query of the bank store,
+// grouped by the address of their app account
+func (ta *TruAPI) transactionsByAddress(ctx context.Context) (map[string][]bank.Transaction, error) {
+	return loadTransactions(ctx, ta.QuerySubspace)
+}
+
+// loadTransactions queries the transactions of the bank store with querySubspace and groups them by address
+func loadTransactions(
+	ctx context.Context,
+	querySubspace func(ctx context.Context, storeName string, prefix []byte) ([]sdk.KVPair, error),
+) (map[string][]bank.Transaction, error) {
+	pairs, err := querySubspace(ctx, bank.StoreKey, bank.TransactionsKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return groupTransactions(pairs)
+}
+
+// groupTransactions decodes the transactions stored in the bank store and groups them by address
+func groupTransactions(pairs []sdk.KVPair) (map[string][]bank.Transaction, error) {
+	transactions := make(map[string][]bank.Transaction)
+	for _, pair := range pairs {
+		var transaction bank.Transaction
+		err := bank.ModuleCodec.UnmarshalBinaryBare(pair.Value, &transaction)
+		if err != nil {
+			return nil, err
+		}
+		address := transaction.AppAccountAddress.String()
+		transactions[address] = append(transactions[address], transaction)
+	}
+	return transactions, nil
+}
@@ -0,0 +1,73 @@
+package truapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TruStory/truchain/x/bank"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupTransactions(t *testing.T) {
+	alice := sdk.AccAddress([]byte("alice"))
+	bob := sdk.AccAddress([]byte("bob"))
+	stored := []bank.Transaction{
+		{ID: 1, AppAccountAddress: alice, Amount: sdk.NewInt64Coin("utru", 10)},
+		{ID: 2, AppAccountAddress: bob, Amount: sdk.NewInt64Coin("utru", 20)},
+		{ID: 3, AppAccountAddress: alice, Amount: sdk.NewInt64Coin("utru", 30)},
+	}
+	transactions, err := groupTransactions(transactionPairs(stored))
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 2)
+	assert.Len(t, transactions[alice.String()], 2)
+	assert.Equal(t, uint64(3), transactions[alice.String()][1].ID)
+	assert.Equal(t, int64(20), transactions[bob.String()][0].Amount.Amount.Int64())
+
+	_, err = groupTransactions([]sdk.KVPair{{Key: []byte{0}, Value: []byte("garbage")}})
+	assert.Error(t, err)
+}
+
+// transactionPairs stores the transactions the way the bank store does
+func transactionPairs(transactions []bank.Transaction) []sdk.KVPair {
+	pairs := make([]sdk.KVPair, 0, len(transactions))
+	for _, transaction := range transactions {
+		pairs = append(pairs, sdk.KVPair{
+			Key:   append(bank.TransactionsKeyPrefix, sdk.Uint64ToBigEndian(transaction.ID)...),
+			Value: bank.ModuleCodec.MustMarshalBinaryBare(transaction),
+		})
+	}
+	return pairs
+}
+
+// BenchmarkTransactionsByAddress reports the node queries made to load the transactions of every user,
+// which stays at one per run regardless of the number of users.
+func BenchmarkTransactionsByAddress(b *testing.B) {
+	const users = 1000
+	transactions := make([]bank.Transaction, 0, users*2)
+	for i := 0; i < users*2; i++ {
+		address := sdk.AccAddress(sdk.Uint64ToBigEndian(uint64(i % users)))
+		transactions = append(transactions, bank.Transaction{ID: uint64(i), AppAccountAddress: address, Amount: sdk.NewInt64Coin("utru", 10)})
+	}
+	pairs := transactionPairs(transactions)
+
+	queries := 0
+	querySubspace := func(ctx context.Context, storeName string, prefix []byte) ([]sdk.KVPair, error) {
+		queries++
+		return pairs, nil
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		byAddress, err := loadTransactions(context.Background(), querySubspace)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(byAddress) != users {
+			b.Fatalf("expected the transactions of %d users, got %d", users, len(byAddress))
+		}
+	}
+	if queries != b.N {
+		b.Fatalf("expected one query per run, got %d queries for %d runs", queries, b.N)
+	}
+	b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+}
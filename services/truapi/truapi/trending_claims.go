@@ -0,0 +1,226 @@
+package truapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/TruStory/truchain/x/claim"
+	"github.com/TruStory/truchain/x/staking"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+const (
+	defaultTrendingClaimsLimit       = 10
+	maxTrendingClaimsLimit           = 100
+	defaultTrendingClaimsWindowHours = 72
+
+	// weights of each kind of engagement in the trending score of a claim
+	trendingArgumentWeight    = 3.0
+	trendingParticipantWeight = 2.0
+	trendingCommentWeight     = 1.0
+)
+
+type queryTrendingClaimsParams struct {
+	CommunityID string `graphql:"communityId,optional"`
+	Limit       int64  `graphql:"limit,optional"`
+	WindowHours int64  `graphql:"windowHours,optional"`
+}
+
+// trendingWindow is the period over which the engagement on claims is scored
+type trendingWindow struct {
+	since time.Time
+	now   time.Time
+}
+
+// weight of an engagement at the given time, from 0 at the start of the window up to 1 now
+func (w trendingWindow) weight(t time.Time) float64 {
+	if t.Before(w.since) {
+		return 0
+	}
+	if t.After(w.now) {
+		return 1
+	}
+	return t.Sub(w.since).Seconds() / w.now.Sub(w.since).Seconds()
+}
+
+// weightSum of the engagements made elapsedSeconds after the start of the window, summed up
+func (w trendingWindow) weightSum(elapsedSeconds float64) float64 {
+	return elapsedSeconds / w.now.Sub(w.since).Seconds()
+}
+
+// claimEngagementScores scores claims by the arguments, participants and comments they got during the window,
+// each weighted by how recent it is. Stakes are attributed to claims through their arguments, and every
+// participant counts once per claim, at their latest stake or comment.
+func claimEngagementScores(window trendingWindow, arguments []staking.Argument, stakes []staking.Stake, commenters []db.CommenterActivity) map[uint64]float64 {
+	scores := make(map[uint64]float64)
+	argumentClaimIDs := make(map[uint64]uint64, len(arguments))
+	for _, argument := range arguments {
+		argumentClaimIDs[argument.ID] = argument.ClaimID
+		scores[argument.ClaimID] += trendingArgumentWeight * window.weight(argument.CreatedTime)
+	}
+
+	type participant struct {
+		claimID uint64
+		address string
+	}
+	lastParticipation := make(map[participant]time.Time)
+	participate := func(claimID uint64, address string, t time.Time) {
+		p := participant{claimID: claimID, address: address}
+		if t.After(lastParticipation[p]) {
+			lastParticipation[p] = t
+		}
+	}
+	for _, stake := range stakes {
+		claimID, ok := argumentClaimIDs[stake.ArgumentID]
+		if !ok {
+			continue
+		}
+		participate(claimID, stake.Creator.String(), stake.CreatedTime)
+	}
+	for _, commenter := range commenters {
+		claimID := uint64(commenter.ClaimID)
+		scores[claimID] += trendingCommentWeight * window.weightSum(commenter.ElapsedSeconds)
+		participate(claimID, commenter.Creator, commenter.LastCommentedAt)
+	}
+	for p, t := range lastParticipation {
+		scores[p.claimID] += trendingParticipantWeight * window.weight(t)
+	}
+	return scores
+}
+
+// rankTrendingClaims returns up to limit claims with some engagement, highest score first
+// and newest first among equal scores
+func rankTrendingClaims(claims []claim.Claim, scores map[uint64]float64, limit int) []claim.Claim {
+	ranked := make([]claim.Claim, 0)
+	for _, c := range claims {
+		if scores[c.ID] > 0 {
+			ranked = append(ranked, c)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if scores[ranked[i].ID] != scores[ranked[j].ID] {
+			return scores[ranked[i].ID] > scores[ranked[j].ID]
+		}
+		if !ranked[i].CreatedTime.Equal(ranked[j].CreatedTime) {
+			return ranked[i].CreatedTime.After(ranked[j].CreatedTime)
+		}
+		return ranked[i].ID > ranked[j].ID
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// trendingClaimsLimit defaults a missing or negative limit and caps it
+func trendingClaimsLimit(limit int64) int {
+	if limit <= 0 {
+		return defaultTrendingClaimsLimit
+	}
+	if limit > maxTrendingClaimsLimit {
+		return maxTrendingClaimsLimit
+	}
+	return int(limit)
+}
+
+// trendingClaimsResolver ranks the claims of a community by their recent engagement.
+// Engagement is fetched in batches, with one stakes query per community rather than queries per claim.
+func (ta *TruAPI) trendingClaimsResolver(ctx context.Context, q queryTrendingClaimsParams) ([]claim.Claim, error) {
+	if q.WindowHours < 0 {
+		return nil, errors.New("windowHours cannot be negative")
+	}
+	communityID := q.CommunityID
+	if communityID == "" {
+		communityID = "all"
+	}
+	limit := trendingClaimsLimit(q.Limit)
+	windowHours := q.WindowHours
+	if windowHours == 0 {
+		windowHours = defaultTrendingClaimsWindowHours
+	}
+	now := time.Now()
+	window := trendingWindow{since: now.Add(-time.Duration(windowHours) * time.Hour), now: now}
+
+	claims := ta.claimsResolver(ctx, queryByCommunityIDAndFeedFilter{CommunityID: communityID, IsSearch: true})
+	communityIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, c := range claims {
+		if !seen[c.CommunityID] {
+			seen[c.CommunityID] = true
+			communityIDs = append(communityIDs, c.CommunityID)
+		}
+	}
+
+	stakes, err := ta.communityStakesSince(ctx, communityIDs, window.since)
+	if err != nil {
+		fmt.Println("trendingClaimsResolver err: ", err)
+		return []claim.Claim{}, nil
+	}
+	argumentIDs := make([]uint64, 0)
+	seenArguments := make(map[uint64]bool)
+	for _, stake := range stakes {
+		if !seenArguments[stake.ArgumentID] {
+			seenArguments[stake.ArgumentID] = true
+			argumentIDs = append(argumentIDs, stake.ArgumentID)
+		}
+	}
+	arguments, err := ta.argumentsByIDs(ctx, argumentIDs)
+	if err != nil {
+		fmt.Println("trendingClaimsResolver err: ", err)
+		return []claim.Claim{}, nil
+	}
+	commenters, err := ta.DBClient.CommenterActivitySince(window.since, window.now)
+	if err != nil {
+		fmt.Println("trendingClaimsResolver err: ", err)
+		return []claim.Claim{}, nil
+	}
+
+	scores := claimEngagementScores(window, arguments, stakes, commenters)
+	return rankTrendingClaims(claims, scores, limit), nil
+}
+
+// communityStakesSince returns the stakes made in the communities since the given time
+func (ta *TruAPI) communityStakesSince(ctx context.Context, communityIDs []string, since time.Time) ([]staking.Stake, error) {
+	recent := make([]staking.Stake, 0)
+	queryRoute := path.Join(staking.QuerierRoute, staking.QueryCommunityStakes)
+	for _, communityID := range communityIDs {
+		res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryCommunityStakesParams{CommunityID: communityID}, staking.ModuleCodec)
+		if err != nil {
+			return nil, err
+		}
+		stakes := make([]staking.Stake, 0)
+		err = staking.ModuleCodec.UnmarshalJSON(res, &stakes)
+		if err != nil {
+			return nil, err
+		}
+		for _, stake := range stakes {
+			if !stake.CreatedTime.Before(since) {
+				recent = append(recent, stake)
+			}
+		}
+	}
+	return recent, nil
+}
+
+// argumentsByIDs fetches arguments in a single query
+func (ta *TruAPI) argumentsByIDs(ctx context.Context, ids []uint64) ([]staking.Argument, error) {
+	arguments := make([]staking.Argument, 0)
+	if len(ids) == 0 {
+		return arguments, nil
+	}
+	queryRoute := path.Join(staking.QuerierRoute, staking.QueryArgumentsByIDs)
+	res, err := ta.QueryWithContext(ctx, queryRoute, staking.QueryArgumentsByIDsParams{ArgumentIDs: ids}, staking.ModuleCodec)
+	if err != nil {
+		return nil, err
+	}
+	err = staking.ModuleCodec.UnmarshalJSON(res, &arguments)
+	if err != nil {
+		return nil, err
+	}
+	return arguments, nil
+}
@@ -0,0 +1,83 @@
+package truapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TruStory/truchain/x/claim"
+	"github.com/TruStory/truchain/x/staking"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+)
+
+func TestTrendingClaims(t *testing.T) {
+	now := time.Now()
+	window := trendingWindow{since: now.Add(-72 * time.Hour), now: now}
+	hoursAgo := func(hours int) time.Time { return now.Add(-time.Duration(hours) * time.Hour) }
+	address := func(name string) sdk.AccAddress { return sdk.AccAddress([]byte(name)) }
+
+	claims := []claim.Claim{
+		{ID: 1, CreatedTime: hoursAgo(500)},
+		{ID: 2, CreatedTime: hoursAgo(10)},
+		{ID: 3, CreatedTime: hoursAgo(48)},
+		{ID: 4, CreatedTime: hoursAgo(2)},
+		{ID: 5, CreatedTime: hoursAgo(30)},
+	}
+	arguments := []staking.Argument{
+		// an old claim with a fresh argument agreed on
+		{ID: 10, ClaimID: 1, CreatedTime: hoursAgo(1)},
+		// an argument from before the window, only its recent agree counts
+		{ID: 11, ClaimID: 2, CreatedTime: hoursAgo(100)},
+		// the same engagement as claim 1, but two days ago
+		{ID: 12, ClaimID: 3, CreatedTime: hoursAgo(49)},
+	}
+	stakes := []staking.Stake{
+		{ArgumentID: 10, Creator: address("alice"), CreatedTime: hoursAgo(1)},
+		{ArgumentID: 10, Creator: address("bob"), CreatedTime: hoursAgo(1)},
+		{ArgumentID: 11, Creator: address("bob"), CreatedTime: hoursAgo(5)},
+		{ArgumentID: 12, Creator: address("alice"), CreatedTime: hoursAgo(49)},
+		{ArgumentID: 12, Creator: address("bob"), CreatedTime: hoursAgo(49)},
+	}
+	elapsed := func(times ...time.Time) float64 {
+		var seconds float64
+		for _, t := range times {
+			seconds += t.Sub(window.since).Seconds()
+		}
+		return seconds
+	}
+	commenters := []db.CommenterActivity{
+		{ClaimID: 2, Creator: "carol", Comments: 2, ElapsedSeconds: elapsed(hoursAgo(5), hoursAgo(4)), LastCommentedAt: hoursAgo(4)},
+		{ClaimID: 5, Creator: "dave", Comments: 1, ElapsedSeconds: elapsed(hoursAgo(30)), LastCommentedAt: hoursAgo(30)},
+	}
+
+	scores := claimEngagementScores(window, arguments, stakes, commenters)
+	ids := func(claims []claim.Claim) []uint64 {
+		ids := make([]uint64, 0, len(claims))
+		for _, c := range claims {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	// claim 4 has no engagement and isn't trending
+	assert.Equal(t, []uint64{1, 2, 3, 5}, ids(rankTrendingClaims(claims, scores, 10)))
+	assert.Equal(t, []uint64{1, 2}, ids(rankTrendingClaims(claims, scores, 2)))
+	// each participant counts once, at their latest activity:
+	// two comments by carol, and bob agreeing on an argument from before the window
+	recent := window.weight(hoursAgo(5)) + window.weight(hoursAgo(4))
+	assert.InDelta(t, (trendingCommentWeight+trendingParticipantWeight)*recent, scores[2], 1e-9)
+	assert.Zero(t, window.weight(hoursAgo(100)))
+}
+
+func TestTrendingClaimsParams(t *testing.T) {
+	assert.Equal(t, defaultTrendingClaimsLimit, trendingClaimsLimit(0))
+	assert.Equal(t, defaultTrendingClaimsLimit, trendingClaimsLimit(-5))
+	assert.Equal(t, 3, trendingClaimsLimit(3))
+	assert.Equal(t, maxTrendingClaimsLimit, trendingClaimsLimit(1000))
+
+	_, err := (&TruAPI{}).trendingClaimsResolver(context.Background(), queryTrendingClaimsParams{WindowHours: -1})
+	assert.Error(t, err)
+}
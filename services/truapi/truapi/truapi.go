@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"net/url"
 	"path"
-	"strings"
 	"time"
 
 	app "github.com/TruStory/truchain/types"
@@ -55,6 +54,7 @@ const (
 type dataLoaders struct {
 	appAccountLoader  *AppAccountLoader
 	userProfileLoader *UserProfileLoader
+	claimStakes       *claimStakesCache
 }
 
 // TruAPI implements an HTTP server for TruStory functionality using `chttp.API`
@@ -70,7 +70,14 @@ type TruAPI struct {
 	notificationsInitialized bool
 	commentsNotificationsCh  chan CommentNotificationRequest
 	broadcastNotificationsCh chan BroadcastNotificationRequest
+	reactionsNotificationsCh chan ReactionNotificationRequest
+	commentsOutbox           *notificationOutbox
+	broadcastOutbox          *notificationOutbox
+	reactionsOutbox          *notificationOutbox
 	httpClient               *http.Client
+
+	participationCache *participationCache
+	loginRateLimiter   *loginRateLimiter
 }
 
 // NewTruAPI returns a `TruAPI` instance populated with the existing app and a new GraphQL client
@@ -86,10 +93,20 @@ func NewTruAPI(apiCtx truCtx.TruAPIContext) *TruAPI {
 	if err != nil {
 		log.Fatal(err)
 	}
+	httpClient := newHTTPClient(apiCtx.Config.HTTPClient)
 	dripperService, err := dripper.NewDripper(apiCtx.Config)
 	if err != nil {
 		log.Fatal(err)
 	}
+	dripperService.HTTPClient = httpClient
+	bufferSize := apiCtx.Config.Push.NotificationsBufferSize
+	if bufferSize == 0 {
+		bufferSize = defaultNotificationsBufferSize
+	}
+	outboxSize := apiCtx.Config.Push.NotificationsOutboxSize
+	if outboxSize == 0 {
+		outboxSize = defaultNotificationsOutboxSize
+	}
 	ta := TruAPI{
 		API:                      chttp.NewAPI(apiCtx, supported),
 		APIContext:               apiCtx,
@@ -97,11 +114,15 @@ func NewTruAPI(apiCtx truCtx.TruAPIContext) *TruAPI {
 		DBClient:                 db.NewDBClient(apiCtx.Config),
 		Postman:                  postmanService,
 		Dripper:                  dripperService,
-		commentsNotificationsCh:  make(chan CommentNotificationRequest),
-		broadcastNotificationsCh: make(chan BroadcastNotificationRequest),
-		httpClient: &http.Client{
-			Timeout: time.Second * 5,
-		},
+		commentsNotificationsCh:  make(chan CommentNotificationRequest, bufferSize),
+		broadcastNotificationsCh: make(chan BroadcastNotificationRequest, bufferSize),
+		reactionsNotificationsCh: make(chan ReactionNotificationRequest, bufferSize),
+		commentsOutbox:           newNotificationOutbox(outboxSize),
+		broadcastOutbox:          newNotificationOutbox(outboxSize),
+		reactionsOutbox:          newNotificationOutbox(outboxSize),
+		httpClient:               httpClient,
+		participationCache:       &participationCache{},
+		loginRateLimiter:         newLoginRateLimiter(),
 	}
 
 	return &ta
@@ -112,6 +133,16 @@ func (ta *TruAPI) RunNotificationSender(apiCtx truCtx.TruAPIContext) error {
 	ta.notificationsInitialized = true
 	go ta.runCommentNotificationSender(ta.commentsNotificationsCh, apiCtx.Config.Push.EndpointURL)
 	go ta.runBroadcastNotificationSender(ta.broadcastNotificationsCh, apiCtx.Config.Push.EndpointURL)
+	go ta.runReactionNotificationSender(ta.reactionsNotificationsCh, apiCtx.Config.Push.EndpointURL)
+	go ta.commentsOutbox.run(func(n interface{}) {
+		ta.commentsNotificationsCh <- n.(CommentNotificationRequest)
+	})
+	go ta.broadcastOutbox.run(func(n interface{}) {
+		ta.broadcastNotificationsCh <- n.(BroadcastNotificationRequest)
+	})
+	go ta.reactionsOutbox.run(func(n interface{}) {
+		ta.reactionsNotificationsCh <- n.(ReactionNotificationRequest)
+	})
 	return nil
 }
 
@@ -120,30 +151,78 @@ func (ta *TruAPI) RunLeaderboardScheduler(apiCtx truCtx.TruAPIContext) {
 	go ta.leaderboardScheduler()
 }
 
+// RunNotificationPruner runs the pruning of old read notifications in the background.
+func (ta *TruAPI) RunNotificationPruner(apiCtx truCtx.TruAPIContext) {
+	go ta.notificationPruner()
+}
+
+// RunClaimSearchIndexer runs the indexing of claim bodies for search in the background.
+func (ta *TruAPI) RunClaimSearchIndexer(apiCtx truCtx.TruAPIContext) {
+	go ta.claimSearchIndexer()
+}
+
 // WrapHandler wraps a chttp.Handler and returns a standar http.Handler
 func WrapHandler(h chttp.Handler) http.Handler {
 	return h.HandlerFunc()
 }
 
+// WrapStreamHandler wraps a chttp.StreamHandler and returns a standard http.Handler
+func WrapStreamHandler(h chttp.StreamHandler) http.Handler {
+	return h.HandlerFunc()
+}
+
 // WithUser sets the user in the context that will be passed down to handlers.
-func WithUser(apiCtx truCtx.TruAPIContext) mux.MiddlewareFunc {
+// Sessions of deleted users, or revoked since they logged in, are logged out and their
+// cookie is dropped from the request, so that no handler sees it.
+func (ta *TruAPI) WithUser() mux.MiddlewareFunc {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			auth, err := cookies.GetAuthenticatedUser(apiCtx, r)
+			auth, err := cookies.GetAuthenticatedUser(ta.APIContext, r)
 			if err != nil {
 				h.ServeHTTP(w, r)
 				return
 			}
+			user, err := ta.DBClient.UserByID(auth.ID)
+			if err != nil {
+				// the session can't be checked, so it's kept rather than logging everyone out
+				fmt.Println("WithUser err: ", err)
+			}
+			if err == nil && sessionRevoked(user, auth) {
+				http.SetCookie(w, cookies.GetLogoutCookie(ta.APIContext))
+				h.ServeHTTP(w, withoutCookie(r, cookies.UserCookieName))
+				return
+			}
 			ctx := context.WithValue(r.Context(), userContextKey, auth)
 			h.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// sessionRevoked tells whether the session of a user can't be used anymore
+func sessionRevoked(user *db.User, auth *cookies.AuthenticatedUser) bool {
+	if user == nil || user.DeletedAt != nil {
+		return true
+	}
+	return user.SessionsRevokedAt != nil && !time.Unix(auth.AuthenticatedAt, 0).After(*user.SessionsRevokedAt)
+}
+
+// withoutCookie returns a copy of the request without the named cookie
+func withoutCookie(r *http.Request, name string) *http.Request {
+	stripped := r.Clone(r.Context())
+	stripped.Header.Del("Cookie")
+	for _, cookie := range r.Cookies() {
+		if cookie.Name != name {
+			stripped.AddCookie(cookie)
+		}
+	}
+	return stripped
+}
+
 func (ta *TruAPI) createContext(ctx context.Context) context.Context {
 	loaders := &dataLoaders{
 		appAccountLoader:  ta.AppAccountLoader(),
 		userProfileLoader: ta.UserProfileLoader(),
+		claimStakes:       &claimStakesCache{stakes: make(map[uint64][]staking.Stake)},
 	}
 	return context.WithValue(ctx, dataLoadersContextKey, loaders)
 }
@@ -196,6 +275,11 @@ func (ta *TruAPI) RegisterMutations() {
 		err := ta.DBClient.AddComment(&db.Comment{ParentID: args.Parent, Body: args.Body})
 		return err
 	})
+	ta.GraphQLClient.RegisterMutation("markClaimCommentsSeen", func(ctx context.Context, args struct {
+		ClaimID int64 `graphql:"claimId"`
+	}) error {
+		return ta.markClaimCommentsSeen(ctx, args.ClaimID)
+	})
 }
 
 // RegisterResolvers builds the app's GraphQL schema from resolvers (declared in `resolver.go`)
@@ -212,6 +296,15 @@ func (ta *TruAPI) RegisterResolvers() {
 		"amount":        func(_ context.Context, q sdk.Coin) string { return q.Amount.String() },
 		"denom":         func(_ context.Context, q sdk.Coin) string { return q.Denom },
 		"humanReadable": func(_ context.Context, q sdk.Coin) string { return HumanReadable(q) },
+		"humanReadableFormatted": func(_ context.Context, q sdk.Coin, args struct {
+			Decimals *int64 `graphql:"decimals"`
+		}) string {
+			decimalPlaces := displayDecimalPlaces(q)
+			if args.Decimals != nil {
+				decimalPlaces = int(*args.Decimals)
+			}
+			return HumanReadableFormatted(q, decimalPlaces)
+		},
 	})
 
 	ta.GraphQLClient.RegisterQueryResolver("invites", ta.invitesResolver)
@@ -230,21 +323,45 @@ func (ta *TruAPI) RegisterResolvers() {
 		"createdAt": func(_ context.Context, q db.Invite) time.Time { return q.CreatedAt },
 	})
 
+	ta.GraphQLClient.RegisterQueryResolver("rewardLedger", ta.rewardLedgerResolver)
+	ta.GraphQLClient.RegisterObjectResolver("RewardLedgerEntry", db.RewardLedgerEntry{}, map[string]interface{}{
+		"id":        func(_ context.Context, q db.RewardLedgerEntry) int64 { return q.ID },
+		"direction": func(_ context.Context, q db.RewardLedgerEntry) string { return string(q.Direction) },
+		"amount":    func(_ context.Context, q db.RewardLedgerEntry) int64 { return q.Amount },
+		"currency":  func(_ context.Context, q db.RewardLedgerEntry) string { return string(q.Currency) },
+		"createdAt": func(_ context.Context, q db.RewardLedgerEntry) time.Time { return q.CreatedAt },
+	})
+
 	ta.GraphQLClient.RegisterObjectResolver("URL", url.URL{}, map[string]interface{}{
 		"url": func(_ context.Context, q url.URL) string { return q.String() },
 	})
 
 	ta.GraphQLClient.RegisterQueryResolver("referredAppAccounts", ta.referredAppAccountsResolver)
+	ta.GraphQLClient.RegisterQueryResolver("referralTree", ta.referralTreeResolver)
+	ta.GraphQLClient.RegisterObjectResolver("ReferredUser", db.ReferredUser{}, map[string]interface{}{
+		"id":         func(_ context.Context, q db.ReferredUser) int64 { return q.ID },
+		"referredBy": func(_ context.Context, q db.ReferredUser) int64 { return q.ReferredBy },
+		"depth":      func(_ context.Context, q db.ReferredUser) int { return q.Depth },
+		"appAccount": func(ctx context.Context, q db.ReferredUser) *AppAccount {
+			// users who haven't verified their account yet have no address
+			if q.Address == "" {
+				return nil
+			}
+			return ta.appAccountResolver(ctx, queryByAddress{ID: q.Address})
+		},
+	})
+	ta.GraphQLClient.RegisterQueryResolver("appAccountPendingRewards", ta.appAccountPendingRewardsResolver)
 
 	ta.GraphQLClient.RegisterQueryResolver("appAccount", ta.appAccountResolver)
-	ta.GraphQLClient.RegisterObjectResolver("AppAccount", AppAccount{}, map[string]interface{}{
+	ta.GraphQLClient.RegisterPaginatedObjectResolver("AppAccount", "address", AppAccount{}, map[string]interface{}{
 		"id": func(_ context.Context, q AppAccount) string { return q.Address },
 		"availableBalance": func(_ context.Context, q AppAccount) sdk.Coin {
 			return sdk.NewCoin(app.StakeDenom, q.Coins.AmountOf(app.StakeDenom))
 		},
-		"totalClaims": func(ctx context.Context, q AppAccount) int {
-			return len(ta.appAccountClaimsCreatedResolver(ctx, queryByAddress{ID: q.Address}))
-		},
+		"totalClaims": optionalField("AppAccount.totalClaims", func(ctx context.Context, q AppAccount) *int {
+			totalClaims := len(ta.appAccountClaimsCreatedResolver(ctx, queryByAddress{ID: q.Address}))
+			return &totalClaims
+		}),
 		"totalArguments": func(ctx context.Context, q AppAccount) int {
 			return len(ta.appAccountArgumentsResolver(ctx, queryByAddress{ID: q.Address}))
 		},
@@ -266,17 +383,8 @@ func (ta *TruAPI) RegisterResolvers() {
 		"pendingStake": func(ctx context.Context, q AppAccount) []EarnedCoin {
 			return ta.pendingStakeResolver(ctx, queryByAddress{ID: q.Address})
 		},
-		"userProfile": func(ctx context.Context, q AppAccount) *db.UserProfile {
-			return ta.userProfileResolver(ctx, q.Address)
-		},
-		"userJourney": func(ctx context.Context, q AppAccount) []db.UserJourneyStep {
-			user := ta.userResolver(ctx, q.Address)
-			if user == nil {
-				return []db.UserJourneyStep{}
-			}
-
-			return user.Meta.Journey
-		},
+		"userProfile": optionalField("AppAccount.userProfile", ta.appAccountUserProfileResolver),
+		"userJourney": optionalField("AppAccount.userJourney", ta.userJourneyResolver),
 		// deprecated, use "userProfile" instead
 		"twitterProfile": func(ctx context.Context, q AppAccount) db.TwitterProfile {
 			return ta.twitterProfileResolver(ctx, q.Address)
@@ -284,17 +392,15 @@ func (ta *TruAPI) RegisterResolvers() {
 	})
 
 	ta.GraphQLClient.RegisterObjectResolver("TwitterProfile", db.TwitterProfile{}, map[string]interface{}{
-		"id": func(_ context.Context, q db.TwitterProfile) string { return string(q.ID) },
+		"id": func(_ context.Context, q db.TwitterProfile) string { return string(rune(q.ID)) },
 		"avatarURI": func(_ context.Context, q db.TwitterProfile) string {
-			largeURI := strings.Replace(q.AvatarURI, "_bigger", "_200x200", 1)
-			return strings.Replace(largeURI, "http://", "https://", 1)
+			return ta.upgradeAvatarURL(q.AvatarURI)
 		},
 	})
 
 	ta.GraphQLClient.RegisterObjectResolver("User", db.UserProfile{}, map[string]interface{}{
 		"avatarURL": func(_ context.Context, q db.UserProfile) string {
-			largeURI := strings.Replace(q.AvatarURL, "_bigger", "_200x200", 1)
-			return strings.Replace(largeURI, "http://", "https://", 1)
+			return ta.upgradeAvatarURL(q.AvatarURL)
 		},
 	})
 
@@ -313,8 +419,11 @@ func (ta *TruAPI) RegisterResolvers() {
 	})
 
 	ta.GraphQLClient.RegisterQueryResolver("appAccountEarnings", ta.appAccountEarningsResolver)
+	ta.GraphQLClient.RegisterQueryResolver("appAccountEarningsBetween", ta.appAccountEarningsBetweenResolver)
+	ta.GraphQLClient.RegisterQueryResolver("appAccountStakePositions", ta.appAccountStakePositionsResolver)
 
 	ta.GraphQLClient.RegisterQueryResolver("leaderboard", ta.leaderboardResolver)
+	ta.GraphQLClient.RegisterQueryResolver("communityLeaderboard", ta.communityLeaderboardResolver)
 	ta.GraphQLClient.RegisterObjectResolver("LeaderboardTopUser", db.LeaderboardTopUser{}, map[string]interface{}{
 		"account": func(ctx context.Context, t db.LeaderboardTopUser) *AppAccount {
 			return ta.appAccountResolver(ctx, queryByAddress{ID: t.Address})
@@ -325,15 +434,14 @@ func (ta *TruAPI) RegisterResolvers() {
 	})
 
 	ta.GraphQLClient.RegisterQueryResolver("communities", ta.communitiesResolver)
+	ta.GraphQLClient.RegisterQueryResolver("recentCommunityMembers", ta.recentCommunityMembersResolver)
+	ta.GraphQLClient.RegisterQueryResolver("usersByGroup", ta.usersByGroupResolver)
+	ta.GraphQLClient.RegisterQueryResolver("userCommunityParticipation", ta.userCommunityParticipationResolver)
 	ta.GraphQLClient.RegisterQueryResolver("community", ta.communityResolver)
 	ta.GraphQLClient.RegisterObjectResolver("Community", community.Community{}, map[string]interface{}{
-		"id": func(_ context.Context, q community.Community) string { return q.ID },
-		"name": func(_ context.Context, q community.Community) string {
-			if contains(ta.APIContext.Config.Community.BetaCommunities, q.ID) {
-				return q.Name + " (Beta)"
-			}
-			return q.Name
-		},
+		"id":        func(_ context.Context, q community.Community) string { return q.ID },
+		"name":      ta.communityNameResolver,
+		"isBeta":    ta.isBetaCommunityResolver,
 		"iconImage": ta.communityIconImageResolver,
 		"heroImage": func(_ context.Context, q community.Community) string {
 			return joinPath(ta.APIContext.Config.App.S3AssetsURL, fmt.Sprintf("communities/%s_hero.jpg", q.ID))
@@ -341,7 +449,9 @@ func (ta *TruAPI) RegisterResolvers() {
 		"following": func(ctx context.Context, q community.Community) bool {
 			return ta.followsCommunity(ctx, queryByCommunityID{CommunityID: q.ID})
 		},
+		"followersCount": ta.communityFollowersCountResolver,
 	})
+	ta.GraphQLClient.RegisterPaginatedQueryResolver("followers", ta.communityFollowersResolver)
 
 	ta.GraphQLClient.RegisterPaginatedQueryResolverWithFilter("claims", ta.claimsResolver, map[string]interface{}{
 		"body": func(_ context.Context, q claim.Claim) string { return q.Body },
@@ -361,17 +471,17 @@ func (ta *TruAPI) RegisterResolvers() {
 		"arguments": func(ctx context.Context, q claim.Claim, a queryClaimArgumentParams) []staking.Argument {
 			return ta.claimArgumentsResolver(ctx, queryClaimArgumentParams{ClaimID: q.ID, Address: a.Address, Filter: a.Filter})
 		},
-		"participants":      ta.claimParticipantsResolver,
-		"participantsCount": func(ctx context.Context, q claim.Claim) int { return len(ta.claimParticipantsResolver(ctx, q)) },
-		"comments": func(ctx context.Context, q claim.Claim) []db.Comment {
-			return ta.commentsResolver(ctx, queryCommentsParams{ClaimID: &q.ID})
-		},
+		"participants":          ta.claimParticipantsResolver,
+		"participantsCount":     func(ctx context.Context, q claim.Claim) int { return len(ta.claimParticipantsResolver(ctx, q)) },
+		"participantsWithRoles": ta.claimParticipantsWithRolesResolver,
+		"comments": optionalField("Claim.comments", ta.claimCommentsResolver),
 		"creator": func(ctx context.Context, q claim.Claim) *AppAccount {
 			return ta.appAccountResolver(ctx, queryByAddress{ID: q.Creator.String()})
 		},
-		"commentCount": func(ctx context.Context, q claim.Claim) int {
-			return len(ta.commentsResolver(ctx, queryCommentsParams{ClaimID: &q.ID}))
-		},
+		"commentCount": optionalField("Claim.commentCount", ta.claimCommentCountResolver),
+		"viewerHasStaked": ta.viewerHasStakedResolver,
+		"viewerArgument":  ta.viewerArgumentResolver,
+		"tags":            ta.claimTagsResolver,
 
 		// deprecated
 		"sourceUrlPreview": ta.claimImageResolver,
@@ -379,6 +489,11 @@ func (ta *TruAPI) RegisterResolvers() {
 	})
 	ta.GraphQLClient.RegisterQueryResolver("claim", ta.claimResolver)
 	ta.GraphQLClient.RegisterQueryResolver("claimOfTheDay", ta.claimOfTheDayResolver)
+	ta.GraphQLClient.RegisterQueryResolver("claimsByTag", ta.claimsByTagResolver)
+	ta.GraphQLClient.RegisterQueryResolver("topArguments", ta.topArgumentsResolver)
+	ta.GraphQLClient.RegisterQueryResolver("trendingClaims", ta.trendingClaimsResolver)
+	ta.GraphQLClient.RegisterQueryResolver("searchClaims", ta.searchClaimsResolver)
+	ta.GraphQLClient.RegisterQueryResolver("featuredClaims", ta.featuredClaimsResolver)
 
 	ta.GraphQLClient.RegisterQueryResolver("claimArgument", ta.claimArgumentResolver)
 	ta.GraphQLClient.RegisterQueryResolver("claimArguments", ta.claimArgumentsResolver)
@@ -405,7 +520,10 @@ func (ta *TruAPI) RegisterResolvers() {
 			return ta.appAccountResolver(ctx, queryByAddress{ID: q.Creator.String()})
 		},
 		"appAccountStake": ta.appAccountStakeResolver,
+		"viewerStake":     ta.viewerStakeResolver,
 		"appAccountSlash": ta.appAccountSlashResolver,
+		"reactionCounts":  ta.argumentReactionCountsResolver,
+		"myReactions":     ta.argumentMyReactionsResolver,
 		"stakers":         ta.claimArgumentUpvoteStakersResolver,
 		"claim": func(ctx context.Context, q staking.Argument) *claim.Claim {
 			claim := ta.claimResolver(ctx, queryByClaimID{ID: q.ClaimID})
@@ -417,6 +535,7 @@ func (ta *TruAPI) RegisterResolvers() {
 	})
 
 	ta.GraphQLClient.RegisterPaginatedQueryResolver("comments", ta.commentsResolver)
+	ta.GraphQLClient.RegisterQueryResolver("keywordFlaggedComments", ta.keywordFlaggedCommentsResolver)
 	ta.GraphQLClient.RegisterPaginatedObjectResolver("Comment", "iD", db.Comment{}, map[string]interface{}{
 		"id":         func(_ context.Context, q db.Comment) int64 { return q.ID },
 		"parentId":   func(_ context.Context, q db.Comment) int64 { return q.ParentID },
@@ -428,6 +547,36 @@ func (ta *TruAPI) RegisterResolvers() {
 			return ta.appAccountResolver(ctx, queryByAddress{ID: q.Creator})
 		},
 		"createdAt": func(_ context.Context, q db.Comment) time.Time { return q.CreatedAt },
+		"updatedAt": func(_ context.Context, q db.Comment) time.Time { return q.UpdatedAt },
+	})
+
+	ta.GraphQLClient.RegisterQueryResolver("commentTree", ta.commentTreeResolver)
+	ta.GraphQLClient.RegisterQueryResolver("unreadCommentsCount", ta.unreadCommentsCountResolver)
+	ta.GraphQLClient.RegisterObjectResolver("CommentTree", CommentTree{}, map[string]interface{}{
+		"comments":      func(_ context.Context, q CommentTree) []*CommentTreeNode { return q.Comments },
+		"totalTopLevel": func(_ context.Context, q CommentTree) int { return q.TotalTopLevel },
+		"truncated":     func(_ context.Context, q CommentTree) bool { return q.Truncated },
+	})
+	ta.GraphQLClient.RegisterObjectResolver("CommentTreeNode", CommentTreeNode{}, map[string]interface{}{
+		"id":       func(_ context.Context, q CommentTreeNode) int64 { return q.Comment.ID },
+		"parentId": func(_ context.Context, q CommentTreeNode) int64 { return q.Comment.ParentID },
+		"body":     func(_ context.Context, q CommentTreeNode) string { return q.Comment.Body },
+		"creator": func(ctx context.Context, q CommentTreeNode) *AppAccount {
+			if q.Deleted {
+				return nil
+			}
+			return ta.appAccountResolver(ctx, queryByAddress{ID: q.Comment.Creator})
+		},
+		"createdAt": func(_ context.Context, q CommentTreeNode) time.Time { return q.Comment.CreatedAt },
+		"updatedAt": func(_ context.Context, q CommentTreeNode) time.Time { return q.Comment.UpdatedAt },
+		"deleted":   func(_ context.Context, q CommentTreeNode) bool { return q.Deleted },
+		"reactions": func(_ context.Context, q CommentTreeNode) []db.ReactionsCount { return q.Reactions },
+		"replies":   func(_ context.Context, q CommentTreeNode) []*CommentTreeNode { return q.Replies },
+		"truncated": func(_ context.Context, q CommentTreeNode) bool { return q.Truncated },
+	})
+	ta.GraphQLClient.RegisterObjectResolver("ReactionsCount", db.ReactionsCount{}, map[string]interface{}{
+		"type":  func(_ context.Context, q db.ReactionsCount) db.ReactionType { return q.Type },
+		"count": func(_ context.Context, q db.ReactionsCount) int64 { return q.Count },
 	})
 
 	ta.GraphQLClient.RegisterQueryResolver("claimQuestions", ta.claimQuestionsResolver)
@@ -483,9 +632,12 @@ func (ta *TruAPI) RegisterResolvers() {
 	ta.GraphQLClient.RegisterPaginatedQueryResolver("appAccountClaimsWithAgrees", ta.appAccountClaimsWithAgreesResolver)
 
 	ta.GraphQLClient.RegisterQueryResolver("settings", ta.settingsResolver)
-	ta.GraphQLClient.RegisterObjectResolver("Settings", Settings{}, map[string]interface{}{})
+	ta.GraphQLClient.RegisterObjectResolver("Settings", Settings{}, map[string]interface{}{
+		"featureFlags": ta.featureFlagsResolver,
+	})
 
 	ta.GraphQLClient.RegisterPaginatedQueryResolver("notifications", ta.notificationsResolver)
+	ta.GraphQLClient.RegisterPaginatedQueryResolver("mentionNotifications", ta.mentionNotificationsResolver)
 	ta.GraphQLClient.RegisterObjectResolver("NotificationMeta", db.NotificationMeta{}, map[string]interface{}{})
 	ta.GraphQLClient.RegisterPaginatedObjectResolver("NotificationEvent", "iD", db.NotificationEvent{}, map[string]interface{}{
 		"id": func(_ context.Context, q db.NotificationEvent) int64 { return q.ID },
@@ -521,9 +673,9 @@ func (ta *TruAPI) RegisterResolvers() {
 				return joinPath(ta.APIContext.Config.App.S3AssetsURL, path.Join("notifications", icon))
 			}
 			if q.SenderProfile != nil {
-				return strings.Replace(q.SenderProfile.AvatarURL, "http://", "https://", 1)
+				return ta.upgradeAvatarURL(q.SenderProfile.AvatarURL)
 			}
-			return strings.Replace(q.UserProfile.AvatarURL, "http://", "https://", 1)
+			return ta.upgradeAvatarURL(q.UserProfile.AvatarURL)
 		},
 		"meta": func(_ context.Context, q db.NotificationEvent) db.NotificationMeta {
 			return q.Meta
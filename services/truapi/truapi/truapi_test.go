@@ -0,0 +1,86 @@
+package truapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TruStory/octopus/services/truapi/db"
+	"github.com/TruStory/octopus/services/truapi/truapi/cookies"
+)
+
+func TestSessionRevoked(t *testing.T) {
+	loggedIn := time.Date(2019, 9, 12, 10, 0, 0, 0, time.UTC)
+	auth := &cookies.AuthenticatedUser{ID: 1, AuthenticatedAt: loggedIn.Unix()}
+	before, after := loggedIn.Add(-time.Hour), loggedIn.Add(time.Hour)
+
+	assert.False(t, sessionRevoked(&db.User{ID: 1}, auth))
+	assert.False(t, sessionRevoked(&db.User{ID: 1, SessionsRevokedAt: &before}, auth))
+	assert.True(t, sessionRevoked(&db.User{ID: 1, SessionsRevokedAt: &after}, auth))
+	assert.True(t, sessionRevoked(&db.User{ID: 1, Timestamps: db.Timestamps{DeletedAt: &before}}, auth))
+	assert.True(t, sessionRevoked(nil, auth))
+}
+
+func TestWithUserDropsRevokedSessions(t *testing.T) {
+	revokedAt := time.Now().Add(time.Hour)
+	user := &db.User{ID: 1, Address: "cosmos1alice"}
+	ta := newOnboardingTestAPI(user, "")
+	var seen *cookies.AuthenticatedUser
+	var cookieErr error
+	handler := ta.WithUser()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = r.Context().Value(userContextKey).(*cookies.AuthenticatedUser)
+		_, cookieErr = cookies.GetAuthenticatedUser(ta.APIContext, r)
+	}))
+	request := func() *http.Request {
+		r := resendOnboardingRequest(t, ta, user)
+		r.AddCookie(&http.Cookie{Name: "other", Value: "kept"})
+		return r
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, request())
+	assert.NotNil(t, seen)
+	assert.NoError(t, cookieErr)
+
+	user.SessionsRevokedAt = &revokedAt
+	w = httptest.NewRecorder()
+	r := request()
+	handler.ServeHTTP(w, r)
+	assert.Nil(t, seen)
+	assert.Error(t, cookieErr)
+	assert.Contains(t, w.Header().Get("Set-Cookie"), cookies.UserCookieName+"=")
+	// the original request is left as is
+	_, err := r.Cookie(cookies.UserCookieName)
+	assert.NoError(t, err)
+}
+
+type failingUserStore struct {
+	db.Datastore
+}
+
+func (s failingUserStore) UserByID(id int64) (*db.User, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestWithUserKeepsSessionsWhenTheDatabaseFails(t *testing.T) {
+	user := &db.User{ID: 1, Address: "cosmos1alice"}
+	ta := newOnboardingTestAPI(user, "")
+	r := resendOnboardingRequest(t, ta, user)
+	ta.DBClient = failingUserStore{}
+	var seen *cookies.AuthenticatedUser
+	var cookieErr error
+	handler := ta.WithUser()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = r.Context().Value(userContextKey).(*cookies.AuthenticatedUser)
+		_, cookieErr = cookies.GetAuthenticatedUser(ta.APIContext, r)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.NotNil(t, seen)
+	assert.NoError(t, cookieErr)
+	assert.Empty(t, w.Header().Get("Set-Cookie"))
+}
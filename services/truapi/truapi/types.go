@@ -112,6 +112,18 @@ type CommentNotificationRequest struct {
 	ElementID       int64     `json:"elementId"`
 	Creator         string    `json:"creator"`
 	Timestamp       time.Time `json:"timestamp"`
+	// MentionedUserIDs are the users mentioned in the comment, other than its creator
+	MentionedUserIDs []int64 `json:"mentionedUserIds,omitempty"`
+}
+
+// ReactionNotificationRequest is the payload sent to pushd for sending reaction notifications.
+type ReactionNotificationRequest struct {
+	// ID is the reaction id.
+	ID               int64               `json:"id"`
+	ReactionType     db.ReactionType     `json:"reaction_type"`
+	ReactionableType db.ReactionableType `json:"reactionable_type"`
+	ReactionableID   int64               `json:"reactionable_id"`
+	Creator          string              `json:"creator"`
 }
 
 // BroadcastNotificationRequest is the payload sent to pushd for broadcasting notifications.
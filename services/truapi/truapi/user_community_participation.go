@@ -0,0 +1,154 @@
+package truapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const participationCacheTTL = 5 * time.Minute
+
+// participationScanTimeout bounds the scan of the chain shared by the requests waiting for participation stats
+const participationScanTimeout = 2 * time.Minute
+
+// ParticipationStats represents a user's participation in a community
+type ParticipationStats struct {
+	Claims         int `json:"claims"`
+	Arguments      int `json:"arguments"`
+	AgreesGiven    int `json:"agreesGiven"`
+	AgreesReceived int `json:"agreesReceived"`
+}
+
+// CommunityParticipation represents a user's participation stats for a single community
+type CommunityParticipation struct {
+	CommunityID string             `json:"communityId"`
+	Stats       ParticipationStats `json:"stats"`
+}
+
+// participationCache holds the participation stats of every user, built from a single scan of the chain
+// and replaced once expired. Keeping one snapshot bounds the cache to the stats of existing users, and
+// concurrent requests on an expired cache wait for the same scan instead of starting their own.
+type participationCache struct {
+	mu        sync.Mutex
+	stats     map[string]map[string]ParticipationStats
+	expiresAt time.Time
+	scans     singleflight.Group
+}
+
+func (c *participationCache) get(now time.Time) (map[string]map[string]ParticipationStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stats == nil || now.After(c.expiresAt) {
+		return nil, false
+	}
+	return c.stats, true
+}
+
+func (c *participationCache) set(stats map[string]map[string]ParticipationStats, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = stats
+	c.expiresAt = now.Add(participationCacheTTL)
+}
+
+// load returns the cached snapshot, scanning a new one once it has expired. The scan is shared by concurrent
+// callers and runs with its own timeout, so a caller giving up doesn't fail the others waiting for it.
+func (c *participationCache) load(
+	ctx context.Context,
+	scan func(ctx context.Context, now time.Time) (map[string]map[string]ParticipationStats, error),
+) (map[string]map[string]ParticipationStats, error) {
+	stats, ok := c.get(time.Now())
+	if ok {
+		return stats, nil
+	}
+	scanned := c.scans.DoChan("participation", func() (interface{}, error) {
+		scanCtx, cancel := context.WithTimeout(context.Background(), participationScanTimeout)
+		defer cancel()
+		now := time.Now()
+		stats, err := scan(scanCtx, now)
+		if err != nil {
+			return nil, err
+		}
+		c.set(stats, now)
+		return stats, nil
+	})
+	select {
+	case result := <-scanned:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(map[string]map[string]ParticipationStats), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// UserCommunityParticipation returns the claims, arguments and agrees given/received per community for a user
+func (ta *TruAPI) UserCommunityParticipation(ctx context.Context, address string) (map[string]ParticipationStats, error) {
+	stats, err := ta.participationCache.load(ctx, func(ctx context.Context, now time.Time) (map[string]map[string]ParticipationStats, error) {
+		return ta.participationStats(ta.createContext(ctx), now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	userStats, ok := stats[address]
+	if !ok {
+		return make(map[string]ParticipationStats), nil
+	}
+	return userStats, nil
+}
+
+// participationStats returns the participation stats of every user by address, then by community
+func (ta *TruAPI) participationStats(ctx context.Context, now time.Time) (map[string]map[string]ParticipationStats, error) {
+	claims, err := ta.claimsBeforeTime(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	cache := ta.newClaimMetricsCache()
+	err = cache.prefetch(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	chainMetrics := &Metrics{UserMetrics: make(map[string]*UserMetrics)}
+	for _, claim := range claims {
+		err = ta.collectClaimMetrics(ctx, cache, chainMetrics, claim, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stats := make(map[string]map[string]ParticipationStats, len(chainMetrics.UserMetrics))
+	for address, userMetrics := range chainMetrics.UserMetrics {
+		userStats := make(map[string]ParticipationStats, len(userMetrics.CommunityMetrics))
+		for communityID, m := range userMetrics.CommunityMetrics {
+			userStats[communityID] = ParticipationStats{
+				Claims:         m.Claims,
+				Arguments:      m.Arguments,
+				AgreesGiven:    m.AgreesGiven,
+				AgreesReceived: m.AgreesReceived,
+			}
+		}
+		stats[address] = userStats
+	}
+	return stats, nil
+}
+
+func (ta *TruAPI) userCommunityParticipationResolver(ctx context.Context, q queryByAddress) []CommunityParticipation {
+	stats, err := ta.UserCommunityParticipation(ctx, q.ID)
+	if err != nil {
+		fmt.Println("userCommunityParticipationResolver err: ", err)
+		return make([]CommunityParticipation, 0)
+	}
+	participation := make([]CommunityParticipation, 0, len(stats))
+	for communityID, s := range stats {
+		participation = append(participation, CommunityParticipation{CommunityID: communityID, Stats: s})
+	}
+	sort.Slice(participation, func(i, j int) bool {
+		return participation[i].CommunityID < participation[j].CommunityID
+	})
+	return participation
+}
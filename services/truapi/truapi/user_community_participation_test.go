@@ -0,0 +1,66 @@
+package truapi
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParticipationCache(t *testing.T) {
+	var cache participationCache
+	now := time.Now()
+	_, ok := cache.get(now)
+	assert.False(t, ok)
+
+	stats := map[string]map[string]ParticipationStats{
+		"cosmos1alice": {"crypto": {Claims: 1, AgreesReceived: 2}},
+	}
+	cache.set(stats, now)
+	cached, ok := cache.get(now.Add(participationCacheTTL))
+	assert.True(t, ok)
+	assert.Equal(t, stats, cached)
+
+	// the whole snapshot expires at once
+	_, ok = cache.get(now.Add(participationCacheTTL + time.Second))
+	assert.False(t, ok)
+}
+
+func TestParticipationCacheScanOutlivesCancelledCaller(t *testing.T) {
+	var cache participationCache
+	stats := map[string]map[string]ParticipationStats{"cosmos1alice": {"crypto": {Claims: 1}}}
+	started := make(chan struct{})
+	var startOnce sync.Once
+	release := make(chan struct{})
+	scan := func(ctx context.Context, now time.Time) (map[string]map[string]ParticipationStats, error) {
+		startOnce.Do(func() { close(started) })
+		<-release
+		return stats, ctx.Err()
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := cache.load(cancelled, scan)
+		first <- err
+	}()
+	<-started
+	second := make(chan map[string]map[string]ParticipationStats, 1)
+	go func() {
+		loaded, err := cache.load(context.Background(), scan)
+		assert.NoError(t, err)
+		second <- loaded
+	}()
+
+	// the first caller gives up once the second one waits for its scan, which carries on
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-first)
+	close(release)
+	assert.Equal(t, stats, <-second)
+	cached, ok := cache.get(time.Now())
+	assert.True(t, ok)
+	assert.Equal(t, stats, cached)
+}